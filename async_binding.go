@@ -0,0 +1,286 @@
+package swag
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/go-asyncapi/spec-2.4.0"
+)
+
+const bindingAttrPrefix = "@binding."
+
+type bindingTarget string
+
+const (
+	channelBinding   bindingTarget = "channel"
+	operationBinding bindingTarget = "operation"
+	messageBinding   bindingTarget = "message"
+)
+
+type bindingValueKind int
+
+const (
+	bindingString bindingValueKind = iota
+	bindingInteger
+	bindingBoolean
+	bindingStringList
+	bindingTypeSchema
+)
+
+type bindingField struct {
+	target bindingTarget
+	// path is the location of the field inside the binding object, e.g. exchange.autoDelete
+	path []string
+	kind bindingValueKind
+}
+
+// asyncAPIBindings describes the binding fields supported for every protocol, keyed by the lowercase field
+// name used in the @binding.{protocol}.{field} attribute.
+var asyncAPIBindings = map[string]map[string]bindingField{
+	"kafka": {
+		"topic":      {target: channelBinding, path: []string{"topic"}, kind: bindingString},
+		"partitions": {target: channelBinding, path: []string{"partitions"}, kind: bindingInteger},
+		"replicas":   {target: channelBinding, path: []string{"replicas"}, kind: bindingInteger},
+		"groupid":    {target: operationBinding, path: []string{"groupId"}, kind: bindingString},
+		"clientid":   {target: operationBinding, path: []string{"clientId"}, kind: bindingString},
+		"key":        {target: messageBinding, path: []string{"key"}, kind: bindingTypeSchema},
+	},
+	"amqp": {
+		"is":                  {target: channelBinding, path: []string{"is"}, kind: bindingString},
+		"exchange.name":       {target: channelBinding, path: []string{"exchange", "name"}, kind: bindingString},
+		"exchange.type":       {target: channelBinding, path: []string{"exchange", "type"}, kind: bindingString},
+		"exchange.durable":    {target: channelBinding, path: []string{"exchange", "durable"}, kind: bindingBoolean},
+		"exchange.autodelete": {target: channelBinding, path: []string{"exchange", "autoDelete"}, kind: bindingBoolean},
+		"exchange.vhost":      {target: channelBinding, path: []string{"exchange", "vhost"}, kind: bindingString},
+		"queue.name":          {target: channelBinding, path: []string{"queue", "name"}, kind: bindingString},
+		"queue.durable":       {target: channelBinding, path: []string{"queue", "durable"}, kind: bindingBoolean},
+		"queue.exclusive":     {target: channelBinding, path: []string{"queue", "exclusive"}, kind: bindingBoolean},
+		"queue.autodelete":    {target: channelBinding, path: []string{"queue", "autoDelete"}, kind: bindingBoolean},
+		"queue.vhost":         {target: channelBinding, path: []string{"queue", "vhost"}, kind: bindingString},
+		"expiration":          {target: operationBinding, path: []string{"expiration"}, kind: bindingInteger},
+		"userid":              {target: operationBinding, path: []string{"userId"}, kind: bindingString},
+		"cc":                  {target: operationBinding, path: []string{"cc"}, kind: bindingStringList},
+		"priority":            {target: operationBinding, path: []string{"priority"}, kind: bindingInteger},
+		"deliverymode":        {target: operationBinding, path: []string{"deliveryMode"}, kind: bindingInteger},
+		"mandatory":           {target: operationBinding, path: []string{"mandatory"}, kind: bindingBoolean},
+		"bcc":                 {target: operationBinding, path: []string{"bcc"}, kind: bindingStringList},
+		"replyto":             {target: operationBinding, path: []string{"replyTo"}, kind: bindingString},
+		"timestamp":           {target: operationBinding, path: []string{"timestamp"}, kind: bindingBoolean},
+		"ack":                 {target: operationBinding, path: []string{"ack"}, kind: bindingBoolean},
+		"contentencoding":     {target: messageBinding, path: []string{"contentEncoding"}, kind: bindingString},
+		"messagetype":         {target: messageBinding, path: []string{"messageType"}, kind: bindingString},
+	},
+	"mqtt": {
+		"qos":    {target: operationBinding, path: []string{"qos"}, kind: bindingInteger},
+		"retain": {target: operationBinding, path: []string{"retain"}, kind: bindingBoolean},
+	},
+	"nats": {
+		"queue": {target: operationBinding, path: []string{"queue"}, kind: bindingString},
+	},
+	"ws": {
+		"method":  {target: channelBinding, path: []string{"method"}, kind: bindingString},
+		"query":   {target: channelBinding, path: []string{"query"}, kind: bindingTypeSchema},
+		"headers": {target: channelBinding, path: []string{"headers"}, kind: bindingTypeSchema},
+	},
+}
+
+// asyncAPIBindingVersions are the binding versions written for protocols whose binding object doesn't define one.
+var asyncAPIBindingVersions = map[string]string{
+	"kafka": "0.3.0",
+	"amqp":  "0.2.0",
+	"mqtt":  "0.1.0",
+	"nats":  "0.1.0",
+	"ws":    "0.1.0",
+}
+
+// asyncAPIBindingServerProtocols lists the server protocols a binding can be used with.
+var asyncAPIBindingServerProtocols = map[string][]string{
+	"kafka": {"kafka", "kafka-secure"},
+	"amqp":  {"amqp", "amqps"},
+	"mqtt":  {"mqtt", "secure-mqtt"},
+	"nats":  {"nats"},
+	"ws":    {"ws", "wss"},
+}
+
+// IsAsyncAPIBindingCompatible reports whether a binding of the given protocol can be used on a server with the given protocol.
+func IsAsyncAPIBindingCompatible(bindingProtocol, serverProtocol string) bool {
+	return findInSlice(asyncAPIBindingServerProtocols[bindingProtocol], strings.ToLower(serverProtocol))
+}
+
+// @binding.{protocol}.{field} {value}
+func (asyncScope *AsyncScope) ParseBindingComment(attribute string, commentLine string, astFile *ast.File) error {
+	protocol, fieldName, _ := strings.Cut(strings.TrimPrefix(attribute, bindingAttrPrefix), ".")
+
+	fields, ok := asyncAPIBindings[protocol]
+	if !ok {
+		return fmt.Errorf("unsupported binding protocol '%s', supported protocols are kafka, amqp, mqtt, nats and ws", protocol)
+	}
+
+	field, ok := fields[fieldName]
+	if !ok {
+		return fmt.Errorf("unknown %s binding field '%s'", protocol, fieldName)
+	}
+
+	value, err := asyncScope.parseBindingValue(field.kind, strings.TrimSpace(commentLine), astFile)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", attribute, err)
+	}
+
+	var bindings interface{}
+
+	switch field.target {
+	case channelBinding:
+		if asyncScope.currentChannel == nil {
+			return fmt.Errorf("%s must follow a %s", attribute, channelAttr)
+		}
+
+		if asyncScope.currentChannel.Bindings == nil {
+			asyncScope.currentChannel.Bindings = &spec.ChannelBindingsObject{}
+		}
+		bindings = asyncScope.currentChannel.Bindings
+	case operationBinding:
+		if asyncScope.currentOperation == nil {
+			return fmt.Errorf("%s must follow an %s", attribute, operationAttr)
+		}
+
+		if asyncScope.currentOperation.Bindings == nil {
+			asyncScope.currentOperation.Bindings = &spec.OperationBindingsObject{}
+		}
+		bindings = asyncScope.currentOperation.Bindings
+	case messageBinding:
+		message, err := asyncScope.getCurrentMessage(Attribute(attribute))
+		if err != nil {
+			return err
+		}
+
+		if message.Bindings == nil {
+			message.Bindings = &spec.MessageBindingsObject{}
+		}
+		bindings = message.Bindings
+	}
+
+	if err := setBindingField(bindings, protocol, field.path, value); err != nil {
+		return fmt.Errorf("invalid %s %s binding: %w", protocol, field.target, err)
+	}
+
+	return nil
+}
+
+func (asyncScope *AsyncScope) parseBindingValue(kind bindingValueKind, value string, astFile *ast.File) (interface{}, error) {
+	if value == "" {
+		return nil, fmt.Errorf("missing value")
+	}
+
+	switch kind {
+	case bindingInteger:
+		return strconv.ParseInt(value, 10, 64)
+	case bindingBoolean:
+		return strconv.ParseBool(value)
+	case bindingStringList:
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			values = append(values, strings.TrimSpace(item))
+		}
+		return values, nil
+	case bindingTypeSchema:
+		typeSchema, err := asyncScope.parser.getTypeSchema(value, astFile, true, true)
+		if err != nil {
+			return nil, err
+		}
+		return CreateAsyncAPISchema(typeSchema)
+	}
+
+	return value, nil
+}
+
+// setBindingField sets a field in the binding object of a protocol. The bindings object is updated through its JSON
+// representation, so the validation of the AsyncAPI binding types (e.g. enumerations) applies to the new value.
+func setBindingField(bindings interface{}, protocol string, path []string, value interface{}) error {
+	data, err := json.Marshal(bindings)
+	if err != nil {
+		return err
+	}
+
+	var bindingsMap map[string]interface{}
+	if err := json.Unmarshal(data, &bindingsMap); err != nil {
+		return err
+	}
+
+	binding, _ := bindingsMap[protocol].(map[string]interface{})
+	if binding == nil {
+		binding = make(map[string]interface{})
+		bindingsMap[protocol] = binding
+	}
+
+	if _, ok := binding["bindingVersion"]; !ok {
+		binding["bindingVersion"] = asyncAPIBindingVersions[protocol]
+	}
+
+	object := binding
+	for _, key := range path[:len(path)-1] {
+		child, _ := object[key].(map[string]interface{})
+		if child == nil {
+			child = make(map[string]interface{})
+			object[key] = child
+		}
+		object = child
+	}
+	object[path[len(path)-1]] = value
+
+	// AMQP channels are either a routing key on an exchange or a queue.
+	if protocol == "amqp" && len(path) > 1 && binding["is"] == nil {
+		binding["is"] = map[string]string{"exchange": "routingKey", "queue": "queue"}[path[0]]
+	}
+
+	channelBindings, isChannel := bindings.(*spec.ChannelBindingsObject)
+	amqpChannel, hasAMQPChannel := bindingsMap["amqp"]
+	if isChannel && hasAMQPChannel {
+		delete(bindingsMap, "amqp")
+	}
+
+	data, err = json.Marshal(bindingsMap)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, bindings); err != nil {
+		return err
+	}
+
+	if isChannel && hasAMQPChannel {
+		return setAMQPChannelBinding(channelBindings, amqpChannel)
+	}
+
+	return nil
+}
+
+// amqpChannelFields holds the fields of an AMQP channel binding. spec.AmqpChannel can't be decoded from JSON
+// because its generated oneOf constraints never validate, so the fields are decoded through this type.
+type amqpChannelFields struct {
+	Is       spec.AmqpChannelIs        `json:"is"`
+	Exchange *spec.AmqpChannelExchange `json:"exchange"`
+	Queue    *spec.AmqpChannelQueue    `json:"queue"`
+}
+
+func setAMQPChannelBinding(bindings *spec.ChannelBindingsObject, binding interface{}) error {
+	data, err := json.Marshal(binding)
+	if err != nil {
+		return err
+	}
+
+	var fields amqpChannelFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	bindings.Amqp = &spec.AmqpChannel{
+		Is:       fields.Is,
+		Exchange: fields.Exchange,
+		Queue:    fields.Queue,
+	}
+
+	return nil
+}
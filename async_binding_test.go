@@ -0,0 +1,161 @@
+package swag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBindingComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		comments    []string
+		expectedErr string
+		expected    string
+	}{
+		{
+			name: "kafka channel, operation and message bindings",
+			comments: []string{
+				`@channel orders kafkaServer "Orders"`,
+				"@binding.kafka.partitions 12",
+				"@binding.kafka.replicas 3",
+				"@operation orderCreated send orders model.OrderRow",
+				"@binding.kafka.groupId orders-service",
+				"@binding.kafka.key string",
+			},
+			expected: `{
+				"channel": {"kafka": {"bindingVersion": "0.3.0", "partitions": 12, "replicas": 3}},
+				"operation": {"kafka": {"bindingVersion": "0.3.0", "groupId": "orders-service"}},
+				"message": {"kafka": {"bindingVersion": "0.3.0", "key": {"type": "string"}}}
+			}`,
+		},
+		{
+			name: "kafka key referencing a type",
+			comments: []string{
+				"@operation orderCreated send orders model.OrderRow",
+				"@binding.kafka.key model.OrderKey",
+			},
+			expected: `{
+				"message": {"kafka": {"bindingVersion": "0.3.0", "key": {"$ref": "#/components/schemas/model.OrderKey"}}}
+			}`,
+		},
+		{
+			name: "amqp bindings",
+			comments: []string{
+				`@channel orders rabbit "Orders"`,
+				"@binding.amqp.exchange.name orders",
+				"@binding.amqp.exchange.type topic",
+				"@binding.amqp.exchange.durable true",
+				"@operation orderCreated send orders model.OrderRow",
+				"@binding.amqp.deliveryMode 2",
+				"@binding.amqp.cc orders.created, orders.all",
+				"@binding.amqp.contentEncoding gzip",
+			},
+			expected: `{
+				"channel": {"amqp": {"bindingVersion": "0.2.0", "is": "routingKey", "exchange": {"name": "orders", "type": "topic", "durable": true}}},
+				"operation": {"amqp": {"bindingVersion": "0.2.0", "deliveryMode": 2, "cc": ["orders.created", "orders.all"]}},
+				"message": {"amqp": {"bindingVersion": "0.2.0", "contentEncoding": "gzip"}}
+			}`,
+		},
+		{
+			name: "mqtt, nats and ws bindings",
+			comments: []string{
+				`@channel orders ws "Orders"`,
+				"@binding.ws.method GET",
+				"@operation orderCreated send orders model.OrderRow",
+				"@binding.mqtt.qos 1",
+				"@binding.mqtt.retain true",
+				"@binding.nats.queue workers",
+			},
+			expected: `{
+				"channel": {"ws": {"bindingVersion": "0.1.0", "method": "GET"}},
+				"operation": {
+					"mqtt": {"bindingVersion": "0.1.0", "qos": 1, "retain": true},
+					"nats": {"bindingVersion": "0.1.0", "queue": "workers"}
+				}
+			}`,
+		},
+		{
+			name:        "returns error for an unsupported protocol",
+			comments:    []string{"@binding.sqs.queue orders"},
+			expectedErr: "unsupported binding protocol 'sqs', supported protocols are kafka, amqp, mqtt, nats and ws",
+		},
+		{
+			name:        "returns error for an unknown field",
+			comments:    []string{"@binding.kafka.retention 1d"},
+			expectedErr: "unknown kafka binding field 'retention'",
+		},
+		{
+			name:        "returns error for a channel binding without @channel",
+			comments:    []string{"@binding.kafka.partitions 3"},
+			expectedErr: "@binding.kafka.partitions must follow a @channel",
+		},
+		{
+			name: "returns error for an invalid value",
+			comments: []string{
+				`@channel orders kafkaServer "Orders"`,
+				"@binding.kafka.partitions many",
+			},
+			expectedErr: `invalid value for @binding.kafka.partitions: strconv.ParseInt: parsing "many": invalid syntax`,
+		},
+		{
+			name: "returns error for a value rejected by the binding",
+			comments: []string{
+				`@channel orders rabbit "Orders"`,
+				"@binding.amqp.exchange.type broadcast",
+			},
+			expectedErr: "invalid amqp channel binding",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asyncScope := NewAsyncScope(nil)
+			asyncScope.parser.addTestType("model.OrderRow")
+			asyncScope.parser.addTestType("model.OrderKey")
+
+			var err error
+			for _, comment := range tt.comments {
+				if err = asyncScope.ParseAsyncAPIComment(nil, comment, nil); err != nil {
+					break
+				}
+			}
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			bindings := make(map[string]interface{})
+			if asyncScope.currentChannel != nil && asyncScope.currentChannel.Bindings != nil {
+				bindings["channel"] = asyncScope.currentChannel.Bindings
+			}
+			if asyncScope.currentOperation != nil && asyncScope.currentOperation.Bindings != nil {
+				bindings["operation"] = asyncScope.currentOperation.Bindings
+			}
+			if asyncScope.currentMessage != nil && asyncScope.currentMessage.Bindings != nil {
+				bindings["message"] = asyncScope.currentMessage.Bindings
+			}
+
+			out, err := json.Marshal(bindings)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestIsAsyncAPIBindingCompatible(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsAsyncAPIBindingCompatible("kafka", "kafka-secure"))
+	assert.True(t, IsAsyncAPIBindingCompatible("mqtt", "MQTT"))
+	assert.False(t, IsAsyncAPIBindingCompatible("amqp", "kafka"))
+	assert.False(t, IsAsyncAPIBindingCompatible("sqs", "sqs"))
+}
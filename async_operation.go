@@ -1,11 +1,11 @@
 package swag
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"log"
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -25,24 +25,75 @@ const (
 	serverAttr    Attribute = "@server"
 	channelAttr   Attribute = "@channel"
 	operationAttr Attribute = "@operation"
-)
 
-const (
-	openAPISchemaPrefix  = "#/definitions/"
-	asyncAPISchemaPrefix = "#/components/schemas/"
+	serverVariableAttr        Attribute = "@server.variable"
+	serverDescriptionAttr     Attribute = "@server.description"
+	serverProtocolVersionAttr Attribute = "@server.protocolversion"
+	serverSecurityAttr        Attribute = "@server.security"
+
+	channelParamAttr Attribute = "@channel.param"
+
+	messageNameAttr          Attribute = "@message.name"
+	messageTitleAttr         Attribute = "@message.title"
+	messageSummaryAttr       Attribute = "@message.summary"
+	messageDescriptionAttr   Attribute = "@message.description"
+	messageContentTypeAttr   Attribute = "@message.contenttype"
+	messageHeadersAttr       Attribute = "@message.headers"
+	messageCorrelationIDAttr Attribute = "@message.correlationid"
 )
 
+const asyncAPIMessagePrefix = "#/components/messages/"
+
 type AsyncScope struct {
 	parser     *Parser
 	servers    map[string]*spec.ServersAdditionalProperties
 	channels   map[string]*spec.ChannelItem
 	operations map[string]*OperationWithChannel
+	messages   map[string]*spec.MessageEntity
+
+	// currentServer is the server created by the last @server, @server.* attributes are applied to it
+	currentServer *spec.Server
+	// currentChannel and currentOperation are the last channel and operation of the scope, bindings are applied to them
+	currentChannel   *spec.ChannelItem
+	currentOperation *spec.Operation
+	// currentMessage holds the @message.* attributes of the message of the last @operation
+	currentMessage *spec.MessageEntity
+	// messageAttributes are the @message.* attributes of the operations of the scope, they are applied to the
+	// shared messages by applyMessageAttributes
+	messageAttributes []messageAttributes
+
+	// position is the position of the attribute being parsed by parseAsyncAPICommentAt
+	position token.Position
+	// channelPositions holds the positions of the @channel attributes by channel name
+	channelPositions map[string]token.Position
+}
+
+// messageAttributes are the @message.* attributes given by one operation for a message, which may be shared with
+// other operations.
+type messageAttributes struct {
+	message    *spec.MessageEntity
+	attributes *spec.MessageEntity
+	// position is the one of the @operation giving the attributes
+	position token.Position
 }
 
 type OperationWithChannel struct {
 	action  OperationAction
 	channel string
 	spec.Operation
+
+	// position is the one of the @operation attribute
+	position token.Position
+}
+
+// Action returns whether the application sends or receives messages with this operation.
+func (operation *OperationWithChannel) Action() OperationAction {
+	return operation.action
+}
+
+// Channel returns the name of the channel the operation is bound to.
+func (operation *OperationWithChannel) Channel() string {
+	return operation.channel
 }
 
 // NewAsyncOperation creates a new AsyncOperation with default properties.
@@ -56,6 +107,9 @@ func NewAsyncScope(parser *Parser) *AsyncScope {
 		servers:    make(map[string]*spec.ServersAdditionalProperties),
 		channels:   make(map[string]*spec.ChannelItem),
 		operations: make(map[string]*OperationWithChannel),
+		messages:   make(map[string]*spec.MessageEntity),
+
+		channelPositions: make(map[string]token.Position),
 	}
 
 	return asyncOperation
@@ -63,9 +117,21 @@ func NewAsyncScope(parser *Parser) *AsyncScope {
 
 // AttributeHandler is a map of attribute to the function that handles the attribute.
 var AttributeHandler = map[Attribute]func(*AsyncScope, *string, string, *ast.File) error{
-	serverAttr:    (*AsyncScope).ParseServerComment,
-	channelAttr:   (*AsyncScope).ParseChannelComment,
-	operationAttr: (*AsyncScope).ParseOperationComment,
+	serverAttr:                (*AsyncScope).ParseServerComment,
+	channelAttr:               (*AsyncScope).ParseChannelComment,
+	operationAttr:             (*AsyncScope).ParseOperationComment,
+	serverVariableAttr:        (*AsyncScope).ParseServerVariableComment,
+	serverDescriptionAttr:     (*AsyncScope).ParseServerDescriptionComment,
+	serverProtocolVersionAttr: (*AsyncScope).ParseServerProtocolVersionComment,
+	serverSecurityAttr:        (*AsyncScope).ParseServerSecurityComment,
+	channelParamAttr:          (*AsyncScope).ParseChannelParamComment,
+	messageNameAttr:           (*AsyncScope).ParseMessageNameComment,
+	messageTitleAttr:          (*AsyncScope).ParseMessageTitleComment,
+	messageSummaryAttr:        (*AsyncScope).ParseMessageSummaryComment,
+	messageDescriptionAttr:    (*AsyncScope).ParseMessageDescriptionComment,
+	messageContentTypeAttr:    (*AsyncScope).ParseMessageContentTypeComment,
+	messageHeadersAttr:        (*AsyncScope).ParseMessageHeadersComment,
+	messageCorrelationIDAttr:  (*AsyncScope).ParseMessageCorrelationIDComment,
 }
 
 // ParseAsyncAPIComment parses the comment line and sets the AsyncAPI properties.
@@ -84,6 +150,10 @@ func (asyncScope *AsyncScope) ParseAsyncAPIComment(funcName *string, comment str
 		lineRemainder = fields[1]
 	}
 
+	if strings.HasPrefix(lowerAttribute, bindingAttrPrefix) {
+		return asyncScope.ParseBindingComment(lowerAttribute, lineRemainder, astFile)
+	}
+
 	handler, exists := AttributeHandler[Attribute(lowerAttribute)]
 	if !exists {
 		log.Printf("unknown attribute '%s' in comment '%s', skipping...", attribute, comment)
@@ -93,6 +163,14 @@ func (asyncScope *AsyncScope) ParseAsyncAPIComment(funcName *string, comment str
 	return handler(asyncScope, funcName, lineRemainder, astFile)
 }
 
+// parseAsyncAPICommentAt parses a comment like ParseAsyncAPIComment, the position of its attribute is recorded to
+// report the problems found in the scope.
+func (asyncScope *AsyncScope) parseAsyncAPICommentAt(funcName *string, comment string, astFile *ast.File, position token.Position) error {
+	asyncScope.position = position
+
+	return asyncScope.ParseAsyncAPIComment(funcName, comment, astFile)
+}
+
 var serverCommentPattern = regexp.MustCompile(`(\S+)\s+(\S+)\s+(\S+)`)
 
 // @server {name} {protocol} {host}
@@ -106,13 +184,101 @@ func (asyncScope *AsyncScope) ParseServerComment(funcName *string, commentLine s
 	protocol := matches[2]
 	host := matches[3]
 
-	asyncScope.servers[serverName] = &spec.ServersAdditionalProperties{
-		Server: &spec.Server{
-			URL:      host,
-			Protocol: protocol,
-		},
+	server := &spec.Server{
+		URL:      host,
+		Protocol: protocol,
 	}
 
+	asyncScope.servers[serverName] = &spec.ServersAdditionalProperties{Server: server}
+	asyncScope.currentServer = server
+
+	return nil
+}
+
+// Returns the server created by the preceding @server.
+func (asyncScope *AsyncScope) getCurrentServer(attribute Attribute) (*spec.Server, error) {
+	if asyncScope.currentServer == nil {
+		return nil, fmt.Errorf("%s must follow a %s", attribute, serverAttr)
+	}
+	return asyncScope.currentServer, nil
+}
+
+var serverVariableCommentPattern = regexp.MustCompile(`^(\S+)\s+(\S+)(?:\s+(?i:enums)\(([^)]*)\))?(?:\s+"([^"]*)")?$`)
+
+// @server.variable {name} {default} Enums({value1},{value2}) "{description}"
+func (asyncScope *AsyncScope) ParseServerVariableComment(funcName *string, commentLine string, astFile *ast.File) error {
+	server, err := asyncScope.getCurrentServer(serverVariableAttr)
+	if err != nil {
+		return err
+	}
+
+	matches := serverVariableCommentPattern.FindStringSubmatch(strings.TrimSpace(commentLine))
+	if len(matches) < 5 {
+		return fmt.Errorf("missing required param comment parameters \"%s\"", commentLine)
+	}
+
+	variable := spec.ServerVariable{
+		Default:     matches[2],
+		Description: matches[4],
+	}
+
+	if matches[3] != "" {
+		for _, value := range strings.Split(matches[3], ",") {
+			variable.Enum = append(variable.Enum, strings.TrimSpace(value))
+		}
+
+		if !findInSlice(variable.Enum, variable.Default) {
+			return fmt.Errorf("default value '%s' of server variable '%s' is not one of %v", variable.Default, matches[1], variable.Enum)
+		}
+	}
+
+	if server.Variables == nil {
+		server.Variables = make(map[string]spec.ServerVariable)
+	}
+
+	server.Variables[matches[1]] = variable
+	return nil
+}
+
+// @server.description {description}, repeated lines are joined with a new line
+func (asyncScope *AsyncScope) ParseServerDescriptionComment(funcName *string, commentLine string, astFile *ast.File) error {
+	server, err := asyncScope.getCurrentServer(serverDescriptionAttr)
+	if err != nil {
+		return err
+	}
+
+	if server.Description == "" {
+		server.Description = commentLine
+		return nil
+	}
+
+	server.Description += "\n" + commentLine
+	return nil
+}
+
+// @server.protocolversion {version}
+func (asyncScope *AsyncScope) ParseServerProtocolVersionComment(funcName *string, commentLine string, astFile *ast.File) error {
+	server, err := asyncScope.getCurrentServer(serverProtocolVersionAttr)
+	if err != nil {
+		return err
+	}
+
+	server.ProtocolVersion = strings.TrimSpace(commentLine)
+	return nil
+}
+
+// @server.security {scheme}[{scope1},{scope2}] && {scheme}, repeated lines are alternative requirements
+func (asyncScope *AsyncScope) ParseServerSecurityComment(funcName *string, commentLine string, astFile *ast.File) error {
+	server, err := asyncScope.getCurrentServer(serverSecurityAttr)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(commentLine) == "" {
+		return fmt.Errorf("missing required param comment parameters \"%s\"", commentLine)
+	}
+
+	server.Security = append(server.Security, parseSecurity(commentLine))
 	return nil
 }
 
@@ -129,20 +295,71 @@ func (asyncScope *AsyncScope) ParseChannelComment(funcName *string, commentLine
 	server := matches[2]
 	description := matches[3]
 
-	asyncScope.channels[channelName] = &spec.ChannelItem{
+	channel := &spec.ChannelItem{
 		Servers:     []string{server},
 		Description: description,
 	}
 
+	asyncScope.channels[channelName] = channel
+	asyncScope.channelPositions[channelName] = asyncScope.position
+	asyncScope.currentChannel = channel
+
 	return nil
 }
 
-var operationCommentPattern = regexp.MustCompile(`(\S+)\s+(\S+)\s+(\S+)\s*(.*)?`)
+var channelParamCommentPattern = regexp.MustCompile(`^([A-Za-z0-9_\-]+)\s+(\S+)(?:\s+"([^"]*)")?$`)
+
+// @channel.param {name} {type} "{description}"
+func (asyncScope *AsyncScope) ParseChannelParamComment(funcName *string, commentLine string, astFile *ast.File) error {
+	if asyncScope.currentChannel == nil {
+		return fmt.Errorf("%s must follow a %s", channelParamAttr, channelAttr)
+	}
+
+	matches := channelParamCommentPattern.FindStringSubmatch(strings.TrimSpace(commentLine))
+	if len(matches) < 4 {
+		return fmt.Errorf("missing required param comment parameters \"%s\"", commentLine)
+	}
+
+	paramName := matches[1]
+	paramType := matches[2]
+
+	typeSchema, err := asyncScope.parser.getTypeSchema(paramType, astFile, false, true)
+	if err != nil {
+		return err
+	}
+
+	if len(typeSchema.Type) == 0 || !IsSimplePrimitiveType(typeSchema.Type[0]) {
+		return fmt.Errorf("channel parameter '%s' must be a primitive type, got '%s'", paramName, paramType)
+	}
 
-// @operation {operationID} {action} {channel} {message}
-// @operation {action} {channel} {message}
+	schema, err := CreateAsyncAPISchema(typeSchema)
+	if err != nil {
+		return err
+	}
+
+	if asyncScope.currentChannel.Parameters == nil {
+		asyncScope.currentChannel.Parameters = make(map[string]spec.Parameter)
+	}
+
+	asyncScope.currentChannel.Parameters[paramName] = spec.Parameter{
+		Description: matches[3],
+		Schema:      schema,
+	}
+
+	return nil
+}
+
+var (
+	operationCommentPattern = regexp.MustCompile(`(\S+)\s+(\S+)\s+(\S+)\s*(.*)?`)
+	messageListPattern      = regexp.MustCompile(`\s*,\s*`)
+)
+
+// @operation {operationID} {action} {channel} {message}[,{message}...]
+// @operation {action} {channel} {message}[,{message}...]
+//
+// Several messages are described as a oneOf list, @message.* attributes are applied to the last one.
 func (asyncScope *AsyncScope) ParseOperationComment(funcName *string, commentLine string, astFile *ast.File) error {
-	matches, err := asyncScope.validateOperationCommentLine(commentLine)
+	matches, err := asyncScope.validateOperationCommentLine(messageListPattern.ReplaceAllString(commentLine, ","))
 	if err != nil {
 		return err
 	}
@@ -158,21 +375,96 @@ func (asyncScope *AsyncScope) ParseOperationComment(funcName *string, commentLin
 	}
 
 	channel := matches[argsStartIndex+1]
-	message := matches[argsStartIndex+2]
 
-	typeSchema, err := asyncScope.parser.getTypeSchema(message, astFile, false, true)
-	if err != nil {
-		log.Printf("unable to get type schema for message type '%s': %v", message, err)
-		return err
+	var messages []spec.Message
+	var messageTypes []string
+
+	for _, message := range strings.Split(matches[argsStartIndex+2], ",") {
+		if message == "" || findInSlice(messageTypes, message) {
+			continue
+		}
+
+		messageTypes = append(messageTypes, message)
+
+		typeSchema, err := asyncScope.parser.getTypeSchema(message, astFile, true, true)
+		if err != nil {
+			log.Printf("unable to get type schema for message type '%s': %v", message, err)
+			return err
+		}
+
+		msg, err := asyncScope.createMessage(typeSchema, formatMessageID(message))
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, msg)
 	}
 
-	msg, err := asyncScope.createMessage(typeSchema, formatMessageID(message))
-	if err != nil {
-		return err
+	if len(messages) == 0 {
+		return fmt.Errorf("missing required comment parameters: \"%s\"", commentLine)
 	}
 
-	asyncScope.addOperation(operationID, operationAction, channel, msg)
-	return nil
+	return asyncScope.addOperation(operationID, operationAction, channel, newMessageList(messages))
+}
+
+// AsyncAPIMessages returns the messages of an operation message, which is either a single message or a oneOf list.
+func AsyncAPIMessages(message *spec.Message) []spec.Message {
+	if message == nil {
+		return nil
+	}
+
+	if message.OneOf1 != nil && message.OneOf1.OneOf0 != nil {
+		return message.OneOf1.OneOf0.OneOf
+	}
+
+	return []spec.Message{*message}
+}
+
+// Returns the operation message describing the given messages, a oneOf list is only used for several messages.
+func newMessageList(messages []spec.Message) spec.Message {
+	if len(messages) == 1 {
+		return messages[0]
+	}
+
+	msg := spec.Message{}
+	msg.OneOf1Ens().WithOneOf0(spec.MessageOneOf1OneOf0{OneOf: messages})
+
+	return msg
+}
+
+// mergeOperations returns an operation whose messages are the messages of both operations, so a channel can carry
+// several message types with the same action. The other fields are kept from the existing operation, the
+// given operations are not modified.
+func mergeOperations(existing *spec.Operation, operation *spec.Operation) *spec.Operation {
+	if existing == nil {
+		return operation
+	}
+
+	messages := AsyncAPIMessages(existing.Message)
+	for _, message := range AsyncAPIMessages(operation.Message) {
+		if !hasMessage(messages, message) {
+			messages = append(messages, message)
+		}
+	}
+
+	merged := *existing
+	msg := newMessageList(messages)
+	merged.Message = &msg
+
+	if merged.Bindings == nil {
+		merged.Bindings = operation.Bindings
+	}
+
+	return &merged
+}
+
+func hasMessage(messages []spec.Message, message spec.Message) bool {
+	for _, item := range messages {
+		if reflect.DeepEqual(item, message) {
+			return true
+		}
+	}
+	return false
 }
 
 // Validates the comment line and ensures it matches the required pattern.
@@ -203,66 +495,280 @@ func (asyncScope *AsyncScope) validateOperationAction(action string, commentLine
 	return "", fmt.Errorf("invalid operation action '%s' in comment line '%s'. Valid values are 'send' or 'receive'", action, commentLine)
 }
 
-// Creates a message based on the type schema and message ID.
+// Creates a message based on the type schema and message ID. The message is registered in the components
+// of the scope and the returned message references it, so a type used by several operations is described once.
 func (asyncScope *AsyncScope) createMessage(typeSchema *typeSpec.Schema, messageID string) (spec.Message, error) {
 	msg := spec.Message{}
 
-	payload := map[string]interface{}{"type": typeSchema.Type[0]}
-	if typeSchema.Type[0] == OBJECT {
-		properties, err := GetAsyncAPISchemaProperties(typeSchema)
-		if err != nil {
-			return msg, err
+	payload, err := CreateAsyncAPISchema(typeSchema)
+	if err != nil {
+		return msg, err
+	}
+
+	entity, err := asyncScope.findMessage(messageID, payload)
+	if err != nil {
+		return msg, err
+	}
+
+	if entity == nil {
+		entity = &spec.MessageEntity{
+			MessageID: messageID,
+			Payload:   payload,
 		}
-		payload["properties"] = properties
+		asyncScope.messages[messageID] = entity
 	}
 
-	msg.OneOf1Ens().WithMessageEntity(spec.MessageEntity{
-		MessageID: messageID,
-		Payload:   payload,
+	asyncScope.currentMessage = &spec.MessageEntity{MessageID: messageID}
+	asyncScope.messageAttributes = append(asyncScope.messageAttributes, messageAttributes{
+		message:    entity,
+		attributes: asyncScope.currentMessage,
+		position:   asyncScope.position,
 	})
+	msg.WithReference(spec.Reference{Ref: asyncAPIMessagePrefix + messageID})
 
 	return msg, nil
 }
 
-// formatMessageID extracts the message type from the package name (e.g., "package.MessageType" -> "MessageType").
-func formatMessageID(messageID string) string {
-	if i := strings.LastIndex(messageID, "."); i != -1 {
-		return messageID[i+1:]
+// Returns the message already registered with the given ID, either in the scope or in the parsed AsyncAPI components.
+func (asyncScope *AsyncScope) findMessage(messageID string, payload map[string]interface{}) (*spec.MessageEntity, error) {
+	entity, ok := asyncScope.messages[messageID]
+	if !ok {
+		message, exists := asyncScope.parser.asyncAPI.Components.Messages[messageID]
+		if !exists || message.OneOf1 == nil || message.OneOf1.MessageEntity == nil {
+			return nil, nil
+		}
+		entity = message.OneOf1.MessageEntity
 	}
-	return messageID
+
+	if !reflect.DeepEqual(entity.Payload, payload) {
+		return nil, fmt.Errorf("message '%s' is already defined with a different payload", messageID)
+	}
+
+	asyncScope.messages[messageID] = entity
+	return entity, nil
+}
+
+// applyMessageAttributes applies the @message.* attributes of the operations to their messages. A message shared by
+// several operations gets the attributes of all of them, giving it another value for an attribute is an error,
+// located at the @operation giving it.
+func (asyncScope *AsyncScope) applyMessageAttributes() error {
+	for _, item := range asyncScope.messageAttributes {
+		if err := mergeMessageAttributes(item.message, item.attributes); err != nil {
+			return &ParseError{Pos: item.position, Err: err}
+		}
+	}
+
+	asyncScope.messageAttributes = nil
+	return nil
+}
+
+// mergeMessageAttributes copies the attributes set on one operation to the message.
+func mergeMessageAttributes(message, attributes *spec.MessageEntity) error {
+	fields := []struct {
+		name          string
+		target, value *string
+	}{
+		{"name", &message.Name, &attributes.Name},
+		{"title", &message.Title, &attributes.Title},
+		{"summary", &message.Summary, &attributes.Summary},
+		{"description", &message.Description, &attributes.Description},
+		{"content type", &message.ContentType, &attributes.ContentType},
+	}
+
+	for _, field := range fields {
+		if *field.value == "" {
+			continue
+		}
+
+		if *field.target != "" && *field.target != *field.value {
+			return fmt.Errorf("message '%s' is already defined with a different %s", message.MessageID, field.name)
+		}
+
+		*field.target = *field.value
+	}
+
+	if attributes.Headers != nil {
+		if message.Headers != nil && !reflect.DeepEqual(message.Headers, attributes.Headers) {
+			return fmt.Errorf("message '%s' is already defined with different headers", message.MessageID)
+		}
+
+		message.Headers = attributes.Headers
+	}
+
+	if attributes.CorrelationID != nil {
+		if message.CorrelationID != nil && !reflect.DeepEqual(message.CorrelationID, attributes.CorrelationID) {
+			return fmt.Errorf("message '%s' is already defined with a different correlation ID", message.MessageID)
+		}
+
+		message.CorrelationID = attributes.CorrelationID
+	}
+
+	if attributes.Bindings != nil {
+		if message.Bindings != nil && !reflect.DeepEqual(message.Bindings, attributes.Bindings) {
+			return fmt.Errorf("message '%s' is already defined with different bindings", message.MessageID)
+		}
+
+		message.Bindings = attributes.Bindings
+	}
+
+	return nil
+}
+
+// Returns the attributes of the message created by the preceding @operation.
+func (asyncScope *AsyncScope) getCurrentMessage(attribute Attribute) (*spec.MessageEntity, error) {
+	if asyncScope.currentMessage == nil {
+		return nil, fmt.Errorf("%s must follow an %s", attribute, operationAttr)
+	}
+	return asyncScope.currentMessage, nil
+}
+
+// @message.name {name}
+func (asyncScope *AsyncScope) ParseMessageNameComment(funcName *string, commentLine string, astFile *ast.File) error {
+	message, err := asyncScope.getCurrentMessage(messageNameAttr)
+	if err != nil {
+		return err
+	}
+
+	message.Name = commentLine
+	return nil
+}
+
+// @message.title {title}
+func (asyncScope *AsyncScope) ParseMessageTitleComment(funcName *string, commentLine string, astFile *ast.File) error {
+	message, err := asyncScope.getCurrentMessage(messageTitleAttr)
+	if err != nil {
+		return err
+	}
+
+	message.Title = commentLine
+	return nil
+}
+
+// @message.summary {summary}
+func (asyncScope *AsyncScope) ParseMessageSummaryComment(funcName *string, commentLine string, astFile *ast.File) error {
+	message, err := asyncScope.getCurrentMessage(messageSummaryAttr)
+	if err != nil {
+		return err
+	}
+
+	message.Summary = commentLine
+	return nil
 }
 
-// Marshals and processes asyncAPI type schema properties.
-func GetAsyncAPISchemaProperties(typeSchema *typeSpec.Schema) (map[string]interface{}, error) {
-	jsonData, err := typeSchema.Properties.MarshalJSON()
+// @message.description {description}, repeated lines are joined with a new line
+func (asyncScope *AsyncScope) ParseMessageDescriptionComment(funcName *string, commentLine string, astFile *ast.File) error {
+	message, err := asyncScope.getCurrentMessage(messageDescriptionAttr)
+	if err != nil {
+		return err
+	}
+
+	if message.Description == "" {
+		message.Description = commentLine
+		return nil
+	}
+
+	message.Description += "\n" + commentLine
+	return nil
+}
+
+// @message.contenttype {mime type or alias}
+func (asyncScope *AsyncScope) ParseMessageContentTypeComment(funcName *string, commentLine string, astFile *ast.File) error {
+	message, err := asyncScope.getCurrentMessage(messageContentTypeAttr)
+	if err != nil {
+		return err
+	}
+
+	var contentTypes []string
+	if err := parseMimeTypeList(strings.TrimSpace(commentLine), &contentTypes, "%v content type can't be accepted"); err != nil {
+		return err
+	}
+
+	if len(contentTypes) != 1 {
+		return fmt.Errorf("%s accepts a single content type, got \"%s\"", messageContentTypeAttr, commentLine)
+	}
+
+	message.ContentType = contentTypes[0]
+	return nil
+}
+
+// @message.headers {Type}
+func (asyncScope *AsyncScope) ParseMessageHeadersComment(funcName *string, commentLine string, astFile *ast.File) error {
+	message, err := asyncScope.getCurrentMessage(messageHeadersAttr)
+	if err != nil {
+		return err
+	}
+
+	headersType := strings.TrimSpace(commentLine)
+	if headersType == "" {
+		return fmt.Errorf("missing required comment parameters: \"%s\"", commentLine)
+	}
+
+	typeSchema, err := asyncScope.parser.getTypeSchema(headersType, astFile, false, true)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	jsonData, _ = replaceStringInJSON(jsonData, openAPISchemaPrefix, asyncAPISchemaPrefix)
+	if len(typeSchema.Type) == 0 || typeSchema.Type[0] != OBJECT {
+		return fmt.Errorf("message headers type '%s' must be a struct", headersType)
+	}
 
-	var properties map[string]interface{}
-	if err := json.Unmarshal(jsonData, &properties); err != nil {
-		return nil, err
+	headers, err := CreateAsyncAPISchema(typeSchema)
+	if err != nil {
+		return err
 	}
 
-	return properties, nil
+	message.Headers = (&spec.MessageOneOf1OneOf1Headers{}).WithSchema(headers)
+	return nil
+}
+
+var correlationIDCommentPattern = regexp.MustCompile(`^(\$message\.(?:header|payload)#(?:/[^/\s]+)*)\s*(?:"([^"]*)")?$`)
+
+// @message.correlationid {location} "{description}"
+func (asyncScope *AsyncScope) ParseMessageCorrelationIDComment(funcName *string, commentLine string, astFile *ast.File) error {
+	message, err := asyncScope.getCurrentMessage(messageCorrelationIDAttr)
+	if err != nil {
+		return err
+	}
+
+	matches := correlationIDCommentPattern.FindStringSubmatch(strings.TrimSpace(commentLine))
+	if len(matches) < 3 {
+		return fmt.Errorf("invalid correlation ID location \"%s\", expected a runtime expression like $message.header#/correlationId", commentLine)
+	}
+
+	message.CorrelationID = (&spec.MessageOneOf1OneOf1CorrelationID{}).WithCorrelationID(spec.CorrelationID{
+		Location:    matches[1],
+		Description: matches[2],
+	})
+	return nil
+}
+
+// formatMessageID extracts the message type from the package name (e.g., "package.MessageType" -> "MessageType").
+func formatMessageID(messageID string) string {
+	if i := strings.LastIndex(messageID, "."); i != -1 {
+		return messageID[i+1:]
+	}
+	return messageID
 }
 
 // Adds an operation to the async scope.
-func (asyncScope *AsyncScope) addOperation(operationID string, action OperationAction, channel string, msg spec.Message) {
+func (asyncScope *AsyncScope) addOperation(operationID string, action OperationAction, channel string, msg spec.Message) error {
+	if previous, ok := asyncScope.operations[operationID]; ok {
+		return fmt.Errorf("duplicated AsyncAPI operation id '%s' found in '%s %s', previously declared in: '%s %s'",
+			operationID, action, channel, previous.action, previous.channel)
+	}
+
 	operation := spec.Operation{}
 	operation.WithID(operationID).WithMessage(msg)
 
-	asyncScope.operations[operationID] = &OperationWithChannel{
+	operationWithChannel := &OperationWithChannel{
 		action:    action,
 		channel:   channel,
 		Operation: operation,
+		position:  asyncScope.position,
 	}
-}
 
-// Replace all occurrences of oldValue with newValue
-func replaceStringInJSON(originalJSON []byte, oldValue, newValue string) ([]byte, error) {
-	updatedJSON := bytes.ReplaceAll(originalJSON, []byte(oldValue), []byte(newValue))
-	return updatedJSON, nil
+	asyncScope.operations[operationID] = operationWithChannel
+	asyncScope.currentOperation = &operationWithChannel.Operation
+
+	return nil
 }
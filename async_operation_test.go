@@ -1,9 +1,13 @@
 package swag
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/go-asyncapi/spec-2.4.0"
 )
 
 func TestParseEmptyAsyncComment(t *testing.T) {
@@ -70,6 +74,101 @@ func TestParseServerComment(t *testing.T) {
 	}
 }
 
+func TestParseServerAttributeComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		comments    []string
+		expectedErr string
+		assertFunc  func(t *testing.T, server *spec.Server)
+	}{
+		{
+			name: "parses server variables",
+			comments: []string{
+				"@server production kafka {env}.kafka.internal:{port}",
+				`@server.variable env prod Enums(prod, staging) "Deployment environment"`,
+				"@server.variable port 9092",
+			},
+			assertFunc: func(t *testing.T, server *spec.Server) {
+				assert.Equal(t, map[string]spec.ServerVariable{
+					"env":  {Default: "prod", Enum: []string{"prod", "staging"}, Description: "Deployment environment"},
+					"port": {Default: "9092"},
+				}, server.Variables)
+			},
+		},
+		{
+			name: "parses server description and protocol version",
+			comments: []string{
+				"@server production kafka kafka.internal:9092",
+				"@server.description Production cluster",
+				"@server.description shared by every team",
+				"@server.protocolVersion 3.5.0",
+			},
+			assertFunc: func(t *testing.T, server *spec.Server) {
+				assert.Equal(t, "Production cluster\nshared by every team", server.Description)
+				assert.Equal(t, "3.5.0", server.ProtocolVersion)
+			},
+		},
+		{
+			name: "parses server security",
+			comments: []string{
+				"@server production kafka kafka.internal:9092",
+				"@server.security scram && certs",
+				"@server.security oauth[events:read, events:write]",
+			},
+			assertFunc: func(t *testing.T, server *spec.Server) {
+				assert.Equal(t, []map[string][]string{
+					{"scram": {}, "certs": {}},
+					{"oauth": {"events:read", "events:write"}},
+				}, server.Security)
+			},
+		},
+		{
+			name:        "returns error for a server attribute without @server",
+			comments:    []string{"@server.protocolVersion 3.5.0"},
+			expectedErr: "@server.protocolversion must follow a @server",
+		},
+		{
+			name: "returns error for a default value outside the enums",
+			comments: []string{
+				"@server production kafka {env}.kafka.internal:9092",
+				"@server.variable env dev Enums(prod,staging)",
+			},
+			expectedErr: "default value 'dev' of server variable 'env' is not one of [prod staging]",
+		},
+		{
+			name: "returns error for an invalid server variable",
+			comments: []string{
+				"@server production kafka {env}.kafka.internal:9092",
+				"@server.variable env",
+			},
+			expectedErr: "missing required param comment parameters \"env\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asyncScope := NewAsyncScope(nil)
+
+			var err error
+			for _, comment := range tt.comments {
+				if err = asyncScope.ParseAsyncAPIComment(nil, comment, nil); err != nil {
+					break
+				}
+			}
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.assertFunc(t, asyncScope.servers["production"].Server)
+		})
+	}
+}
+
 func TestParseChannelComment(t *testing.T) {
 	t.Parallel()
 
@@ -112,6 +211,76 @@ func TestParseChannelComment(t *testing.T) {
 	}
 }
 
+func TestParseChannelParamComment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		comments    []string
+		expectedErr string
+		expected    string
+	}{
+		{
+			name: "parses typed parameters",
+			comments: []string{
+				`@channel orders/{tenantId}/{orderId} kafkaServer "Orders of a tenant"`,
+				`@channel.param tenantId string "Tenant identifier"`,
+				"@channel.param orderId int",
+			},
+			expected: `{
+				"tenantId": {"description": "Tenant identifier", "schema": {"type": "string"}},
+				"orderId": {"schema": {"type": "integer"}}
+			}`,
+		},
+		{
+			name:        "returns error without @channel",
+			comments:    []string{"@channel.param tenantId string"},
+			expectedErr: "@channel.param must follow a @channel",
+		},
+		{
+			name: "returns error for a non primitive type",
+			comments: []string{
+				`@channel orders/{tenantId} kafkaServer "Orders of a tenant"`,
+				"@channel.param tenantId model.OrderRow",
+			},
+			expectedErr: "channel parameter 'tenantId' must be a primitive type, got 'model.OrderRow'",
+		},
+		{
+			name: "returns error for a missing type",
+			comments: []string{
+				`@channel orders/{tenantId} kafkaServer "Orders of a tenant"`,
+				"@channel.param tenantId",
+			},
+			expectedErr: "missing required param comment parameters \"tenantId\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asyncScope := NewAsyncScope(nil)
+			asyncScope.parser.addTestType("model.OrderRow")
+
+			var err error
+			for _, comment := range tt.comments {
+				if err = asyncScope.ParseAsyncAPIComment(nil, comment, nil); err != nil {
+					break
+				}
+			}
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			out, err := json.Marshal(asyncScope.currentChannel.Parameters)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(out))
+		})
+	}
+}
+
 func TestParseOperationComment(t *testing.T) {
 	t.Parallel()
 	funcNameExample := "myOperation"
@@ -131,7 +300,10 @@ func TestParseOperationComment(t *testing.T) {
 				assert.Contains(t, asyncScope.operations, "myOperation")
 				assert.Equal(t, Send, asyncScope.operations["myOperation"].action)
 				assert.Equal(t, "topic1", asyncScope.operations["myOperation"].channel)
-				assert.Equal(t, "OrderRow", asyncScope.operations["myOperation"].Message.OneOf1.MessageEntity.MessageID)
+				assert.Equal(t, "#/components/messages/OrderRow", asyncScope.operations["myOperation"].Message.Reference.Ref)
+				require.Contains(t, asyncScope.messages, "OrderRow")
+				assert.Equal(t, "OrderRow", asyncScope.messages["OrderRow"].MessageID)
+				assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/model.OrderRow"}, asyncScope.messages["OrderRow"].Payload)
 			},
 		},
 		{
@@ -143,7 +315,10 @@ func TestParseOperationComment(t *testing.T) {
 				assert.Contains(t, asyncScope.operations, "myOperation")
 				assert.Equal(t, Send, asyncScope.operations["myOperation"].action)
 				assert.Equal(t, "topic1", asyncScope.operations["myOperation"].channel)
-				assert.Equal(t, "OrderRow", asyncScope.operations["myOperation"].Message.OneOf1.MessageEntity.MessageID)
+				assert.Equal(t, "#/components/messages/OrderRow", asyncScope.operations["myOperation"].Message.Reference.Ref)
+				require.Contains(t, asyncScope.messages, "OrderRow")
+				assert.Equal(t, "OrderRow", asyncScope.messages["OrderRow"].MessageID)
+				assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/model.OrderRow"}, asyncScope.messages["OrderRow"].Payload)
 			},
 		},
 		{
@@ -183,17 +358,91 @@ func TestParseOperationComment(t *testing.T) {
 	}
 }
 
-func TestReplaceStringInJSON(t *testing.T) {
+func TestParseOperationComment_SharedMessage(t *testing.T) {
 	t.Parallel()
-	t.Run("replaces occurrences of a string in JSON", func(t *testing.T) {
-		input := []byte(`{"$ref": "#/definitions/MyType"}`)
-		expected := []byte(`{"$ref": "#/components/schemas/MyType"}`)
 
-		result, err := replaceStringInJSON(input, "#/definitions/", "#/components/schemas/")
+	asyncScope := NewAsyncScope(nil)
+	asyncScope.parser.addTestType("model.OrderRow")
+	asyncScope.parser.addTestType("other.OrderRow")
 
-		assert.NoError(t, err)
-		assert.Equal(t, expected, result)
-	})
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@operation orderCreated send topic1 model.OrderRow`, nil))
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@operation orderUpdated send topic2 model.OrderRow`, nil))
+
+	assert.Len(t, asyncScope.messages, 1)
+	assert.Equal(t, asyncScope.operations["orderCreated"].Message, asyncScope.operations["orderUpdated"].Message)
+
+	err := asyncScope.ParseAsyncAPIComment(nil, `@operation otherCreated send topic3 other.OrderRow`, nil)
+	assert.EqualError(t, err, "message 'OrderRow' is already defined with a different payload")
+}
+
+func TestParseOperationComment_MessageList(t *testing.T) {
+	t.Parallel()
+
+	asyncScope := NewAsyncScope(nil)
+	asyncScope.parser.addTestType("model.OrderCreated")
+	asyncScope.parser.addTestType("model.OrderCancelled")
+
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@operation orderChanged send orders model.OrderCreated, model.OrderCancelled,model.OrderCreated`, nil))
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@message.name orderCancelled`, nil))
+	require.NoError(t, asyncScope.applyMessageAttributes())
+
+	out, err := json.Marshal(asyncScope.operations["orderChanged"].Message)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"oneOf": [
+		{"$ref": "#/components/messages/OrderCreated"},
+		{"$ref": "#/components/messages/OrderCancelled"}
+	]}`, string(out))
+
+	assert.Len(t, asyncScope.messages, 2)
+	assert.Equal(t, "orderCancelled", asyncScope.messages["OrderCancelled"].Name)
+	assert.Empty(t, asyncScope.messages["OrderCreated"].Name)
+}
+
+func TestParseMessageAttributes_SharedMessage(t *testing.T) {
+	t.Parallel()
+
+	asyncScope := NewAsyncScope(nil)
+	asyncScope.parser.addTestType("model.OrderRow")
+
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@operation orderCreated send topic1 model.OrderRow`, nil))
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@message.summary An order row`, nil))
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@operation orderUpdated send topic2 model.OrderRow`, nil))
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@message.summary An order row`, nil))
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@message.contentType json`, nil))
+
+	assert.Empty(t, asyncScope.messages["OrderRow"].Summary)
+	require.NoError(t, asyncScope.applyMessageAttributes())
+	assert.Equal(t, "An order row", asyncScope.messages["OrderRow"].Summary)
+	assert.Equal(t, "application/json", asyncScope.messages["OrderRow"].ContentType)
+
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@operation orderDeleted send topic3 model.OrderRow`, nil))
+	require.NoError(t, asyncScope.ParseAsyncAPIComment(nil, `@message.summary A deleted order row`, nil))
+	err := asyncScope.applyMessageAttributes()
+	require.Error(t, err)
+	assert.EqualError(t, errors.Unwrap(err), "message 'OrderRow' is already defined with a different summary")
+	assert.Equal(t, "An order row", asyncScope.messages["OrderRow"].Summary)
+}
+
+func TestMergeOperations(t *testing.T) {
+	t.Parallel()
+
+	created := *(&spec.Message{}).WithReference(spec.Reference{Ref: "#/components/messages/OrderCreated"})
+	cancelled := *(&spec.Message{}).WithReference(spec.Reference{Ref: "#/components/messages/OrderCancelled"})
+	shipped := *(&spec.Message{}).WithReference(spec.Reference{Ref: "#/components/messages/OrderShipped"})
+
+	first := (&spec.Operation{}).WithID("orderCreated").WithMessage(created)
+	assert.Same(t, first, mergeOperations(nil, first))
+
+	second := (&spec.Operation{}).WithID("orderChanged").WithMessage(newMessageList([]spec.Message{cancelled, created}))
+	second.Bindings = &spec.OperationBindingsObject{Mqtt: &spec.MqttOperation{}}
+
+	merged := mergeOperations(first, second)
+	merged = mergeOperations(merged, (&spec.Operation{}).WithID("orderShipped").WithMessage(shipped))
+
+	assert.Equal(t, "orderCreated", merged.ID)
+	assert.Equal(t, second.Bindings, merged.Bindings)
+	assert.Equal(t, []spec.Message{created, cancelled, shipped}, AsyncAPIMessages(merged.Message))
+	assert.Equal(t, &created, first.Message)
 }
 
 func TestInvalidCommentAttr(t *testing.T) {
@@ -206,3 +455,120 @@ func TestInvalidCommentAttr(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestParseMessageComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		comments    []string
+		expectedErr string
+		assertFunc  func(t *testing.T, message *spec.MessageEntity)
+	}{
+		{
+			name: "parses message name, title, summary and description",
+			comments: []string{
+				`@operation send topic1 model.OrderRow`,
+				`@message.name orderRow`,
+				`@message.title Order row`,
+				`@message.summary An order row was created`,
+				`@message.description First line`,
+				`@message.description Second line`,
+			},
+			assertFunc: func(t *testing.T, message *spec.MessageEntity) {
+				assert.Equal(t, "orderRow", message.Name)
+				assert.Equal(t, "Order row", message.Title)
+				assert.Equal(t, "An order row was created", message.Summary)
+				assert.Equal(t, "First line\nSecond line", message.Description)
+			},
+		},
+		{
+			name: "parses content type aliases",
+			comments: []string{
+				`@operation send topic1 model.OrderRow`,
+				`@message.contentType json`,
+			},
+			assertFunc: func(t *testing.T, message *spec.MessageEntity) {
+				assert.Equal(t, "application/json", message.ContentType)
+			},
+		},
+		{
+			name: "returns error for unknown content type",
+			comments: []string{
+				`@operation send topic1 model.OrderRow`,
+				`@message.contentType unknown`,
+			},
+			expectedErr: "unknown content type can't be accepted",
+		},
+		{
+			name: "parses headers",
+			comments: []string{
+				`@operation send topic1 model.OrderRow`,
+				`@message.headers model.OrderRow`,
+			},
+			assertFunc: func(t *testing.T, message *spec.MessageEntity) {
+				require.NotNil(t, message.Headers)
+				assert.Equal(t, OBJECT, message.Headers.Schema["type"])
+			},
+		},
+		{
+			name: "returns error for unknown headers type",
+			comments: []string{
+				`@operation send topic1 model.OrderRow`,
+				`@message.headers model.Unknown`,
+			},
+			expectedErr: "cannot find type definition: model.Unknown",
+		},
+		{
+			name: "parses correlation ID",
+			comments: []string{
+				`@operation send topic1 model.OrderRow`,
+				`@message.correlationId $message.header#/correlationId "Request correlation"`,
+			},
+			assertFunc: func(t *testing.T, message *spec.MessageEntity) {
+				require.NotNil(t, message.CorrelationID)
+				assert.Equal(t, "$message.header#/correlationId", message.CorrelationID.CorrelationID.Location)
+				assert.Equal(t, "Request correlation", message.CorrelationID.CorrelationID.Description)
+			},
+		},
+		{
+			name: "returns error for invalid correlation ID location",
+			comments: []string{
+				`@operation send topic1 model.OrderRow`,
+				`@message.correlationId header.correlationId`,
+			},
+			expectedErr: "invalid correlation ID location \"header.correlationId\", expected a runtime expression like $message.header#/correlationId",
+		},
+		{
+			name: "returns error when no operation precedes the message attribute",
+			comments: []string{
+				`@message.summary Lonely message`,
+			},
+			expectedErr: "@message.summary must follow an @operation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			funcName := "myOperation"
+			asyncScope := NewAsyncScope(nil)
+			asyncScope.parser.addTestType("model.OrderRow")
+
+			var err error
+			for _, comment := range tt.comments {
+				if err = asyncScope.ParseAsyncAPIComment(&funcName, comment, nil); err != nil {
+					break
+				}
+			}
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, asyncScope.applyMessageAttributes())
+			tt.assertFunc(t, asyncScope.messages["OrderRow"])
+		})
+	}
+}
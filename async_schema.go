@@ -0,0 +1,66 @@
+package swag
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/yalochat/swag/internal/jsonschema"
+)
+
+const (
+	openAPISchemaPrefix  = "#/definitions/"
+	asyncAPISchemaPrefix = "#/components/schemas/"
+)
+
+// CreateAsyncAPISchema converts a Swagger 2.0 schema into an AsyncAPI schema object. Every keyword is kept,
+// references to definitions are pointed to the AsyncAPI components and the few keywords whose meaning differs
+// between Swagger 2.0 and JSON Schema draft 7 are translated.
+func CreateAsyncAPISchema(schema *spec.Schema) (map[string]interface{}, error) {
+	jsonData, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var asyncAPISchema map[string]interface{}
+	if err := json.Unmarshal(jsonData, &asyncAPISchema); err != nil {
+		return nil, err
+	}
+
+	convertAsyncAPISchema(asyncAPISchema)
+
+	return asyncAPISchema, nil
+}
+
+// GetAsyncAPISchemaProperties converts the properties of a Swagger 2.0 object schema into AsyncAPI schemas.
+//
+// Deprecated: use CreateAsyncAPISchema, which converts the whole schema.
+func GetAsyncAPISchemaProperties(typeSchema *spec.Schema) (map[string]interface{}, error) {
+	asyncAPISchema, err := CreateAsyncAPISchema(typeSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, _ := asyncAPISchema["properties"].(map[string]interface{})
+
+	return properties, nil
+}
+
+// asyncAPISchemaRef converts a Swagger definition reference into an AsyncAPI component schema reference.
+func asyncAPISchemaRef(ref string) string {
+	return asyncAPISchemaPrefix + strings.TrimPrefix(ref, openAPISchemaPrefix)
+}
+
+// convertAsyncAPISchema translates a decoded Swagger schema in place into an AsyncAPI schema.
+func convertAsyncAPISchema(schema map[string]interface{}) {
+	jsonschema.Convert(schema, func(ref string) string {
+		if strings.HasPrefix(ref, openAPISchemaPrefix) {
+			return asyncAPISchemaRef(ref)
+		}
+
+		return ref
+	}, func(schema map[string]interface{}) {
+		// xml only applies to XML payloads described by Swagger.
+		delete(schema, "xml")
+	})
+}
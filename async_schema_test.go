@@ -0,0 +1,245 @@
+package swag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAsyncAPISchema_FieldTags(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+type Item struct {
+	ID int
+}
+
+// Event is emitted for every change
+type Event struct {
+	// ID of the event
+	ID string ` + "`" + `json:"id" binding:"required" format:"uuid" example:"a5f6b3c2-1d2e-4f3a-9b8c-7d6e5f4a3b2c"` + "`" + `
+	Name string ` + "`" + `json:"name" title:"Event name" validate:"required,min=1,max=32" default:"created"` + "`" + `
+	Kind string ` + "`" + `json:"kind" enums:"created,deleted" x-enum-varnames:"Created,Deleted"` + "`" + `
+	Level int ` + "`" + `json:"level" validate:"oneof=1 2 3"` + "`" + `
+	Score float64 ` + "`" + `json:"score" minimum:"0" maximum:"10.5" multipleOf:"0.5"` + "`" + `
+	Code string ` + "`" + `json:"code" minLength:"2" maxLength:"4" readonly:"true"` + "`" + `
+	Count int ` + "`" + `json:"count,string"` + "`" + `
+	Tags []string ` + "`" + `json:"tags" validate:"min=1,max=5,unique" format:"hostname" example:"a,b"` + "`" + `
+	Labels map[string]string ` + "`" + `json:"labels" extensions:"x-nullable,x-order=1"` + "`" + `
+	Items []Item ` + "`" + `json:"items"` + "`" + `
+	Parent *Item ` + "`" + `json:"parent"` + "`" + `
+}
+
+// @Success 200 {object} Event
+// @Router /events [get]
+func Test(){
+}
+`
+	expected := `{
+	"type": "object",
+	"required": ["id", "name"],
+	"properties": {
+		"id": {
+			"description": "ID of the event",
+			"type": "string",
+			"format": "uuid",
+			"example": "a5f6b3c2-1d2e-4f3a-9b8c-7d6e5f4a3b2c"
+		},
+		"name": {
+			"type": "string",
+			"title": "Event name",
+			"default": "created",
+			"minLength": 1,
+			"maxLength": 32
+		},
+		"kind": {
+			"type": "string",
+			"enum": ["created", "deleted"],
+			"x-enum-varnames": ["Created", "Deleted"]
+		},
+		"level": {
+			"type": "integer",
+			"enum": [1, 2, 3]
+		},
+		"score": {
+			"type": "number",
+			"minimum": 0,
+			"maximum": 10.5,
+			"multipleOf": 0.5
+		},
+		"code": {
+			"type": "string",
+			"minLength": 2,
+			"maxLength": 4,
+			"readOnly": true
+		},
+		"count": {
+			"type": "string",
+			"example": "0"
+		},
+		"tags": {
+			"type": "array",
+			"minItems": 1,
+			"maxItems": 5,
+			"uniqueItems": true,
+			"example": ["a", "b"],
+			"items": {
+				"type": "string",
+				"format": "hostname"
+			}
+		},
+		"labels": {
+			"type": "object",
+			"additionalProperties": {
+				"type": "string"
+			},
+			"x-nullable": true,
+			"x-order": "1"
+		},
+		"items": {
+			"type": "array",
+			"items": {
+				"$ref": "#/components/schemas/api.Item"
+			}
+		},
+		"parent": {
+			"$ref": "#/components/schemas/api.Item"
+		}
+	}
+}`
+
+	p := New()
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	definition, ok := p.swagger.Definitions["api.Event"]
+	require.True(t, ok)
+
+	schema, err := CreateAsyncAPISchema(&definition)
+	require.NoError(t, err)
+
+	out, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, expected, string(out))
+}
+
+func TestCreateAsyncAPISchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		schema   *spec.Schema
+		expected string
+	}{
+		{
+			name:     "empty schema",
+			schema:   &spec.Schema{},
+			expected: `{}`,
+		},
+		{
+			name: "allOf composition without type",
+			schema: &spec.Schema{SchemaProps: spec.SchemaProps{
+				AllOf: []spec.Schema{
+					*RefSchema("model.Base"),
+					*PrimitiveSchema(OBJECT).SetProperty("data", *spec.ArrayProperty(RefSchema("model.Item"))),
+				},
+			}},
+			expected: `{
+				"allOf": [
+					{"$ref": "#/components/schemas/model.Base"},
+					{"type": "object", "properties": {"data": {"type": "array", "items": {"$ref": "#/components/schemas/model.Item"}}}}
+				]
+			}`,
+		},
+		{
+			name:     "additional properties",
+			schema:   spec.MapProperty(RefSchema("model.Item")),
+			expected: `{"type": "object", "additionalProperties": {"$ref": "#/components/schemas/model.Item"}}`,
+		},
+		{
+			name:     "exclusive bounds",
+			schema:   spec.Int64Property().WithMinimum(0, true).WithMaximum(100, false),
+			expected: `{"type": "integer", "format": "int64", "exclusiveMinimum": 0, "maximum": 100}`,
+		},
+		{
+			name:     "nullable",
+			schema:   spec.StringProperty().AsNullable(),
+			expected: `{"type": ["string", "null"]}`,
+		},
+		{
+			name: "xml",
+			schema: &spec.Schema{
+				SchemaProps:        spec.SchemaProps{Type: []string{STRING}},
+				SwaggerSchemaProps: spec.SwaggerSchemaProps{XML: &spec.XMLObject{Name: "value"}},
+			},
+			expected: `{"type": "string"}`,
+		},
+		{
+			name: "values and texts are kept",
+			schema: &spec.Schema{
+				SchemaProps: spec.SchemaProps{
+					Type:        []string{OBJECT},
+					Description: "see #/definitions/model.Item",
+					Properties: map[string]spec.Schema{
+						"enum":    *RefSchema("model.Item"),
+						"example": *RefSchema("model.Item"),
+					},
+					Default: map[string]interface{}{"$ref": "#/definitions/model.Item"},
+				},
+				SwaggerSchemaProps: spec.SwaggerSchemaProps{
+					Example: map[string]interface{}{"$ref": "#/definitions/model.Item"},
+				},
+			},
+			expected: `{
+				"type": "object",
+				"description": "see #/definitions/model.Item",
+				"properties": {
+					"enum": {"$ref": "#/components/schemas/model.Item"},
+					"example": {"$ref": "#/components/schemas/model.Item"}
+				},
+				"default": {"$ref": "#/definitions/model.Item"},
+				"example": {"$ref": "#/definitions/model.Item"}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := CreateAsyncAPISchema(tt.schema)
+			require.NoError(t, err)
+
+			out, err := json.Marshal(schema)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestGetAsyncAPISchemaProperties(t *testing.T) {
+	t.Parallel()
+
+	schema := (&spec.Schema{}).Typed(OBJECT, "").
+		SetProperty("id", *spec.Int64Property()).
+		SetProperty("owner", *spec.RefSchema("#/definitions/model.User"))
+
+	properties, err := GetAsyncAPISchemaProperties(schema)
+	require.NoError(t, err)
+
+	out, err := json.Marshal(properties)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": {"type": "integer", "format": "int64"},
+		"owner": {"$ref": "#/components/schemas/model.User"}
+	}`, string(out))
+
+	properties, err = GetAsyncAPISchemaProperties(spec.StringProperty())
+	require.NoError(t, err)
+	assert.Nil(t, properties)
+}
@@ -0,0 +1,127 @@
+// Package asyncswag holds the AsyncAPI document embedded in the generated docs.go. It only depends on the standard
+// library, so services serving their event contract don't link the parser.
+package asyncswag
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+// Name is the name the AsyncAPI document of docs.go is registered with.
+const Name = "asyncapi"
+
+// AsyncSpec holds exported AsyncAPI Info so clients can modify it.
+type AsyncSpec struct {
+	Version     string
+	Title       string
+	Description string
+	// Host replaces the host of the server of a document having a single server
+	Host string
+	// Hosts replace the hosts of the servers by server name, e.g. the production and staging brokers
+	Hosts            map[string]string
+	InfoInstanceName string
+	AsyncAPITemplate string
+	LeftDelim        string
+	RightDelim       string
+}
+
+// ReadDoc parses AsyncAPITemplate into AsyncAPI document.
+func (i *AsyncSpec) ReadDoc() string {
+	i.Description = strings.ReplaceAll(i.Description, "\n", "\\n")
+
+	doc := executeDocTemplate("asyncapi_info", i.AsyncAPITemplate, i.LeftDelim, i.RightDelim, i)
+	if i.Host == "" && len(i.Hosts) == 0 {
+		return doc
+	}
+
+	var asyncAPI map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &asyncAPI); err != nil {
+		return doc
+	}
+
+	servers, _ := asyncAPI["servers"].(map[string]interface{})
+	for name, item := range servers {
+		server, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		host, ok := i.Hosts[name]
+		if !ok {
+			if len(servers) != 1 || i.Host == "" {
+				continue
+			}
+
+			host = i.Host
+		}
+
+		// AsyncAPI 3.0 servers have a host, AsyncAPI 2.x servers have an URL holding the host.
+		if _, ok := server["host"]; ok {
+			server["host"] = host
+		} else if url, ok := server["url"].(string); ok {
+			server["url"] = replaceURLHost(url, host)
+		}
+	}
+
+	data, err := json.MarshalIndent(asyncAPI, "", "    ")
+	if err != nil {
+		return doc
+	}
+
+	return string(data)
+}
+
+// InstanceName returns AsyncSpec instance name.
+func (i *AsyncSpec) InstanceName() string {
+	return i.InfoInstanceName
+}
+
+// replaceURLHost replaces the host of an URL, which may have no scheme (e.g., "kafka.internal:9092").
+func replaceURLHost(url, host string) string {
+	scheme := ""
+	if index := strings.Index(url, "://"); index != -1 {
+		scheme, url = url[:index+3], url[index+3:]
+	}
+
+	if index := strings.Index(url, "/"); index != -1 {
+		return scheme + host + url[index:]
+	}
+
+	return scheme + host
+}
+
+func executeDocTemplate(name, text, leftDelim, rightDelim string, data interface{}) string {
+	tpl := template.New(name).Funcs(template.FuncMap{
+		"marshal": func(v interface{}) string {
+			a, _ := json.Marshal(v)
+
+			return string(a)
+		},
+		"escape": func(v interface{}) string {
+			// escape tabs
+			var str = strings.ReplaceAll(v.(string), "\t", "\\t")
+			// replace " with \", and if that results in \\", replace that with \\\"
+			str = strings.ReplaceAll(str, "\"", "\\\"")
+
+			return strings.ReplaceAll(str, "\\\\\"", "\\\\\\\"")
+		},
+	})
+
+	if leftDelim != "" && rightDelim != "" {
+		tpl = tpl.Delims(leftDelim, rightDelim)
+	}
+
+	parsed, err := tpl.Parse(text)
+	if err != nil {
+		return text
+	}
+
+	var doc bytes.Buffer
+	if err = parsed.Execute(&doc, data); err != nil {
+		return text
+	}
+
+	return doc.String()
+}
@@ -0,0 +1,79 @@
+package asyncswag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncSpec_ReadDoc(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		hosts    map[string]string
+		template string
+		want     string
+	}{
+		{
+			name:     "info overrides",
+			template: `{"asyncapi": "2.4.0", "info": {"title": "{{.Title}}", "version": "{{.Version}}", "description": "{{escape .Description}}"}}`,
+			want:     `{"asyncapi": "2.4.0", "info": {"title": "Orders", "version": "2.0", "description": "Orders \"events\"\nof the shop"}}`,
+		},
+		{
+			name:     "AsyncAPI 2.4.0 host override",
+			host:     "kafka.staging:9092",
+			template: `{"asyncapi": "2.4.0", "servers": {"kafka": {"url": "kafka://kafka.internal:9092/orders", "protocol": "kafka"}}}`,
+			want:     `{"asyncapi": "2.4.0", "servers": {"kafka": {"url": "kafka://kafka.staging:9092/orders", "protocol": "kafka"}}}`,
+		},
+		{
+			name: "host ignored with several servers",
+			host: "kafka.staging:9092",
+			template: `{"asyncapi": "2.4.0", "servers": {
+				"production": {"url": "kafka://kafka.internal:9092/orders", "protocol": "kafka"},
+				"staging": {"url": "kafka.staging.internal:9092", "protocol": "kafka"}
+			}}`,
+			want: `{"asyncapi": "2.4.0", "servers": {
+				"production": {"url": "kafka://kafka.internal:9092/orders", "protocol": "kafka"},
+				"staging": {"url": "kafka.staging.internal:9092", "protocol": "kafka"}
+			}}`,
+		},
+		{
+			name:  "hosts override by server name",
+			host:  "ignored:9092",
+			hosts: map[string]string{"production": "kafka.eu:9092", "staging": "localhost:9092", "unknown": "other:9092"},
+			template: `{"asyncapi": "2.4.0", "servers": {
+				"production": {"url": "kafka://kafka.internal:9092/orders", "protocol": "kafka"},
+				"staging": {"url": "kafka.staging.internal:9092", "protocol": "kafka"},
+				"local": {"url": "localhost:9093", "protocol": "kafka"}
+			}}`,
+			want: `{"asyncapi": "2.4.0", "servers": {
+				"production": {"url": "kafka://kafka.eu:9092/orders", "protocol": "kafka"},
+				"staging": {"url": "localhost:9092", "protocol": "kafka"},
+				"local": {"url": "localhost:9093", "protocol": "kafka"}
+			}}`,
+		},
+		{
+			name:     "AsyncAPI 3.0.0 host override",
+			host:     "kafka.staging:9092",
+			template: `{"asyncapi": "3.0.0", "servers": {"kafka": {"host": "kafka.internal:9092", "pathname": "/orders", "protocol": "kafka"}}}`,
+			want:     `{"asyncapi": "3.0.0", "servers": {"kafka": {"host": "kafka.staging:9092", "pathname": "/orders", "protocol": "kafka"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := AsyncSpec{
+				Version:          "2.0",
+				Title:            "Orders",
+				Description:      "Orders \"events\"\nof the shop",
+				Host:             tt.host,
+				Hosts:            tt.hosts,
+				InfoInstanceName: Name,
+				AsyncAPITemplate: tt.template,
+			}
+
+			assert.Equal(t, Name, doc.InstanceName())
+			assert.JSONEq(t, tt.want, doc.ReadDoc())
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// Package asynctransport holds the broker abstraction used by the AsyncAPI publishers and subscribers
+// generated by swag, and an in-memory implementation to unit test them.
+package asynctransport
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Handler processes the payload of a message received on a channel.
+type Handler func(ctx context.Context, channel string, payload []byte) error
+
+// Transport sends and receives raw messages through a broker.
+type Transport interface {
+	// Publish sends the payload to the channel.
+	Publish(ctx context.Context, channel string, payload []byte) error
+	// Subscribe registers the handler and returns, the handler is then called for every message received on the
+	// channel until the context is done. Transports translate the parameters of the channel into the wildcard of
+	// their broker, see Channel.Wildcard, and pass the name of the channel of every message to the handler.
+	Subscribe(ctx context.Context, channel Channel, handler Handler) error
+}
+
+// Channel is a channel to subscribe to, its parameters are {param} placeholders of its name which match any value.
+type Channel struct {
+	// Name of the channel, like tenants/{tenantId}/orders
+	Name string
+	// Params are the names of the parameters of the channel, in the order of their placeholders
+	Params []string
+}
+
+// Wildcard returns the name of the channel with every parameter replaced by the wildcard of a broker, like + for
+// MQTT or * for NATS and AMQP topic exchanges.
+func (channel Channel) Wildcard(wildcard string) string {
+	return channel.replaceParams(func(literal string) string {
+		return literal
+	}, func(string) string {
+		return wildcard
+	})
+}
+
+// replaceParams returns the name of the channel with every parameter replaced by param and every other part by literal.
+func (channel Channel) replaceParams(literal, param func(name string) string) string {
+	var name strings.Builder
+
+	last := 0
+	for _, location := range placeholderPattern.FindAllStringSubmatchIndex(channel.Name, -1) {
+		if !channel.hasParam(channel.Name[location[2]:location[3]]) {
+			continue
+		}
+
+		name.WriteString(literal(channel.Name[last:location[0]]))
+		name.WriteString(param(channel.Name[location[2]:location[3]]))
+		last = location[1]
+	}
+
+	name.WriteString(literal(channel.Name[last:]))
+
+	return name.String()
+}
+
+// ParamValues returns the values of the parameters of the channel in the name of a channel it matches, like acme for
+// tenants/acme/orders, in the order of Params.
+func (channel Channel) ParamValues(name string) ([]string, error) {
+	pattern, params, err := channelPattern(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := pattern.FindStringSubmatch(name)
+	if matches == nil {
+		return nil, fmt.Errorf("channel '%s' doesn't match '%s'", name, channel.Name)
+	}
+
+	values := make(map[string]string, len(channel.Params))
+	for i, param := range params {
+		if _, ok := values[param]; !ok {
+			values[param] = matches[i+1]
+		}
+	}
+
+	ordered := make([]string, 0, len(channel.Params))
+	for _, param := range channel.Params {
+		ordered = append(ordered, values[param])
+	}
+
+	return ordered, nil
+}
+
+func (channel Channel) hasParam(name string) bool {
+	for _, param := range channel.Params {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Message is a message sent through a Memory transport.
+type Message struct {
+	Channel string
+	Payload []byte
+}
+
+// Memory is a Transport delivering every published message synchronously to the handlers subscribed to its
+// channel, it's meant for tests.
+type Memory struct {
+	mutex         sync.RWMutex
+	subscriptions map[int]memorySubscription
+	lastID        int
+	published     []Message
+}
+
+type memorySubscription struct {
+	pattern *regexp.Regexp
+	handler Handler
+}
+
+var placeholderPattern = regexp.MustCompile(`{([^{}]+)}`)
+
+// channelSeparators are the characters separating the segments of channel names, like / for MQTT or . for NATS.
+const channelSeparators = "/.:"
+
+// NewMemory creates a new Memory transport.
+func NewMemory() *Memory {
+	return &Memory{
+		subscriptions: make(map[int]memorySubscription),
+	}
+}
+
+// Publish implements Transport. The first error returned by a handler is returned.
+func (memory *Memory) Publish(ctx context.Context, channel string, payload []byte) error {
+	memory.mutex.Lock()
+	memory.published = append(memory.published, Message{Channel: channel, Payload: payload})
+
+	var handlers []Handler
+	for id := 1; id <= memory.lastID; id++ {
+		if subscription, ok := memory.subscriptions[id]; ok && subscription.pattern.MatchString(channel) {
+			handlers = append(handlers, subscription.handler)
+		}
+	}
+	memory.mutex.Unlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, channel, payload); err != nil {
+			return fmt.Errorf("handler of channel '%s' failed: %w", channel, err)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe implements Transport.
+func (memory *Memory) Subscribe(ctx context.Context, channel Channel, handler Handler) error {
+	pattern, _, err := channelPattern(channel)
+	if err != nil {
+		return err
+	}
+
+	memory.mutex.Lock()
+	memory.lastID++
+	id := memory.lastID
+	memory.subscriptions[id] = memorySubscription{pattern: pattern, handler: handler}
+	memory.mutex.Unlock()
+
+	if ctx.Done() != nil {
+		go func() {
+			<-ctx.Done()
+
+			memory.mutex.Lock()
+			delete(memory.subscriptions, id)
+			memory.mutex.Unlock()
+		}()
+	}
+
+	return nil
+}
+
+// channelPattern returns the regular expression matching the names of a channel, with a group per placeholder of a
+// parameter, and the names of the parameters of the groups. Parameters match a single segment of the name: they don't
+// match the separators used by the channel.
+func channelPattern(channel Channel) (*regexp.Regexp, []string, error) {
+	var separators strings.Builder
+	for _, separator := range channelSeparators {
+		if strings.ContainsRune(channel.Wildcard(""), separator) {
+			separators.WriteRune(separator)
+		}
+	}
+
+	segment := `(.+)`
+	if separators.Len() > 0 {
+		segment = `([^` + regexp.QuoteMeta(separators.String()) + `]+)`
+	}
+
+	var params []string
+
+	pattern := channel.replaceParams(regexp.QuoteMeta, func(name string) string {
+		params = append(params, name)
+
+		return segment
+	})
+
+	compiled, err := regexp.Compile("^" + pattern + "$")
+
+	return compiled, params, err
+}
+
+// Published returns the messages published so far, in order.
+func (memory *Memory) Published() []Message {
+	memory.mutex.RLock()
+	defer memory.mutex.RUnlock()
+
+	return append([]Message(nil), memory.published...)
+}
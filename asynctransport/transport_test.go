@@ -0,0 +1,96 @@
+package asynctransport
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+
+	transport := NewMemory()
+
+	var received []Message
+	handler := func(ctx context.Context, channel string, payload []byte) error {
+		received = append(received, Message{Channel: channel, Payload: payload})
+		return nil
+	}
+
+	require.NoError(t, transport.Subscribe(context.Background(), Channel{Name: "orders/{tenantId}", Params: []string{"tenantId"}}, handler))
+	require.NoError(t, transport.Subscribe(context.Background(), Channel{Name: "orders.created"}, handler))
+	require.NoError(t, transport.Subscribe(context.Background(), Channel{Name: "tenants.{tenantId}.items", Params: []string{"tenantId"}}, handler))
+
+	require.NoError(t, transport.Publish(context.Background(), "orders/acme", []byte(`{"id":1}`)))
+	require.NoError(t, transport.Publish(context.Background(), "orders/acme/items", []byte(`{"id":2}`)))
+	require.NoError(t, transport.Publish(context.Background(), "orders.created", []byte(`{"id":3}`)))
+	require.NoError(t, transport.Publish(context.Background(), "ordersXcreated", []byte(`{"id":4}`)))
+	require.NoError(t, transport.Publish(context.Background(), "orders/acme.v2", []byte(`{"id":5}`)))
+	require.NoError(t, transport.Publish(context.Background(), "tenants.acme.items", []byte(`{"id":6}`)))
+	require.NoError(t, transport.Publish(context.Background(), "tenants.acme.eu.items", []byte(`{"id":7}`)))
+
+	assert.Equal(t, []Message{
+		{Channel: "orders/acme", Payload: []byte(`{"id":1}`)},
+		{Channel: "orders.created", Payload: []byte(`{"id":3}`)},
+		{Channel: "orders/acme.v2", Payload: []byte(`{"id":5}`)},
+		{Channel: "tenants.acme.items", Payload: []byte(`{"id":6}`)},
+	}, received)
+	assert.Len(t, transport.Published(), 7)
+}
+
+func TestChannel_Wildcard(t *testing.T) {
+	t.Parallel()
+
+	channel := Channel{Name: "tenants/{tenantId}/orders/{orderId}", Params: []string{"tenantId", "orderId"}}
+	assert.Equal(t, "tenants/+/orders/+", channel.Wildcard("+"))
+
+	channel = Channel{Name: "tenants.{tenantId}.{version}", Params: []string{"tenantId"}}
+	assert.Equal(t, "tenants.*.{version}", channel.Wildcard("*"))
+}
+
+func TestChannel_ParamValues(t *testing.T) {
+	t.Parallel()
+
+	channel := Channel{Name: "tenants/{tenantId}/orders/{orderId}", Params: []string{"orderId", "tenantId"}}
+
+	values, err := channel.ParamValues("tenants/acme/orders/42")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"42", "acme"}, values)
+
+	_, err = channel.ParamValues("tenants/acme/items/42")
+	assert.EqualError(t, err, "channel 'tenants/acme/items/42' doesn't match 'tenants/{tenantId}/orders/{orderId}'")
+}
+
+func TestMemory_HandlerError(t *testing.T) {
+	t.Parallel()
+
+	transport := NewMemory()
+
+	require.NoError(t, transport.Subscribe(context.Background(), Channel{Name: "orders"}, func(ctx context.Context, channel string, payload []byte) error {
+		return errors.New("invalid order")
+	}))
+
+	err := transport.Publish(context.Background(), "orders", nil)
+	assert.EqualError(t, err, "handler of channel 'orders' failed: invalid order")
+}
+
+func TestMemory_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	transport := NewMemory()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, transport.Subscribe(ctx, Channel{Name: "orders"}, func(ctx context.Context, channel string, payload []byte) error {
+		return errors.New("unexpected message")
+	}))
+
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		return transport.Publish(context.Background(), "orders", nil) == nil
+	}, time.Second, time.Millisecond)
+}
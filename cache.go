@@ -0,0 +1,516 @@
+package swag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// SetCacheDir sets the directory of the parse cache, which stores the operations and the definitions parsed from
+// every file by content hash and swag version, so that the operations and the type definitions of unchanged files
+// aren't parsed again. The files are still read and parsed into an AST on every run. The cache is disabled when the
+// directory is empty, and with a warning in lint mode and when inferring routes or schemas.
+func SetCacheDir(cacheDir string) func(*Parser) {
+	return func(p *Parser) {
+		p.cacheDir = cacheDir
+	}
+}
+
+// parseCache stores the results of parsing the operations of the files.
+type parseCache struct {
+	dir string
+
+	// fingerprint of swag version and of the options which change the results
+	fingerprint string
+
+	// hashes holds the content hash of the files by path
+	hashes map[string]string
+
+	// typeNames holds the definition name of the types declared by the files, by type key
+	typeNames map[string]string
+}
+
+// parseCacheEntry is what parsing the operations of a file produces.
+type parseCacheEntry struct {
+	// Dependencies holds the content hash of the files declaring the types used by the operations, by path
+	Dependencies map[string]string `json:"dependencies"`
+
+	// Types holds the definition name of the types used by the operations, by type key, as the name of a type
+	// depends on the types declared by the other packages
+	Types map[string]string `json:"types"`
+
+	Operations []parseCacheOperation `json:"operations"`
+
+	// Definitions are the definitions referenced by the operations, directly or not
+	Definitions map[string]spec.Schema `json:"definitions"`
+}
+
+type parseCacheOperation struct {
+	Routes    []RouteProperties  `json:"routes"`
+	Operation spec.Operation     `json:"operation"`
+	Positions attributePositions `json:"positions"`
+}
+
+// parseCacheRecorder records the operations of a file and the files declaring the types they use.
+type parseCacheRecorder struct {
+	dependencies *cacheDependencies
+	operations   []parseCacheOperation
+
+	// uncacheable is set when the results depend on something else than the files, like a markdown file
+	uncacheable bool
+}
+
+// cacheDependencies holds the files declaring the types used by the operations or by a definition, and the
+// definition names of the types.
+type cacheDependencies struct {
+	files map[string]struct{}
+	types map[string]string
+}
+
+func newCacheDependencies() *cacheDependencies {
+	return &cacheDependencies{
+		files: make(map[string]struct{}),
+		types: make(map[string]string),
+	}
+}
+
+func (dependencies *cacheDependencies) add(others *cacheDependencies) {
+	for file := range others.files {
+		dependencies.files[file] = struct{}{}
+	}
+
+	for key, name := range others.types {
+		dependencies.types[key] = name
+	}
+}
+
+func newParseCache(dir, fingerprint string, packages *PackagesDefinitions) *parseCache {
+	return &parseCache{
+		dir:         dir,
+		fingerprint: fingerprint,
+		hashes:      make(map[string]string),
+		typeNames:   declaredTypeNames(packages),
+	}
+}
+
+// declaredTypeNames returns the definition name of the types declared by the files, by type key, without parsing the
+// type definitions: the name of a type is qualified by its package path when another package with the same name
+// declares a type with the same name, like ParseTypes does.
+func declaredTypeNames(packages *PackagesDefinitions) map[string]string {
+	var typeSpecDefs []*TypeSpecDef
+
+	for _, fileInfo := range packages.files {
+		for _, decl := range fileInfo.File.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				typeSpecDefs = appendTypeSpecDefs(typeSpecDefs, decl, fileInfo, nil)
+			case *ast.FuncDecl:
+				if decl.Body == nil {
+					continue
+				}
+
+				for _, stmt := range decl.Body.List {
+					if declStmt, ok := stmt.(*ast.DeclStmt); ok {
+						if genDecl, ok := declStmt.Decl.(*ast.GenDecl); ok {
+							typeSpecDefs = appendTypeSpecDefs(typeSpecDefs, genDecl, fileInfo, decl)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// the package paths of the types, by unqualified name
+	pkgPaths := make(map[string]map[string]struct{})
+
+	for _, typeSpecDef := range typeSpecDefs {
+		name := typeSpecDef.TypeName()
+		if pkgPaths[name] == nil {
+			pkgPaths[name] = make(map[string]struct{})
+		}
+
+		pkgPaths[name][typeSpecDef.PkgPath] = struct{}{}
+	}
+
+	typeNames := make(map[string]string, len(typeSpecDefs))
+
+	for _, typeSpecDef := range typeSpecDefs {
+		typeSpecDef.NotUnique = len(pkgPaths[typeSpecDef.TypeName()]) > 1
+		typeNames[cacheTypeKey(typeSpecDef)] = typeSpecDef.TypeName()
+	}
+
+	return typeNames
+}
+
+func appendTypeSpecDefs(typeSpecDefs []*TypeSpecDef, genDecl *ast.GenDecl, fileInfo *AstFileInfo, parent ast.Decl) []*TypeSpecDef {
+	if genDecl.Tok != token.TYPE {
+		return typeSpecDefs
+	}
+
+	for _, spec := range genDecl.Specs {
+		if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+			typeSpecDefs = append(typeSpecDefs, &TypeSpecDef{
+				PkgPath:    fileInfo.PackagePath,
+				File:       fileInfo.File,
+				TypeSpec:   typeSpec,
+				ParentSpec: parent,
+			})
+		}
+	}
+
+	return typeSpecDefs
+}
+
+// cacheTypeKey returns the key of a type in the parse cache: its package path, the function declaring it, if any,
+// and its name.
+func cacheTypeKey(typeSpecDef *TypeSpecDef) string {
+	if funcDecl, ok := typeSpecDef.ParentSpec.(*ast.FuncDecl); ok && funcDecl != nil {
+		return typeSpecDef.PkgPath + "." + funcDecl.Name.Name + "." + typeSpecDef.Name()
+	}
+
+	return typeSpecDef.FullPath()
+}
+
+// cacheFingerprint returns the hash of swag version and of the options which change the parsed operations.
+func (parser *Parser) cacheFingerprint() string {
+	tags := make([]string, 0, len(parser.tags))
+	for tag := range parser.tags {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	options, _ := json.Marshal([]interface{}{
+		Version,
+		parser.PropNamingStrategy,
+		parser.ParseVendor,
+		parser.ParseDependency,
+		parser.ParseInternal,
+		parser.Strict,
+		parser.RequiredByDefault,
+		parser.collectionFormatInQuery,
+		parser.excludes,
+		parser.packagePrefix,
+		parser.parseExtension,
+		parser.Overrides,
+		tags,
+		parser.HostState,
+		parser.ParseFuncBody,
+	})
+
+	return hashBytes(options)
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// hash returns the content hash of a file, empty if it can't be read.
+func (cache *parseCache) hash(path string) string {
+	if hash, ok := cache.hashes[path]; ok {
+		return hash
+	}
+
+	var hash string
+
+	if data, err := os.ReadFile(path); err == nil {
+		hash = hashBytes(data)
+	}
+
+	cache.hashes[path] = hash
+
+	return hash
+}
+
+// entryPath returns the path of the cache entry of a file, empty if the file can't be read.
+func (cache *parseCache) entryPath(path string) string {
+	hash := cache.hash(path)
+	if hash == "" {
+		return ""
+	}
+
+	return filepath.Join(cache.dir, hashBytes([]byte(cache.fingerprint+"\x00"+path+"\x00"+hash))+".json")
+}
+
+// load returns the cache entry of a file, nil if there is none, if a dependency has changed or if the definition name
+// of a type it uses has changed.
+func (cache *parseCache) load(path string) *parseCacheEntry {
+	entryPath := cache.entryPath(path)
+	if entryPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(entryPath)
+	if err != nil {
+		return nil
+	}
+
+	var entry parseCacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+
+	for dependency, hash := range entry.Dependencies {
+		if cache.hash(dependency) != hash {
+			return nil
+		}
+	}
+
+	for key, name := range entry.Types {
+		if cache.typeNames[key] != name {
+			return nil
+		}
+	}
+
+	return &entry
+}
+
+// store writes the cache entry of a file.
+func (cache *parseCache) store(path string, entry *parseCacheEntry) error {
+	entryPath := cache.entryPath(path)
+	if entryPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(cache.dir, 0755); err != nil {
+		return err
+	}
+
+	// write then rename, so that a concurrent run never reads a partial entry
+	tmpFile, err := os.CreateTemp(cache.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+
+		return err
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpFile.Name())
+
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), entryPath)
+}
+
+// parseRouterAPIInfoWithCache adds the cached operations of a file, or parses them and stores them in the cache.
+func (parser *Parser) parseRouterAPIInfoWithCache(fileInfo *AstFileInfo) error {
+	if entry := parser.cache.load(fileInfo.Path); entry != nil {
+		parser.debug.Printf("Using the cached operations of %s", fileInfo.Path)
+
+		for name, definition := range entry.Definitions {
+			parser.swagger.Definitions[name] = definition
+		}
+
+		for _, cached := range entry.Operations {
+			operation := NewOperation(parser)
+			operation.Operation = cached.Operation
+			operation.RouterProperties = cached.Routes
+			operation.positions = cached.Positions
+
+			if err := parser.checkOperationAttributes(operation); err != nil {
+				return err
+			}
+
+			if err := processRouterOperation(parser, operation); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := parser.parseTypes(); err != nil {
+		return err
+	}
+
+	parseErrors := len(parser.parseErrors)
+
+	parser.cacheRecorder = &parseCacheRecorder{dependencies: newCacheDependencies()}
+	defer func() {
+		parser.cacheRecorder = nil
+	}()
+
+	if err := parser.parseRouterAPIInfo(fileInfo); err != nil {
+		return err
+	}
+
+	if parser.cacheRecorder.uncacheable || len(parser.parseErrors) > parseErrors {
+		return nil
+	}
+
+	entry, ok := parser.newParseCacheEntry(parser.cacheRecorder)
+	if !ok {
+		return nil
+	}
+
+	if err := parser.cache.store(fileInfo.Path, entry); err != nil {
+		parser.debug.Printf("warning: cannot cache the operations of %s: %s", fileInfo.Path, err)
+	}
+
+	return nil
+}
+
+// definitionRefPattern matches the references to the definitions in JSON.
+var definitionRefPattern = regexp.MustCompile(`"\$ref":"#/definitions/([^"]+)"`)
+
+// newParseCacheEntry returns the cache entry of the recorded operations with the definitions they reference, false
+// if they can't be cached.
+func (parser *Parser) newParseCacheEntry(recorder *parseCacheRecorder) (*parseCacheEntry, bool) {
+	entry := &parseCacheEntry{
+		Dependencies: make(map[string]string, len(recorder.dependencies.files)),
+		Types:        recorder.dependencies.types,
+		Operations:   recorder.operations,
+		Definitions:  make(map[string]spec.Schema),
+	}
+
+	for dependency := range recorder.dependencies.files {
+		hash := parser.cache.hash(dependency)
+		if hash == "" {
+			return nil, false
+		}
+
+		entry.Dependencies[dependency] = hash
+	}
+
+	data, err := json.Marshal(recorder.operations)
+	if err != nil {
+		return nil, false
+	}
+
+	for pending := [][]byte{data}; len(pending) > 0; pending = pending[1:] {
+		for _, match := range definitionRefPattern.FindAllSubmatch(pending[0], -1) {
+			name := string(match[1])
+			if _, ok := entry.Definitions[name]; ok {
+				continue
+			}
+
+			definition, ok := parser.swagger.Definitions[name]
+			if !ok {
+				return nil, false
+			}
+
+			entry.Definitions[name] = definition
+
+			data, err = json.Marshal(definition)
+			if err != nil {
+				return nil, false
+			}
+
+			pending = append(pending, data)
+		}
+	}
+
+	return entry, true
+}
+
+// recordTypeDependency records the file declaring a type and its definition name, and the ones of the types of its
+// definition, as dependencies of the file being parsed and of the definitions being parsed.
+func (parser *Parser) recordTypeDependency(typeSpecDef *TypeSpecDef) {
+	if parser.cacheRecorder == nil {
+		return
+	}
+
+	if typeSpecDef == nil {
+		return
+	}
+
+	fileInfo, ok := parser.packages.files[typeSpecDef.File]
+	if !ok {
+		// like the types of the packages loaded by the loader
+		parser.cacheRecorder.uncacheable = true
+
+		return
+	}
+
+	if typeSpecDef.polymorphism != nil || typeSpecDef.implementation != nil {
+		// the implementations of a polymorphic interface are defined without being referenced by it
+		parser.cacheRecorder.uncacheable = true
+	}
+
+	recorders := append([]*cacheDependencies{parser.cacheRecorder.dependencies}, parser.typeDependencies...)
+	for _, recorder := range recorders {
+		recorder.files[fileInfo.Path] = struct{}{}
+
+		// the instances of the generic types aren't declared, their names derive from the ones of their arguments
+		if key := cacheTypeKey(typeSpecDef); parser.cache.typeNames[key] != "" {
+			recorder.types[key] = typeSpecDef.TypeName()
+		}
+
+		// the enum values may be declared in other files
+		for _, enumValue := range typeSpecDef.Enums {
+			if enumFileInfo, ok := parser.packages.files[enumValue.file]; ok {
+				recorder.files[enumFileInfo.Path] = struct{}{}
+			}
+		}
+
+		// so may the marshaling method
+		if typeSpecDef.marshaler != nil {
+			if methodFileInfo, ok := parser.packages.files[typeSpecDef.marshaler.file]; ok {
+				recorder.files[methodFileInfo.Path] = struct{}{}
+			}
+		}
+
+		if dependencies, ok := parser.definitionDependencies[typeSpecDef]; ok {
+			recorder.add(dependencies)
+		}
+	}
+}
+
+// beginDefinitionDependencies starts recording the dependencies of a definition.
+func (parser *Parser) beginDefinitionDependencies() {
+	if parser.cacheRecorder != nil {
+		parser.typeDependencies = append(parser.typeDependencies, newCacheDependencies())
+	}
+}
+
+// endDefinitionDependencies stops recording the dependencies of a definition and keeps them, so that they're
+// recorded as well when the parsed definition is used again.
+func (parser *Parser) endDefinitionDependencies(typeSpecDef *TypeSpecDef) {
+	if parser.cacheRecorder == nil || len(parser.typeDependencies) == 0 {
+		return
+	}
+
+	last := len(parser.typeDependencies) - 1
+	parser.definitionDependencies[typeSpecDef] = parser.typeDependencies[last]
+	parser.typeDependencies = parser.typeDependencies[:last]
+}
+
+// recordOperation records a parsed operation, unless it depends on a markdown or a code example file.
+func (parser *Parser) recordOperation(operation *Operation, comments []*ast.Comment) {
+	for _, comment := range comments {
+		if attribute, _ := commentAttribute(comment.Text); attribute == descriptionMarkdownAttr || attribute == xCodeSamplesAttr {
+			parser.cacheRecorder.uncacheable = true
+
+			return
+		}
+	}
+
+	if len(operation.RouterProperties) > 0 {
+		parser.cacheRecorder.operations = append(parser.cacheRecorder.operations, parseCacheOperation{
+			Routes:    operation.RouterProperties,
+			Operation: operation.Operation,
+			Positions: operation.positions,
+		})
+	}
+}
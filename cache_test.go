@@ -0,0 +1,192 @@
+package swag
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCacheModule writes a module whose operation uses a type declared in another package.
+func writeCacheModule(t *testing.T, userFields string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/cache\n\ngo 1.18\n",
+		"main.go": `package main
+
+// @title Swagger Example API
+// @version 1.0
+func main() {}
+`,
+		"api/api.go": `package api
+
+import "example.com/cache/model"
+
+// GetUser
+// @Summary Get a user
+// @Param id path int true "User ID"
+// @Success 200 {object} model.User
+// @Router /users/{id} [get]
+func GetUser() {}
+`,
+		"model/model.go": `package model
+
+type User struct {
+` + userFields + `
+	Address Address ` + "`json:\"address\"`" + `
+}
+
+type Address struct {
+	City string ` + "`json:\"city\"`" + `
+}
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func parseWithCache(t *testing.T, searchDir, cacheDir string) (*Parser, string) {
+	t.Helper()
+
+	p := New(SetCacheDir(cacheDir))
+	require.NoError(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+
+	data, err := json.MarshalIndent(p.swagger, "", "  ")
+	require.NoError(t, err)
+
+	return p, string(data)
+}
+
+func TestParser_CacheDir(t *testing.T) {
+	t.Parallel()
+
+	searchDir := writeCacheModule(t, "\tName string `json:\"name\"`")
+	cacheDir := t.TempDir()
+
+	uncached := New()
+	require.NoError(t, uncached.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+	expected, err := json.MarshalIndent(uncached.swagger, "", "  ")
+	require.NoError(t, err)
+
+	first, actual := parseWithCache(t, searchDir, cacheDir)
+	assert.True(t, first.typesParsed)
+	assert.JSONEq(t, string(expected), actual)
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	second, actual := parseWithCache(t, searchDir, cacheDir)
+	assert.False(t, second.typesParsed)
+	assert.JSONEq(t, string(expected), actual)
+	assert.Contains(t, second.swagger.Definitions, "model.Address")
+}
+
+func TestParser_CacheFingerprintTags(t *testing.T) {
+	t.Parallel()
+
+	expected := New(SetTags("pets,users,orders,stores")).cacheFingerprint()
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, expected, New(SetTags("stores,orders,users,pets")).cacheFingerprint())
+	}
+}
+
+func TestParser_CacheDirDependencyChanged(t *testing.T) {
+	t.Parallel()
+
+	searchDir := writeCacheModule(t, "\tName string `json:\"name\"`")
+	cacheDir := t.TempDir()
+
+	_, _ = parseWithCache(t, searchDir, cacheDir)
+
+	model := filepath.Join(searchDir, "model", "model.go")
+	data, err := os.ReadFile(model)
+	require.NoError(t, err)
+	data = []byte(strings.Replace(string(data), "Name string `json:\"name\"`", "FullName string `json:\"fullName\"`", 1))
+	require.NoError(t, os.WriteFile(model, data, 0644))
+
+	p, _ := parseWithCache(t, searchDir, cacheDir)
+	assert.True(t, p.typesParsed)
+	assert.Contains(t, p.swagger.Definitions["model.User"].Properties, "fullName")
+	assert.NotContains(t, p.swagger.Definitions["model.User"].Properties, "name")
+}
+
+func TestParser_CacheDirDefinitionNameChanged(t *testing.T) {
+	t.Parallel()
+
+	searchDir := writeCacheModule(t, "\tName string `json:\"name\"`")
+	cacheDir := t.TempDir()
+
+	_, _ = parseWithCache(t, searchDir, cacheDir)
+
+	// model.User isn't unique anymore, its definition is named after its package path
+	other := filepath.Join(searchDir, "other", "model", "model.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(other), 0755))
+	require.NoError(t, os.WriteFile(other, []byte("package model\n\ntype User struct {\n\tID int `json:\"id\"`\n}\n"), 0644))
+
+	uncached := New()
+	require.NoError(t, uncached.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+	expected, err := json.MarshalIndent(uncached.swagger, "", "  ")
+	require.NoError(t, err)
+	require.Contains(t, uncached.swagger.Definitions, "example_com_cache_model.User")
+
+	p, actual := parseWithCache(t, searchDir, cacheDir)
+	assert.True(t, p.typesParsed)
+	assert.JSONEq(t, string(expected), actual)
+}
+
+func TestParser_CacheDirUncacheable(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+
+	var output bytes.Buffer
+
+	p := New(SetCacheDir(cacheDir), SetLint(true), SetDebugger(log.New(&output, "", 0)))
+	require.NoError(t, p.ParseAPI("testdata/lint", mainAPIFile, defaultParseDepth))
+	assert.Nil(t, p.cache)
+	assert.Contains(t, output.String(), "warning: the parse cache is disabled")
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestParser_CacheDirPositions(t *testing.T) {
+	t.Parallel()
+
+	searchDir := writeCacheModule(t, "\tName string `json:\"name\"`")
+	cacheDir := t.TempDir()
+
+	api := filepath.Join(searchDir, "api", "api.go")
+	data, err := os.ReadFile(api)
+	require.NoError(t, err)
+	data = []byte(strings.Replace(string(data), "@Param id path", "@Param userId path", 1))
+	require.NoError(t, os.WriteFile(api, data, 0644))
+
+	expected := api + ":7:4: path parameter 'userId' isn't a placeholder of GET /users/{id}"
+
+	for i := 0; i < 2; i++ {
+		var output bytes.Buffer
+
+		p := New(SetCacheDir(cacheDir), SetDebugger(log.New(&output, "", 0)))
+		require.NoError(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+		assert.Equal(t, i == 0, p.typesParsed)
+		assert.Contains(t, output.String(), expected)
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/urfave/cli/v2"
 
@@ -43,6 +46,18 @@ const (
 	packagePrefixFlag        = "packagePrefix"
 	stateFlag                = "state"
 	parseFuncBodyFlag        = "parseFuncBody"
+	asyncAPIVersionsFlag     = "asyncAPIVersions"
+	openAPIVersionFlag       = "openAPIVersion"
+	lintFormatFlag           = "format"
+	collectErrorsFlag        = "collectErrors"
+	inferRoutesFlag          = "inferRoutes"
+	inferSchemasFlag         = "inferSchemas"
+	cacheDirFlag             = "cacheDir"
+	parseWorkersFlag         = "parseWorkers"
+	watchFlag                = "watch"
+	checkFlag                = "check"
+	diffFlag                 = "diff"
+	canonicalFlag            = "canonical"
 )
 
 var initFlags = []cli.Flag{
@@ -83,7 +98,7 @@ var initFlags = []cli.Flag{
 		Name:    outputTypesFlag,
 		Aliases: []string{"ot"},
 		Value:   "go,json,yaml",
-		Usage:   "Output types of generated files (docs.go, swagger.json, swagger.yaml) like go,json,yaml",
+		Usage:   "Output types of generated files (docs.go, swagger.json, swagger.yaml, asyncapi.go) like go,json,yaml,async",
 	},
 	&cli.BoolFlag{
 		Name:  parseVendorFlag,
@@ -186,15 +201,75 @@ var initFlags = []cli.Flag{
 		// Value: false,
 		Usage: "Parse API info within body of functions in go files, disabled by default (default: false)",
 	},
+	&cli.StringFlag{
+		Name:    asyncAPIVersionsFlag,
+		Aliases: []string{"av"},
+		Value:   "2.4.0",
+		Usage:   "AsyncAPI specification versions to generate (asyncapi.yaml, asyncapi_v3.yaml) like 2.4.0,3.0.0",
+	},
+	&cli.BoolFlag{
+		Name:    collectErrorsFlag,
+		Aliases: []string{"collect-errors"},
+		Usage:   "Keep parsing after an invalid annotation and report all the errors as file:line:column: message",
+	},
+	&cli.BoolFlag{
+		Name:    inferRoutesFlag,
+		Aliases: []string{"infer-routes"},
+		Usage:   "Bind the routes registered with gin, echo, chi or net/http routers to the handlers without @Router",
+	},
+	&cli.BoolFlag{
+		Name:    inferSchemasFlag,
+		Aliases: []string{"infer-schemas"},
+		Usage:   "Infer the request body and the responses of the handlers from their code, the annotations win",
+	},
+	&cli.StringFlag{
+		Name:    cacheDirFlag,
+		Aliases: []string{"cache-dir"},
+		Usage:   "Directory where the operations and the definitions parsed from every file are cached, by content hash and swag version",
+	},
+	&cli.IntFlag{
+		Name:    parseWorkersFlag,
+		Aliases: []string{"parse-workers"},
+		Usage:   "Number of workers parsing the Go source files and collecting their operations concurrently, the number of CPUs when 0",
+	},
+	&cli.BoolFlag{
+		Name:    watchFlag,
+		Aliases: []string{"w"},
+		Usage:   "Watch the search dirs and generate the docs again when the Go files change",
+	},
+	&cli.StringFlag{
+		Name:    openAPIVersionFlag,
+		Aliases: []string{"openapi-version", "ov"},
+		Value:   "2.0",
+		Usage:   "OpenAPI specification version of the json and yaml output types: 2.0 (swagger.json, swagger.yaml) or 3.1.0 (openapi.json, openapi.yaml), docs.go always embeds the 2.0 document",
+	},
 }
 
 func initAction(ctx *cli.Context) error {
+	config, err := configFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(watchFlag) {
+		watchCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return gen.New().Watch(watchCtx, config)
+	}
+
+	return gen.New().Build(config)
+}
+
+// configFromContext builds the generator config from the flags of a command. The flags a command doesn't define
+// keep their zero value.
+func configFromContext(ctx *cli.Context) (*gen.Config, error) {
 	strategy := ctx.String(propertyStrategyFlag)
 
 	switch strategy {
 	case swag.CamelCase, swag.SnakeCase, swag.PascalCase:
 	default:
-		return fmt.Errorf("not supported %s propertyStrategy", strategy)
+		return nil, fmt.Errorf("not supported %s propertyStrategy", strategy)
 	}
 
 	leftDelim, rightDelim := "{{", "}}"
@@ -202,11 +277,11 @@ func initAction(ctx *cli.Context) error {
 	if ctx.IsSet(templateDelimsFlag) {
 		delims := strings.Split(ctx.String(templateDelimsFlag), ",")
 		if len(delims) != 2 {
-			return fmt.Errorf(
+			return nil, fmt.Errorf(
 				"exactly two template delimiters must be provided, comma separated",
 			)
 		} else if delims[0] == delims[1] {
-			return fmt.Errorf("template delimiters must be different")
+			return nil, fmt.Errorf("template delimiters must be different")
 		}
 		leftDelim, rightDelim = strings.TrimSpace(
 			delims[0],
@@ -217,7 +292,7 @@ func initAction(ctx *cli.Context) error {
 
 	outputTypes := strings.Split(ctx.String(outputTypesFlag), ",")
 	if len(outputTypes) == 0 {
-		return fmt.Errorf("no output types specified")
+		return nil, fmt.Errorf("no output types specified")
 	}
 	logger := log.New(os.Stdout, "", log.LstdFlags)
 	if ctx.Bool(quietFlag) {
@@ -228,19 +303,26 @@ func initAction(ctx *cli.Context) error {
 		ctx.String(collectionFormatFlag),
 	)
 	if collectionFormat == "" {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"not supported %s collectionFormat",
 			ctx.String(collectionFormat),
 		)
 	}
 
+	var asyncAPIVersions []string
+	for _, version := range strings.Split(ctx.String(asyncAPIVersionsFlag), ",") {
+		if version = strings.TrimSpace(version); version != "" {
+			asyncAPIVersions = append(asyncAPIVersions, version)
+		}
+	}
+
 	var pdv = ctx.Int(parseDependencyLevelFlag)
 	if pdv == 0 {
 		if ctx.Bool(parseDependencyFlag) {
 			pdv = 1
 		}
 	}
-	return gen.New().Build(&gen.Config{
+	config := &gen.Config{
 		SearchDir:           ctx.String(searchDirFlag),
 		Excludes:            ctx.String(excludeFlag),
 		ParseExtension:      ctx.String(parseExtensionFlag),
@@ -268,7 +350,90 @@ func initAction(ctx *cli.Context) error {
 		PackagePrefix:       ctx.String(packagePrefixFlag),
 		State:               ctx.String(stateFlag),
 		ParseFuncBody:       ctx.Bool(parseFuncBodyFlag),
-	})
+		AsyncAPIVersions:    asyncAPIVersions,
+		OpenAPIVersion:      ctx.String(openAPIVersionFlag),
+		CollectErrors:       ctx.Bool(collectErrorsFlag),
+		InferRoutes:         ctx.Bool(inferRoutesFlag),
+		InferSchemas:        ctx.Bool(inferSchemasFlag),
+		CacheDir:            ctx.String(cacheDirFlag),
+		ParseWorkers:        ctx.Int(parseWorkersFlag),
+	}
+
+	return config, nil
+}
+
+// lintFlags are the init flags used to parse the annotations and the output format of the diagnostics.
+var lintFlags = append(selectFlags(initFlags,
+	searchDirFlag, excludeFlag, generalInfoFlag, propertyStrategyFlag, parseVendorFlag, parseDependencyLevelFlag,
+	parseDependencyFlag, markdownFilesFlag, codeExampleFilesFlag, parseInternalFlag, parseDepthFlag,
+	requiredByDefaultFlag, overridesFileFlag, parseGoListFlag, parseExtensionFlag, tagsFlag, collectionFormatFlag,
+	packagePrefixFlag, stateFlag, parseFuncBodyFlag, inferRoutesFlag,
+	inferSchemasFlag, parseWorkersFlag,
+), &cli.StringFlag{
+	Name:    lintFormatFlag,
+	Aliases: []string{"f"},
+	Value:   gen.LintFormatText,
+	Usage:   "Output format of the diagnostics: text, json or sarif",
+})
+
+func selectFlags(flags []cli.Flag, names ...string) []cli.Flag {
+	var selected []cli.Flag
+
+	for _, flag := range flags {
+		for _, name := range names {
+			if flag.Names()[0] == name {
+				selected = append(selected, flag)
+			}
+		}
+	}
+
+	return selected
+}
+
+func lintAction(ctx *cli.Context) error {
+	config, err := configFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The diagnostics are the output of lint, the logs of the parser would garble the json and sarif formats.
+	config.Debugger = log.New(io.Discard, "", log.LstdFlags)
+
+	diagnostics, err := gen.New().Lint(config)
+	if err != nil {
+		return err
+	}
+
+	if err := gen.WriteDiagnostics(os.Stdout, diagnostics, ctx.String(lintFormatFlag)); err != nil {
+		return err
+	}
+
+	if gen.HasLintErrors(diagnostics) {
+		return fmt.Errorf("lint errors found")
+	}
+
+	return nil
+}
+
+func diffAction(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("exactly two spec files must be provided: old and new")
+	}
+
+	changes, err := gen.DiffFiles(ctx.Args().Get(0), ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+
+	if gen.HasBreakingChanges(changes) {
+		return fmt.Errorf("breaking changes found")
+	}
+
+	return nil
 }
 
 func main() {
@@ -289,18 +454,22 @@ func main() {
 			Usage:   "format swag comments",
 			Action: func(c *cli.Context) error {
 
+				formatter := format.New(swag.SetCanonical(c.Bool(canonicalFlag)))
+
 				if c.Bool(pipeFlag) {
-					return format.New().Run(os.Stdin, os.Stdout)
+					return formatter.Run(os.Stdin, os.Stdout)
 				}
 
 				searchDir := c.String(searchDirFlag)
 				excludeDir := c.String(excludeFlag)
 				mainFile := c.String(generalInfoFlag)
 
-				return format.New().Build(&format.Config{
+				return formatter.Build(&format.Config{
 					SearchDir: searchDir,
 					Excludes:  excludeDir,
 					MainFile:  mainFile,
+					Check:     c.Bool(checkFlag),
+					Diff:      c.Bool(diffFlag),
 				})
 			},
 			Flags: []cli.Flag{
@@ -326,8 +495,32 @@ func main() {
 					Value:   false,
 					Usage:   "Read from stdin, write to stdout.",
 				},
+				&cli.BoolFlag{
+					Name:  checkFlag,
+					Usage: "List the files which are not formatted without writing them, fail if there are some",
+				},
+				&cli.BoolFlag{
+					Name:  diffFlag,
+					Usage: "Print the diffs of the files which are not formatted without writing them",
+				},
+				&cli.BoolFlag{
+					Name:  canonicalFlag,
+					Usage: "Reorder the attributes of the operations, normalize their casing and expand the MIME type aliases",
+				},
 			},
 		},
+		{
+			Name:      "diff",
+			Usage:     "report the changes between two swagger, openapi, asyncapi or asyncapi_v3 files, fails on breaking changes",
+			ArgsUsage: "old.json new.json",
+			Action:    diffAction,
+		},
+		{
+			Name:   "lint",
+			Usage:  "report the problems of the swag comments without generating the docs, fails on errors",
+			Action: lintAction,
+			Flags:  lintFlags,
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -1,14 +1,161 @@
 package swag
 
+import (
+	"go/ast"
+	"sort"
+)
+
 const (
 	enumVarNamesExtension     = "x-enum-varnames"
 	enumCommentsExtension     = "x-enum-comments"
 	enumDescriptionsExtension = "x-enum-descriptions"
 )
 
+// enumValuesMethod is the method listing the enum values of its receiver type, like
+// `func (Level) EnumValues() []Level { return []Level{Debug, Info} }`.
+const enumValuesMethod = "EnumValues"
+
 // EnumValue a model to record an enum consts variable
 type EnumValue struct {
 	key     string
 	Value   interface{}
 	Comment string
+
+	// file declaring the value, nil for a literal
+	file *ast.File
+}
+
+// collectMethodEnums replaces the enum values of the types declaring an EnumValues method by the values it returns,
+// in their order. The consts keep their name and comment, the literals are named after the const of the same
+// value if there is one.
+func (pkgDefs *PackagesDefinitions) collectMethodEnums(parsedSchemas map[*TypeSpecDef]*Schema) {
+	sortedFiles := make([]*AstFileInfo, 0, len(pkgDefs.files))
+	for _, info := range pkgDefs.files {
+		sortedFiles = append(sortedFiles, info)
+	}
+	sort.Slice(sortedFiles, func(i, j int) bool {
+		return sortedFiles[i].Path < sortedFiles[j].Path
+	})
+
+	for _, info := range sortedFiles {
+		pkg, ok := pkgDefs.packages[info.PackagePath]
+		if !ok {
+			continue
+		}
+		for _, decl := range info.File.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Name.Name != enumValuesMethod {
+				continue
+			}
+			typeDef, ok := pkg.TypeDefinitions[receiverTypeName(funcDecl)]
+			if !ok {
+				continue
+			}
+			values := enumValuesOf(funcDecl, typeDef.Name())
+			if values == nil {
+				pkgDefs.debug.Printf("warning: %s.%s must return a literal slice of its type, skipping it", typeDef.TypeName(), enumValuesMethod)
+				continue
+			}
+
+			enums := make([]EnumValue, 0, len(values))
+			for _, expr := range values {
+				enumValue, ok := pkgDefs.evaluateEnumValue(pkg, info.File, typeDef, expr)
+				if !ok {
+					pkgDefs.debug.Printf("warning: cannot evaluate a value of %s.%s, skipping it", typeDef.TypeName(), enumValuesMethod)
+					enums = nil
+					break
+				}
+				enums = append(enums, enumValue)
+			}
+			if enums == nil {
+				continue
+			}
+
+			// delete it from parsed schemas, and will parse it again
+			delete(parsedSchemas, typeDef)
+			typeDef.Enums = enums
+		}
+	}
+}
+
+// enumValuesOf returns the elements of the slice literal returned by an EnumValues method, nil if the method doesn't
+// return a literal slice of typeName.
+func enumValuesOf(funcDecl *ast.FuncDecl, typeName string) []ast.Expr {
+	funcType := funcDecl.Type
+	if funcDecl.Body == nil || len(funcDecl.Body.List) == 0 || funcType.Params.NumFields() != 0 || funcType.Results.NumFields() != 1 {
+		return nil
+	}
+	if !isSliceOf(funcType.Results.List[0].Type, typeName) {
+		return nil
+	}
+	returnStmt, ok := funcDecl.Body.List[len(funcDecl.Body.List)-1].(*ast.ReturnStmt)
+	if !ok || len(returnStmt.Results) != 1 {
+		return nil
+	}
+	literal, ok := returnStmt.Results[0].(*ast.CompositeLit)
+	if !ok || !isSliceOf(literal.Type, typeName) {
+		return nil
+	}
+	for _, elt := range literal.Elts {
+		if _, ok := elt.(*ast.KeyValueExpr); ok {
+			return nil
+		}
+	}
+	if literal.Elts == nil {
+		return []ast.Expr{}
+	}
+	return literal.Elts
+}
+
+func isSliceOf(expr ast.Expr, typeName string) bool {
+	arrayType, ok := expr.(*ast.ArrayType)
+	if !ok || arrayType.Len != nil {
+		return false
+	}
+	ident, ok := arrayType.Elt.(*ast.Ident)
+	return ok && ident.Name == typeName
+}
+
+// evaluateEnumValue evaluates a value returned by an EnumValues method, a const of any package or a literal.
+func (pkgDefs *PackagesDefinitions) evaluateEnumValue(pkg *PackageDefinitions, file *ast.File, typeDef *TypeSpecDef, expr ast.Expr) (EnumValue, bool) {
+	var constVar *ConstVariable
+	constPkg := pkg
+	switch valueExpr := expr.(type) {
+	case *ast.Ident:
+		constVar = pkg.ConstTable[valueExpr.Name]
+	case *ast.SelectorExpr:
+		if pkgIdent, ok := valueExpr.X.(*ast.Ident); ok {
+			pkgPaths, _ := pkgDefs.findPackagePathFromImports(pkgIdent.Name, file)
+			for _, pkgPath := range pkgPaths {
+				if otherPkg, ok := pkgDefs.packages[pkgPath]; ok && otherPkg.ConstTable[valueExpr.Sel.Name] != nil {
+					constPkg, constVar = otherPkg, otherPkg.ConstTable[valueExpr.Sel.Name]
+					break
+				}
+			}
+		}
+	}
+
+	if constVar != nil {
+		value, _ := pkgDefs.EvaluateConstValue(constPkg, constVar, nil)
+		if _, ok := value.(ast.Expr); ok || value == nil {
+			return EnumValue{}, false
+		}
+		return EnumValue{
+			key:     constVar.Name.Name,
+			Value:   value,
+			Comment: constComment(constVar),
+			file:    constVar.File,
+		}, true
+	}
+
+	value, _ := pkg.evaluateConstValue(file, 0, expr, pkgDefs, make(map[string]struct{}))
+	if _, ok := value.(ast.Expr); ok || value == nil {
+		return EnumValue{}, false
+	}
+	for _, enumValue := range typeDef.Enums {
+		if enumValue.Value == value {
+			return enumValue, true
+		}
+	}
+	return EnumValue{Value: value}, true
 }
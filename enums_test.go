@@ -2,6 +2,7 @@ package swag
 
 import (
 	"encoding/json"
+	"go/ast"
 	"math/bits"
 	"os"
 	"path/filepath"
@@ -32,3 +33,44 @@ func TestParseGlobalEnums(t *testing.T) {
 	assert.Equal(t, 1_000_000, p.packages.packages[constsPath].ConstTable["underscored"].Value)
 	assert.Equal(t, 0b10001000, p.packages.packages[constsPath].ConstTable["binaryInteger"].Value)
 }
+
+func TestParseEnumsAcrossPackages(t *testing.T) {
+	searchDir := "testdata/enums_packages"
+	expected, err := os.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+
+	p := New()
+	err = p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	b, err := json.MarshalIndent(p.swagger, "", "    ")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(b))
+}
+
+func TestEnumValuesOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		want   int
+	}{
+		{"consts and literals", `func (Level) EnumValues() []Level { return []Level{Debug, "error"} }`, 2},
+		{"empty", `func (Level) EnumValues() []Level { return []Level{} }`, 0},
+		{"other type", `func (Level) EnumValues() []string { return []string{"debug"} }`, -1},
+		{"not a literal", `func (Level) EnumValues() []Level { return levels }`, -1},
+		{"parameters", `func (Level) EnumValues(all bool) []Level { return []Level{Debug} }`, -1},
+		{"keyed elements", `func (Level) EnumValues() []Level { return []Level{0: Debug} }`, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, file, err := parseGoFile("level.go", "package level\n"+tt.method)
+			assert.NoError(t, err)
+			values := enumValuesOf(file.Decls[0].(*ast.FuncDecl), "Level")
+			if tt.want < 0 {
+				assert.Nil(t, values)
+			} else {
+				assert.Len(t, values, tt.want)
+				assert.NotNil(t, values)
+			}
+		})
+	}
+}
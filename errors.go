@@ -0,0 +1,82 @@
+package swag
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// ParseError is an error of an annotation, located at its comment.
+type ParseError struct {
+	Pos token.Position
+	Err error
+}
+
+// Error returns the error as file:line:column: message, so that editors can jump to the comment.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Err)
+}
+
+// Unwrap returns the error of the annotation.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// errorPosition returns the position and the error of a ParseError, like the errors found once a whole block is
+// parsed, or the given position and the error otherwise.
+func errorPosition(err error, position token.Position) (token.Position, error) {
+	var parseError *ParseError
+	if errors.As(err, &parseError) {
+		return parseError.Pos, parseError.Err
+	}
+
+	return position, err
+}
+
+// ParseErrors are the errors collected when the parser keeps going after an invalid annotation, see
+// SetCollectErrors.
+type ParseErrors []error
+
+// Error returns the errors, one per line.
+func (errs ParseErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the collected errors.
+func (errs ParseErrors) Unwrap() []error {
+	return errs
+}
+
+// SetCollectErrors sets whether the parser keeps going after an invalid annotation and returns all the errors,
+// as ParseErrors, instead of stopping at the first one.
+func SetCollectErrors(collectErrors bool) func(*Parser) {
+	return func(p *Parser) {
+		p.collectErrors = collectErrors
+	}
+}
+
+// reportError returns the error, or collects it and returns nil when the parser keeps going after errors.
+func (parser *Parser) reportError(err error) error {
+	if !parser.collectErrors {
+		return err
+	}
+
+	parser.parseErrors = append(parser.parseErrors, err)
+
+	return nil
+}
+
+// collectedErrors returns the collected errors, nil if there is none.
+func (parser *Parser) collectedErrors() error {
+	if len(parser.parseErrors) == 0 {
+		return nil
+	}
+
+	return ParseErrors(parser.parseErrors)
+}
@@ -0,0 +1,44 @@
+package swag
+
+import (
+	"errors"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseError(t *testing.T) {
+	cause := errors.New("missing required param comment parameters \"id path\"")
+	err := &ParseError{Pos: token.Position{Filename: "api/api.go", Line: 9, Column: 4}, Err: cause}
+
+	assert.EqualError(t, err, "api/api.go:9:4: missing required param comment parameters \"id path\"")
+	assert.True(t, errors.Is(err, cause))
+}
+
+func TestParseErrors(t *testing.T) {
+	err := ParseErrors{errors.New("api.go:9:4: first"), errors.New("api.go:17:4: second")}
+
+	assert.EqualError(t, err, "api.go:9:4: first\napi.go:17:4: second")
+	assert.Len(t, err.Unwrap(), 2)
+}
+
+func TestParser_ParseErrorPosition(t *testing.T) {
+	err := New().ParseAPI("testdata/parse_errors", mainAPIFile, defaultParseDepth)
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.EqualError(t, err, "testdata/parse_errors/api/api.go:9:4: missing required param comment parameters \"id path\"")
+}
+
+func TestParser_CollectErrors(t *testing.T) {
+	err := New(SetCollectErrors(true)).ParseAPI("testdata/parse_errors", mainAPIFile, defaultParseDepth)
+
+	var parseErrs ParseErrors
+	require.True(t, errors.As(err, &parseErrs))
+	require.Len(t, parseErrs, 3)
+	assert.EqualError(t, parseErrs[0], "testdata/parse_errors/api/api.go:9:4: missing required param comment parameters \"id path\"")
+	assert.EqualError(t, parseErrs[1], "testdata/parse_errors/api/api.go:17:4: cannot find type definition: Unknown")
+	assert.EqualError(t, parseErrs[2], "testdata/parse_errors/api/api.go:23:4: missing required param comment parameters \"broker\"")
+}
@@ -20,6 +20,10 @@ const (
 	optionalLabel    = "optional"
 	swaggerTypeTag   = "swaggertype"
 	swaggerIgnoreTag = "swaggerignore"
+
+	// json tag options
+	inlineOption   = "inline"
+	omitZeroOption = "omitzero"
 )
 
 type tagBaseFieldParser struct {
@@ -66,6 +70,11 @@ func (ps *tagBaseFieldParser) ShouldSkip() bool {
 }
 
 func (ps *tagBaseFieldParser) FieldNames() ([]string, error) {
+	// the properties of an inlined field are the ones of the struct, like for an embedded field
+	if _, isMap := ps.field.Type.(*ast.MapType); ps.hasJSONOption(inlineOption) && !isMap {
+		return nil, nil
+	}
+
 	if len(ps.field.Names) <= 1 {
 		// if embedded but with a json/form name ??
 		if ps.field.Tag != nil {
@@ -99,6 +108,22 @@ func (ps *tagBaseFieldParser) FieldNames() ([]string, error) {
 	return names, nil
 }
 
+// hasJSONOption reports whether the json tag has an option, like `json:",inline"`.
+func (ps *tagBaseFieldParser) hasJSONOption(option string) bool {
+	if ps.field.Tag == nil {
+		return false
+	}
+
+	options := strings.Split(ps.tag.Get(jsonTag), ",")
+	for _, value := range options[1:] {
+		if strings.TrimSpace(value) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ps *tagBaseFieldParser) firstTagValue(tag string) string {
 	if ps.field.Tag != nil {
 		return strings.TrimRight(strings.TrimSpace(strings.Split(ps.tag.Get(tag), ",")[0]), "[]")
@@ -416,7 +441,8 @@ func (ps *tagBaseFieldParser) complementSchema(schema *spec.Schema, types []stri
 
 	schema.Example = field.exampleValue
 
-	if field.schemaType != ARRAY {
+	// the string types keep their format, like the @format of a marshaler type, unless the field sets one
+	if field.schemaType != ARRAY && (field.formatType != "" || field.schemaType != STRING) {
 		schema.Format = field.formatType
 	}
 	schema.Title = field.title
@@ -533,6 +559,11 @@ func (ps *tagBaseFieldParser) IsRequired() (bool, error) {
 		}
 	}
 
+	// a zero value is omitted
+	if ps.hasJSONOption(omitZeroOption) {
+		return false, nil
+	}
+
 	return ps.p.RequiredByDefault, nil
 }
 
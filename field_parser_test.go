@@ -138,6 +138,56 @@ func TestDefaultFieldParser(t *testing.T) {
 		assert.False(t, got)
 	})
 
+	t.Run("Omitzero option", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := newTagBaseFieldParser(
+			&Parser{
+				RequiredByDefault: true,
+			},
+			&ast.Field{Tag: &ast.BasicLit{
+				Value: `json:"test,omitzero"`,
+			}},
+		).IsRequired()
+		assert.NoError(t, err)
+		assert.False(t, got)
+
+		got, err = newTagBaseFieldParser(
+			&Parser{},
+			&ast.Field{Tag: &ast.BasicLit{
+				Value: `json:"test,omitzero" binding:"required"`,
+			}},
+		).IsRequired()
+		assert.NoError(t, err)
+		assert.True(t, got)
+	})
+
+	t.Run("Inline option", func(t *testing.T) {
+		t.Parallel()
+
+		names, err := newTagBaseFieldParser(
+			&Parser{},
+			&ast.Field{
+				Names: []*ast.Ident{{Name: "Meta"}},
+				Type:  &ast.Ident{Name: "Meta"},
+				Tag:   &ast.BasicLit{Value: `json:",inline"`},
+			},
+		).FieldNames()
+		assert.NoError(t, err)
+		assert.Empty(t, names)
+
+		names, err = newTagBaseFieldParser(
+			&Parser{},
+			&ast.Field{
+				Names: []*ast.Ident{{Name: "Extra"}},
+				Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "string"}},
+				Tag:   &ast.BasicLit{Value: `json:"extra,inline"`},
+			},
+		).FieldNames()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"extra"}, names)
+	})
+
 	t.Run("Extensions tag", func(t *testing.T) {
 		t.Parallel()
 
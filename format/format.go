@@ -2,15 +2,20 @@ package format
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/yalochat/swag"
 )
 
+// ErrNotFormatted is returned in check mode when some files aren't formatted.
+var ErrNotFormatted = errors.New("swag comments are not formatted")
+
 // Format implements `fmt` command for formatting swag comments in Go source
 // files.
 type Format struct {
@@ -18,13 +23,19 @@ type Format struct {
 
 	// exclude exclude dirs and files in SearchDir
 	exclude map[string]bool
+
+	// config of the current run
+	config *Config
+
+	// unformatted are the files which would change, in check and diff modes
+	unformatted []string
 }
 
-// New creates a new Format instance
-func New() *Format {
+// New creates a new Format instance, the options configure its formatter, like swag.SetCanonical
+func New(options ...func(*swag.Formatter)) *Format {
 	return &Format{
 		exclude:   map[string]bool{},
-		formatter: swag.NewFormatter(),
+		formatter: swag.NewFormatter(options...),
 	}
 }
 
@@ -38,6 +49,15 @@ type Config struct {
 
 	// MainFile (DEPRECATED)
 	MainFile string
+
+	// Check lists the files which would change instead of writing them, and fails if there are some
+	Check bool
+
+	// Diff prints the unified diffs of the files which would change instead of writing them
+	Diff bool
+
+	// Output receives the files and the diffs of the check and diff modes, os.Stdout when nil
+	Output io.Writer
 }
 
 var defaultExcludes = []string{"docs", "vendor"}
@@ -58,12 +78,17 @@ func (f *Format) Build(config *Config) error {
 			f.exclude[filepath.Clean(fi)] = true
 		}
 	}
+	f.config = config
+	f.unformatted = nil
 	for _, searchDir := range searchDirs {
 		err := filepath.Walk(searchDir, f.visit)
 		if err != nil {
 			return err
 		}
 	}
+	if config.Check && len(f.unformatted) > 0 {
+		return fmt.Errorf("fmt: %w in %d files", ErrNotFormatted, len(f.unformatted))
+	}
 	return nil
 }
 
@@ -107,9 +132,41 @@ func (f *Format) format(path string) error {
 		// Skip write if no change
 		return nil
 	}
+	if f.config.Check || f.config.Diff {
+		return f.report(path, original, formatted)
+	}
 	return write(path, formatted)
 }
 
+// report prints the file which would change in check mode, and its diff in diff mode.
+func (f *Format) report(path string, original, formatted []byte) error {
+	f.unformatted = append(f.unformatted, path)
+	output := f.config.Output
+	if output == nil {
+		output = os.Stdout
+	}
+	if f.config.Check {
+		if _, err := fmt.Fprintln(output, path); err != nil {
+			return err
+		}
+	}
+	if !f.config.Diff {
+		return nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(original)),
+		B:        difflib.SplitLines(string(formatted)),
+		FromFile: path + ".orig",
+		ToFile:   path,
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(output, diff)
+	return err
+}
+
 func write(path string, contents []byte) error {
 	originalFileInfo, err := os.Stat(path)
 	if err != nil {
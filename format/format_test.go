@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/yalochat/swag"
 )
 
 func TestFormat_Format(t *testing.T) {
@@ -74,6 +75,60 @@ func TestFormat_WriteError(t *testing.T) {
 	os.Chmod(fx.basedir, 0755)
 }
 
+func TestFormat_Check(t *testing.T) {
+	fx := setup(t)
+	output := &bytes.Buffer{}
+	err := New().Build(&Config{SearchDir: fx.basedir, Check: true, Output: output})
+	assert.ErrorIs(t, err, ErrNotFormatted)
+	assert.False(t, fx.isFormatted("main.go"))
+	assert.False(t, fx.isFormatted("api/api.go"))
+	assert.Equal(t, filepath.Join(fx.basedir, "api/api.go")+"\n"+filepath.Join(fx.basedir, "main.go")+"\n", output.String())
+
+	assert.NoError(t, New().Build(&Config{SearchDir: fx.basedir}))
+	output.Reset()
+	assert.NoError(t, New().Build(&Config{SearchDir: fx.basedir, Check: true, Output: output}))
+	assert.Empty(t, output.String())
+}
+
+func TestFormat_Diff(t *testing.T) {
+	fx := setup(t)
+	output := &bytes.Buffer{}
+	assert.NoError(t, New().Build(&Config{
+		SearchDir: fx.basedir,
+		Excludes:  filepath.Join(fx.basedir, "api"),
+		Diff:      true,
+		Output:    output,
+	}))
+	assert.False(t, fx.isFormatted("main.go"))
+
+	path := filepath.Join(fx.basedir, "main.go")
+	diff := output.String()
+	assert.Contains(t, diff, "--- "+path+".orig\n+++ "+path+"\n")
+	assert.Contains(t, diff, "\n-		// @title Swagger Example API\n")
+	assert.Contains(t, diff, "\n+// @title		Swagger Example API\n")
+}
+
+func TestFormat_Canonical(t *testing.T) {
+	fx := setup(t)
+	path := filepath.Join(fx.basedir, "api/canonical.go")
+	assert.NoError(t, os.WriteFile(path, []byte(`package api
+
+		// @router /pets [post]
+		// @accept json
+		// @summary Add a new pet to the store
+		func AddPet() {}`), 0644))
+	assert.NoError(t, New(swag.SetCanonical(true)).Build(&Config{SearchDir: fx.basedir}))
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `package api
+
+// @Summary	Add a new pet to the store
+// @Accept		application/json
+// @Router		/pets [post]
+func AddPet() {}
+`, string(contents))
+}
+
 func TestFormat_InvalidSearchDir(t *testing.T) {
 	formatter := New()
 	assert.Error(t, formatter.Build(&Config{SearchDir: "no_such_dir"}))
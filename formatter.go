@@ -25,6 +25,16 @@ var specialTagForSplit = map[string]bool{
 	headerAttr:   true,
 }
 
+// Check of the @asyncapi attributes taking positional arguments
+var asyncTagForSplit = map[string]bool{
+	string(serverAttr):               true,
+	string(serverVariableAttr):       true,
+	string(channelAttr):              true,
+	string(channelParamAttr):         true,
+	string(operationAttr):            true,
+	string(messageCorrelationIDAttr): true,
+}
+
 var skipChar = map[byte]byte{
 	'"': '"',
 	'(': ')',
@@ -36,16 +46,31 @@ var skipChar = map[byte]byte{
 type Formatter struct {
 	// debugging output goes here
 	debug Debugger
+
+	// canonical reorders the attributes of the operations and normalizes their casing and MIME types
+	canonical bool
 }
 
 // NewFormatter create a new formatter instance.
-func NewFormatter() *Formatter {
+func NewFormatter(options ...func(*Formatter)) *Formatter {
 	formatter := &Formatter{
 		debug: log.New(os.Stdout, "", log.LstdFlags),
 	}
+	for _, option := range options {
+		option(formatter)
+	}
 	return formatter
 }
 
+// SetCanonical sets the canonical mode of the formatter, which reorders the attributes of every operation comment
+// block in the canonicalOrder, normalizes their casing and expands the MIME type aliases of @Accept and @Produce.
+// The other comment lines stay in place.
+func SetCanonical(canonical bool) func(*Formatter) {
+	return func(f *Formatter) {
+		f.canonical = canonical
+	}
+}
+
 // Format formats swag comments in contents. It uses fileName to report errors
 // that happen during parsing of contents.
 func (f *Formatter) Format(fileName string, contents []byte) ([]byte, error) {
@@ -68,7 +93,7 @@ func (f *Formatter) Format(fileName string, contents []byte) ([]byte, error) {
 	edits := make(edits, 0, maxEdits)
 
 	for _, comment := range ast.Comments {
-		formatFuncDoc(fileSet, comment.List, &edits)
+		formatFuncDoc(fileSet, comment.List, &edits, f.canonical)
 	}
 	formatted, err := imports.Process(fileName, edits.apply(contents), nil)
 	if err != nil {
@@ -103,7 +128,7 @@ func (edits edits) apply(contents []byte) []byte {
 
 // formatFuncDoc reformats the comment lines in commentList, and appends any
 // changes to the edit list.
-func formatFuncDoc(fileSet *token.FileSet, commentList []*ast.Comment, edits *edits) {
+func formatFuncDoc(fileSet *token.FileSet, commentList []*ast.Comment, edits *edits, canonical bool) {
 	// Building the edit list to format a comment block is a two-step process.
 	// First, we iterate over each comment line looking for Swag attributes. In
 	// each one we find, we replace alignment whitespace with a tab character,
@@ -114,16 +139,22 @@ func formatFuncDoc(fileSet *token.FileSet, commentList []*ast.Comment, edits *ed
 	buffer := &bytes.Buffer{}
 	w := tabwriter.NewWriter(buffer, 1, 4, 1, '\t', 0)
 
-	for commentIndex, comment := range commentList {
-		text := comment.Text
-		if attr, body, found := swagComment(text); found {
-			formatted := "//\t" + attr
-			if body != "" {
-				formatted += "\t" + splitComment2(attr, body)
-			}
-			_, _ = fmt.Fprintln(w, formatted)
-			linesToComments[len(linesToComments)] = commentIndex
+	asyncAPI := isAsyncAPIDoc(commentList)
+
+	lines := swagCommentLines(commentList)
+	if canonical && !asyncAPI && isOperationDoc(lines) {
+		canonicalizeOperationDoc(lines)
+	}
+
+	for lineIndex, line := range lines {
+		formatted := "//\t" + line.attr
+		if line.body != "" && asyncAPI {
+			formatted += "\t" + splitAsyncComment(line.attr, line.body)
+		} else if line.body != "" {
+			formatted += "\t" + splitComment2(line.attr, line.body)
 		}
+		_, _ = fmt.Fprintln(w, formatted)
+		linesToComments[lineIndex] = line.commentIndex
 	}
 
 	// Once we've loaded all of the comment lines to be aligned into the tab
@@ -146,27 +177,189 @@ func formatFuncDoc(fileSet *token.FileSet, commentList []*ast.Comment, edits *ed
 	}
 }
 
+// swagCommentLine is a swag comment line of a comment block.
+type swagCommentLine struct {
+	attr         string
+	body         string
+	commentIndex int
+}
+
+func swagCommentLines(commentList []*ast.Comment) []swagCommentLine {
+	var lines []swagCommentLine
+	for commentIndex, comment := range commentList {
+		if attr, body, found := swagComment(comment.Text); found {
+			lines = append(lines, swagCommentLine{attr: attr, body: body, commentIndex: commentIndex})
+		}
+	}
+	return lines
+}
+
+// canonicalOrder is the order of the attributes of an operation in canonical mode, the attributes of a same rank
+// keep their order, so @Header still follows the responses it applies to. The other attributes, like @Deprecated,
+// @State and the extensions, come before @Router.
+var canonicalOrder = map[string]int{
+	summaryAttr:             0,
+	idAttr:                  1,
+	descriptionAttr:         2,
+	descriptionMarkdownAttr: 2,
+	tagsAttr:                3,
+	acceptAttr:              4,
+	produceAttr:             5,
+	paramAttr:               6,
+	successAttr:             7,
+	failureAttr:             7,
+	responseAttr:            7,
+	headerAttr:              7,
+	securityAttr:            8,
+	routerAttr:              10,
+	deprecatedRouterAttr:    10,
+}
+
+// canonicalOtherRank is the rank of the attributes missing from canonicalOrder.
+const canonicalOtherRank = 9
+
+// canonicalCasing is the casing of the attributes of an operation in canonical mode.
+var canonicalCasing = map[string]string{
+	summaryAttr:             "@Summary",
+	idAttr:                  "@ID",
+	descriptionAttr:         "@Description",
+	descriptionMarkdownAttr: "@Description.markdown",
+	tagsAttr:                "@Tags",
+	acceptAttr:              "@Accept",
+	produceAttr:             "@Produce",
+	paramAttr:               "@Param",
+	successAttr:             "@Success",
+	failureAttr:             "@Failure",
+	responseAttr:            "@Response",
+	headerAttr:              "@Header",
+	securityAttr:            "@Security",
+	deprecatedAttr:          "@Deprecated",
+	stateAttr:               "@State",
+	xCodeSamplesAttr:        "@x-codeSamples",
+	routerAttr:              "@Router",
+	deprecatedRouterAttr:    "@DeprecatedRouter",
+}
+
+// operationOnlyAttrs are the attributes which make a comment block an operation, and not the general API info.
+var operationOnlyAttrs = map[string]bool{
+	paramAttr:            true,
+	successAttr:          true,
+	failureAttr:          true,
+	responseAttr:         true,
+	headerAttr:           true,
+	routerAttr:           true,
+	deprecatedRouterAttr: true,
+}
+
+// isOperationDoc reports whether the swag comment lines describe an operation, whose attributes may be reordered.
+func isOperationDoc(lines []swagCommentLine) bool {
+	operation := false
+	for _, line := range lines {
+		attr := strings.ToLower(line.attr)
+		if attr == titleAttr || attr == versionAttr {
+			return false
+		}
+		operation = operation || operationOnlyAttrs[attr]
+	}
+	return operation
+}
+
+// canonicalizeOperationDoc sorts the lines of an operation in the canonicalOrder, normalizes the casing of the
+// attributes and expands the MIME type aliases.
+func canonicalizeOperationDoc(lines []swagCommentLine) {
+	commentIndexes := make([]int, len(lines))
+	for i := range lines {
+		commentIndexes[i] = lines[i].commentIndex
+
+		attr := strings.ToLower(lines[i].attr)
+		if casing, ok := canonicalCasing[attr]; ok {
+			lines[i].attr = casing
+		}
+		if attr == acceptAttr || attr == produceAttr {
+			lines[i].body = canonicalMimeTypes(lines[i].body)
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return canonicalRank(lines[i].attr) < canonicalRank(lines[j].attr)
+	})
+
+	// the sorted lines take the places of the swag comments, the other comments stay in place
+	for i := range lines {
+		lines[i].commentIndex = commentIndexes[i]
+	}
+}
+
+func canonicalRank(attr string) int {
+	if rank, ok := canonicalOrder[strings.ToLower(attr)]; ok {
+		return rank
+	}
+	return canonicalOtherRank
+}
+
+// canonicalMimeTypes expands the MIME type aliases of an @Accept or @Produce list, the unknown types are kept.
+func canonicalMimeTypes(body string) string {
+	mimeTypes := strings.Split(body, ",")
+	for i, mimeType := range mimeTypes {
+		mimeType = strings.TrimSpace(mimeType)
+		if alias, ok := mimeTypeAliases[strings.ToLower(mimeType)]; ok {
+			mimeType = alias
+		}
+		mimeTypes[i] = mimeType
+	}
+	return strings.Join(mimeTypes, ",")
+}
+
+// isAsyncAPIDoc reports whether the comment block is an @asyncapi block.
+func isAsyncAPIDoc(commentList []*ast.Comment) bool {
+	for _, comment := range commentList {
+		if attr, _, found := swagComment(comment.Text); found && strings.ToLower(attr) == asyncAPIAttr {
+			return true
+		}
+	}
+	return false
+}
+
 func splitComment2(attr, body string) string {
 	if specialTagForSplit[strings.ToLower(attr)] {
-		for i := 0; i < len(body); i++ {
-			if skipEnd, ok := skipChar[body[i]]; ok {
-				skipStart, n := body[i], 1
-				for i++; i < len(body); i++ {
-					if skipStart != skipEnd && body[i] == skipStart {
-						n++
-					} else if body[i] == skipEnd {
-						n--
-						if n == 0 {
-							break
-						}
+		return splitColumns(body)
+	}
+	return body
+}
+
+// splitAsyncComment splits the positional arguments of an @asyncapi attribute into columns, the message list of an
+// @operation stays in a single column.
+func splitAsyncComment(attr, body string) string {
+	attr = strings.ToLower(attr)
+	if !asyncTagForSplit[attr] {
+		return body
+	}
+	if attr == string(operationAttr) {
+		body = messageListPattern.ReplaceAllString(body, ",")
+	}
+	return splitColumns(body)
+}
+
+// splitColumns replaces the spaces between the arguments of body with a tab, except within quotes and brackets.
+func splitColumns(body string) string {
+	for i := 0; i < len(body); i++ {
+		if skipEnd, ok := skipChar[body[i]]; ok {
+			skipStart, n := body[i], 1
+			for i++; i < len(body); i++ {
+				if skipStart != skipEnd && body[i] == skipStart {
+					n++
+				} else if body[i] == skipEnd {
+					n--
+					if n == 0 {
+						break
 					}
 				}
-			} else if body[i] == ' ' || body[i] == '\t' {
-				j := i
-				for ; j < len(body) && (body[j] == ' ' || body[j] == '\t'); j++ {
-				}
-				body = replaceRange(body, i, j, "\t")
 			}
+		} else if body[i] == ' ' || body[i] == '\t' {
+			j := i
+			for ; j < len(body) && (body[j] == ' ' || body[j] == '\t'); j++ {
+			}
+			body = replaceRange(body, i, j, "\t")
 		}
 	}
 	return body
@@ -181,6 +181,100 @@ func GetStringByInt(w http.ResponseWriter, r *http.Request) {}
 	testFormat(t, "api.go", contents, want)
 }
 
+func Test_FormatAsyncAPI(t *testing.T) {
+	contents := `package api
+
+// @asyncapi
+// @server myServer mqtt mqtt://broker.hivemq.com
+// @server.description Public HiveMQ broker
+// @server.variable port 1883 Enums(1883, 8883) "Port of the broker"
+// @channel orders myServer "Events of the orders"
+// @channel tenants/{tenantId}/orders myServer "Events of the orders of a tenant"
+// @channel.param tenantId string "Tenant identifier"
+	func ConfigChannels() {}
+
+// @asyncapi
+// @operation send orders OrderCreated, OrderCancelled
+// @message.summary Event emitted for every  order
+// @message.correlationId $message.header#/correlationId "Correlates the message"
+	func OnOrderChanged() {}`
+
+	want := `package api
+
+// @asyncapi
+// @server				myServer	mqtt	mqtt://broker.hivemq.com
+// @server.description	Public HiveMQ broker
+// @server.variable	port						1883		Enums(1883, 8883)	"Port of the broker"
+// @channel			orders						myServer	"Events of the orders"
+// @channel			tenants/{tenantId}/orders	myServer	"Events of the orders of a tenant"
+// @channel.param		tenantId					string		"Tenant identifier"
+func ConfigChannels() {}
+
+// @asyncapi
+// @operation				send	orders	OrderCreated,OrderCancelled
+// @message.summary		Event emitted for every  order
+// @message.correlationId	$message.header#/correlationId	"Correlates the message"
+func OnOrderChanged() {}
+`
+
+	testFormat(t, "api.go", contents, want)
+}
+
+func Test_FormatCanonical(t *testing.T) {
+	contents := `package api
+
+// GetStringByInt returns a string.
+//
+// @Router /testapi/get-string-by-int/{some_id} [get]
+// @success 200 {string} string "ok"
+// @Header 200 {string} Token "qwerty"
+// @Param some_id path int true "Some ID"
+// @FAILURE 400 {object} web.APIError "We need ID!!"
+// @security ApiKeyAuth
+// @produce JSON, application/xml
+// @accept mpfd
+// @x-example {"key": "value"}
+// @tags example
+// @description get string by ID
+// @Description with a second line
+// @summary Add a new pet to the store
+func GetStringByInt() {}
+
+// @title Swagger Example API
+// @version 1.0
+// @accept json
+func main() {}`
+
+	want := `package api
+
+// GetStringByInt returns a string.
+//
+//	@Summary		Add a new pet to the store
+//	@Description	get string by ID
+//	@Description	with a second line
+//	@Tags			example
+//	@Accept			multipart/form-data
+//	@Produce		application/json,application/xml
+//	@Param			some_id	path		int				true	"Some ID"
+//	@Success		200		{string}	string			"ok"
+//	@Header			200		{string}	Token			"qwerty"
+//	@Failure		400		{object}	web.APIError	"We need ID!!"
+//	@Security		ApiKeyAuth
+//	@x-example		{"key": "value"}
+//	@Router			/testapi/get-string-by-int/{some_id} [get]
+func GetStringByInt() {}
+
+// @title		Swagger Example API
+// @version	1.0
+// @accept		json
+func main() {}
+`
+
+	got, err := NewFormatter(SetCanonical(true)).Format("api.go", []byte(contents))
+	assert.NoError(t, err)
+	assert.Equal(t, want, string(got))
+}
+
 func Test_NonSwagComment(t *testing.T) {
 	contents := `package api
 
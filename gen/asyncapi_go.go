@@ -0,0 +1,581 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+	asyncSpec "github.com/swaggest/go-asyncapi/spec-2.4.0"
+	"github.com/yalochat/swag"
+)
+
+// asyncGoOutputType is the output type generating the Go publishers and subscribers of the AsyncAPI channels.
+const asyncGoOutputType = "async"
+
+// asyncGoDoc holds the typed publishers and subscribers generated for the AsyncAPI channels.
+type asyncGoDoc struct {
+	PackageName   string
+	GeneratedTime bool
+	Timestamp     time.Time
+	Imports       []asyncGoImport
+	Channels      []asyncGoChannel
+}
+
+type asyncGoImport struct {
+	Alias string
+	Path  string
+}
+
+type asyncGoChannel struct {
+	// Name is the Go identifier of the channel
+	Name    string
+	Address string
+	Params  []asyncGoParam
+	// Discriminator is the payload field telling apart the messages received on the channel
+	Discriminator string
+	// Publish holds the messages the application sends, Subscribe the messages it receives
+	Publish   []asyncGoMessage
+	Subscribe []asyncGoMessage
+}
+
+type asyncGoParam struct {
+	// Name is the Go identifier of the parameter, Parameter its name in the channel
+	Name        string
+	Parameter   string
+	Placeholder string
+}
+
+type asyncGoMessage struct {
+	Name               string
+	Type               string
+	DiscriminatorValue string
+}
+
+// HasOperations reports whether a channel of the document sends or receives messages.
+func (doc *asyncGoDoc) HasOperations() bool {
+	for _, channel := range doc.Channels {
+		if len(channel.Publish) > 0 || len(channel.Subscribe) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasParams reports whether a channel of the document has parameters.
+func (doc *asyncGoDoc) HasParams() bool {
+	for _, channel := range doc.Channels {
+		if len(channel.Params) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ParamList returns the parameters of the channel as leading function arguments, e.g. "tenantId string, ".
+func (channel asyncGoChannel) ParamList() string {
+	var params strings.Builder
+	for _, param := range channel.Params {
+		params.WriteString(param.Name + " string, ")
+	}
+	return params.String()
+}
+
+// AddressExpr returns the Go expression of the channel name used to publish a message.
+func (channel asyncGoChannel) AddressExpr() string {
+	if len(channel.Params) == 0 {
+		return "Channel" + channel.Name
+	}
+
+	names := make([]string, 0, len(channel.Params))
+	for _, param := range channel.Params {
+		names = append(names, param.Name)
+	}
+
+	return "Channel" + channel.Name + "Address(" + strings.Join(names, ", ") + ")"
+}
+
+// SubscriptionExpr returns the Go expression of the channel subscribed to, which lists the names of its parameters so
+// that transports can translate them into the wildcard of their broker.
+func (channel asyncGoChannel) SubscriptionExpr() string {
+	if len(channel.Params) == 0 {
+		return "asynctransport.Channel{Name: Channel" + channel.Name + "}"
+	}
+
+	params := make([]string, 0, len(channel.Params))
+	for _, param := range channel.Params {
+		params = append(params, strconv.Quote(param.Parameter))
+	}
+
+	return "asynctransport.Channel{Name: Channel" + channel.Name + ", Params: []string{" + strings.Join(params, ", ") + "}}"
+}
+
+// ParamArgs returns the values of the parameters of a received message as leading function arguments, e.g.
+// "params[0], ".
+func (channel asyncGoChannel) ParamArgs() string {
+	var args strings.Builder
+	for i := range channel.Params {
+		args.WriteString("params[" + strconv.Itoa(i) + "], ")
+	}
+	return args.String()
+}
+
+// PublisherImpl returns the name of the unexported type implementing the channel publisher.
+func (channel asyncGoChannel) PublisherImpl() string {
+	return string(unicode.ToLower(rune(channel.Name[0]))) + channel.Name[1:] + "Publisher"
+}
+
+var (
+	asyncGoWordSeparator = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+	// asyncGoReservedNames can't be used for channel parameters as they're used by the generated functions.
+	asyncGoReservedNames = []string{
+		"ctx", "message", "payload", "err", "publisher", "transport", "subscriber", "channel", "subscription", "params",
+		"context", "json", "fmt", "strings", "asynctransport",
+	}
+)
+
+// asyncGoIdentifier converts a channel or message name into an exported Go identifier, e.g. orders.created becomes
+// OrdersCreated.
+func asyncGoIdentifier(name string) string {
+	var identifier strings.Builder
+
+	for _, word := range asyncGoWordSeparator.Split(name, -1) {
+		if word == "" {
+			continue
+		}
+
+		identifier.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+
+	if identifier.Len() == 0 || unicode.IsDigit(rune(identifier.String()[0])) {
+		return "X" + identifier.String()
+	}
+
+	return identifier.String()
+}
+
+func asyncGoParamName(name string) string {
+	identifier := asyncGoIdentifier(name)
+	paramName := strings.ToLower(identifier[:1]) + identifier[1:]
+
+	if token.IsKeyword(paramName) || findInSlice(asyncGoReservedNames, paramName) {
+		return paramName + "Param"
+	}
+
+	return paramName
+}
+
+func findInSlice(arr []string, target string) bool {
+	for _, item := range arr {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
+
+type asyncGoBuilder struct {
+	asyncAPI *asyncSpec.AsyncAPI
+	// types are the Go types of the schemas used by the AsyncAPI document, keyed by schema name
+	types   map[string]*swag.TypeSpecDef
+	imports map[string]string
+	aliases map[string]string
+}
+
+// newAsyncGoDoc builds the publishers and subscribers of the AsyncAPI channels. Payloads are described by the
+// Go types they were parsed from, payloads without an importable type are handled as json.RawMessage.
+func newAsyncGoDoc(asyncAPI *asyncSpec.AsyncAPI, parsedSchemas map[*swag.TypeSpecDef]*swag.Schema) (*asyncGoDoc, error) {
+	builder := &asyncGoBuilder{
+		asyncAPI: asyncAPI,
+		types:    make(map[string]*swag.TypeSpecDef),
+		imports:  make(map[string]string),
+		// The packages imported by the generated code.
+		aliases: map[string]string{
+			"context":        "context",
+			"json":           "encoding/json",
+			"fmt":            "fmt",
+			"strings":        "strings",
+			"asynctransport": "github.com/yalochat/swag/asynctransport",
+		},
+	}
+
+	for typeSpecDef, schema := range parsedSchemas {
+		if schema.UsedForAsyncAPI {
+			builder.types[schema.Name] = typeSpecDef
+		}
+	}
+
+	channelNames := make([]string, 0, len(asyncAPI.Channels))
+	for channelName := range asyncAPI.Channels {
+		channelNames = append(channelNames, channelName)
+	}
+
+	sort.Strings(channelNames)
+
+	doc := &asyncGoDoc{}
+	identifiers := make(map[string]string, len(channelNames))
+
+	for _, channelName := range channelNames {
+		channel, err := builder.newChannel(channelName, asyncAPI.Channels[channelName])
+		if err != nil {
+			return nil, fmt.Errorf("channel '%s' is invalid: %w", channelName, err)
+		}
+
+		if other, ok := identifiers[channel.Name]; ok {
+			return nil, fmt.Errorf("channels '%s' and '%s' map to the same Go identifier '%s'", other, channelName, channel.Name)
+		}
+
+		identifiers[channel.Name] = channelName
+		doc.Channels = append(doc.Channels, channel)
+	}
+
+	for importPath, alias := range builder.imports {
+		if alias == path.Base(importPath) {
+			alias = ""
+		}
+
+		doc.Imports = append(doc.Imports, asyncGoImport{Alias: alias, Path: importPath})
+	}
+
+	sort.Slice(doc.Imports, func(i, j int) bool {
+		return doc.Imports[i].Path < doc.Imports[j].Path
+	})
+
+	return doc, nil
+}
+
+func (builder *asyncGoBuilder) newChannel(channelName string, channel asyncSpec.ChannelItem) (asyncGoChannel, error) {
+	goChannel := asyncGoChannel{
+		Name:    asyncGoIdentifier(channelName),
+		Address: channelName,
+	}
+
+	for _, match := range asyncAPIPlaceholderPattern.FindAllStringSubmatch(channelName, -1) {
+		goChannel.Params = append(goChannel.Params, asyncGoParam{
+			Name:        asyncGoParamName(match[1]),
+			Parameter:   match[1],
+			Placeholder: match[0],
+		})
+	}
+
+	var err error
+
+	// AsyncAPI 2.4.0 operations are described from the client point of view: the application sends the messages of
+	// the subscribe operation and receives the messages of the publish operation.
+	if channel.Subscribe != nil {
+		if goChannel.Publish, err = builder.newMessages(channel.Subscribe, ""); err != nil {
+			return goChannel, err
+		}
+	}
+
+	if channel.Publish != nil {
+		messages := findAsyncAPIMessages(builder.asyncAPI, channel.Publish.Message)
+		if len(messages) > 1 {
+			if goChannel.Discriminator = builder.discriminator(messages); goChannel.Discriminator == "" {
+				return goChannel, fmt.Errorf("several message types are received without a discriminator, " +
+					"add a field with a distinct constant value to their payloads")
+			}
+		}
+
+		if goChannel.Subscribe, err = builder.newMessages(channel.Publish, goChannel.Discriminator); err != nil {
+			return goChannel, err
+		}
+	}
+
+	return goChannel, nil
+}
+
+func (builder *asyncGoBuilder) newMessages(operation *asyncSpec.Operation, discriminator string) ([]asyncGoMessage, error) {
+	var messages []asyncGoMessage
+
+	for _, entity := range findAsyncAPIMessages(builder.asyncAPI, operation.Message) {
+		if entity.MessageID == "" {
+			return nil, fmt.Errorf("messages of operation '%s' must have a messageId", operation.ID)
+		}
+
+		if entity.ContentType != "" && !strings.Contains(entity.ContentType, "json") {
+			return nil, fmt.Errorf("message '%s' uses the content type '%s', only JSON payloads are supported", entity.MessageID, entity.ContentType)
+		}
+
+		message := asyncGoMessage{
+			Name: asyncGoIdentifier(entity.MessageID),
+			Type: builder.goType(entity.Payload),
+		}
+
+		if discriminator != "" {
+			schema := findAsyncAPIPayloadSchema(builder.asyncAPI, entity.Payload)
+			properties, _ := schema["properties"].(map[string]interface{})
+			property, _ := properties[discriminator].(map[string]interface{})
+			message.DiscriminatorValue, _ = asyncAPITagValue(property)
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// discriminator returns the field telling apart the payloads of the messages.
+func (builder *asyncGoBuilder) discriminator(messages []*asyncSpec.MessageEntity) string {
+	schemas := make([]map[string]interface{}, 0, len(messages))
+	for _, message := range messages {
+		schema := findAsyncAPIPayloadSchema(builder.asyncAPI, message.Payload)
+		if schema == nil {
+			return ""
+		}
+
+		schemas = append(schemas, schema)
+	}
+
+	return findAsyncAPIDiscriminator(schemas)
+}
+
+// goType returns the Go type of a payload schema.
+func (builder *asyncGoBuilder) goType(schema map[string]interface{}) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		typeSpecDef, ok := builder.types[strings.TrimPrefix(ref, asyncAPISchemaPrefix)]
+		if !ok || !isImportableType(typeSpecDef) {
+			return "json.RawMessage"
+		}
+
+		return builder.importAlias(typeSpecDef.PkgPath, typeSpecDef.File.Name.Name) + "." + typeSpecDef.Name()
+	}
+
+	switch schema["type"] {
+	case "string":
+		return "string"
+	case "integer":
+		if format, ok := schema["format"].(string); ok && (format == "int32" || format == "int64") {
+			return format
+		}
+		return "int"
+	case "number":
+		if schema["format"] == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			return "[]" + builder.goType(items)
+		}
+	case "object":
+		if additionalProperties, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			return "map[string]" + builder.goType(additionalProperties)
+		}
+	}
+
+	return "json.RawMessage"
+}
+
+// isImportableType reports whether a type can be referenced from another package.
+func isImportableType(typeSpecDef *swag.TypeSpecDef) bool {
+	if typeSpecDef.File == nil || typeSpecDef.TypeSpec == nil || typeSpecDef.File.Name.Name == "main" {
+		return false
+	}
+
+	if _, ok := typeSpecDef.ParentSpec.(*ast.FuncDecl); ok {
+		return false
+	}
+
+	return typeSpecDef.TypeSpec.TypeParams == nil && ast.IsExported(typeSpecDef.Name())
+}
+
+// importAlias returns the alias a package is imported with, packages sharing a name get a numbered alias.
+func (builder *asyncGoBuilder) importAlias(importPath, packageName string) string {
+	if alias, ok := builder.imports[importPath]; ok {
+		return alias
+	}
+
+	alias := packageName
+	for i := 2; builder.aliases[alias] != ""; i++ {
+		alias = fmt.Sprintf("%s%d", packageName, i)
+	}
+
+	builder.imports[importPath] = alias
+	builder.aliases[alias] = importPath
+
+	return alias
+}
+
+func (g *Gen) writeAsyncGo(config *Config, swagger *spec.Swagger) error {
+	if g.parser == nil || len(g.parser.GetAsyncAPI().Channels) == 0 {
+		g.debug.Printf("no AsyncAPI channels found, skipping %s generation", asyncGoOutputType)
+		return nil
+	}
+
+	var filename = "asyncapi.go"
+
+	if config.State != "" {
+		filename = config.State + "_" + filename
+	}
+
+	if config.InstanceName != swag.Name {
+		filename = config.InstanceName + "_" + filename
+	}
+
+	fileName := path.Join(config.OutputDir, filename)
+
+	packageName, err := goPackageName(config)
+	if err != nil {
+		return err
+	}
+
+	doc, err := newAsyncGoDoc(g.parser.GetAsyncAPI(), g.parser.GetParsedSchemas())
+	if err != nil {
+		return fmt.Errorf("failed to generate AsyncAPI publishers and subscribers: %w", err)
+	}
+
+	doc.PackageName = packageName
+	doc.GeneratedTime = config.GeneratedTime
+	doc.Timestamp = time.Now()
+
+	generator, err := template.New("asyncapi_go").Parse(asyncGoTemplate)
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := generator.Execute(buffer, doc); err != nil {
+		return err
+	}
+
+	if err := g.writeFile(g.formatSource(buffer.Bytes()), fileName); err != nil {
+		return err
+	}
+
+	g.debug.Printf("create asyncapi.go at %+v", fileName)
+
+	return nil
+}
+
+var asyncGoTemplate = `// Package {{ .PackageName }} Code generated by github.com/yalochat/swag{{ if .GeneratedTime }} at {{ .Timestamp }}{{ end }}. DO NOT EDIT
+package {{ .PackageName }}
+
+import (
+{{- if .HasOperations }}
+	"context"
+	"encoding/json"
+	"fmt"
+{{- end }}
+{{- if .HasParams }}
+	"strings"
+{{- end }}
+{{- if .HasOperations }}
+
+	"github.com/yalochat/swag/asynctransport"
+{{- end }}
+{{- range .Imports }}
+	{{ if .Alias }}{{ .Alias }} {{ end }}{{ printf "%q" .Path }}
+{{- end }}
+)
+
+// Names of the AsyncAPI channels.
+const (
+{{- range .Channels }}
+	Channel{{ .Name }} = {{ printf "%q" .Address }}
+{{- end }}
+)
+{{ range $channel := .Channels }}
+{{- if .Params }}
+// Channel{{ .Name }}Address returns the name of the {{ .Address }} channel for the given parameters.
+func Channel{{ .Name }}Address({{ range $index, $param := .Params }}{{ if $index }}, {{ end }}{{ $param.Name }}{{ end }} string) string {
+	return strings.NewReplacer({{ range $index, $param := .Params }}{{ if $index }}, {{ end }}{{ printf "%q" $param.Placeholder }}, {{ $param.Name }}{{ end }}).Replace(Channel{{ .Name }})
+}
+{{ end }}
+{{- if .Publish }}
+// {{ .Name }}Publisher sends the messages of the {{ .Address }} channel.
+type {{ .Name }}Publisher interface {
+{{- range .Publish }}
+	Publish{{ .Name }}(ctx context.Context, {{ $channel.ParamList }}message {{ .Type }}) error
+{{- end }}
+}
+
+// New{{ .Name }}Publisher returns a publisher of the {{ .Address }} channel sending the messages through the transport.
+func New{{ .Name }}Publisher(transport asynctransport.Transport) {{ .Name }}Publisher {
+	return &{{ .PublisherImpl }}{transport: transport}
+}
+
+type {{ .PublisherImpl }} struct {
+	transport asynctransport.Transport
+}
+{{ range .Publish }}
+// Publish{{ .Name }} implements {{ $channel.Name }}Publisher.
+func (publisher *{{ $channel.PublisherImpl }}) Publish{{ .Name }}(ctx context.Context, {{ $channel.ParamList }}message {{ .Type }}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal {{ .Name }} message: %w", err)
+	}
+
+	return publisher.transport.Publish(ctx, {{ $channel.AddressExpr }}, payload)
+}
+{{ end }}
+{{- end }}
+{{- if .Subscribe }}
+// {{ .Name }}Subscriber handles the messages received on the {{ .Address }} channel.
+type {{ .Name }}Subscriber interface {
+{{- range .Subscribe }}
+	Handle{{ .Name }}(ctx context.Context, {{ $channel.ParamList }}message {{ .Type }}) error
+{{- end }}
+}
+
+// Subscribe{{ .Name }} delivers the messages received on the {{ .Address }} channel to the subscriber until the context is done.
+func Subscribe{{ .Name }}(ctx context.Context, transport asynctransport.Transport, subscriber {{ .Name }}Subscriber) error {
+{{- if .Params }}
+	subscription := {{ .SubscriptionExpr }}
+
+	return transport.Subscribe(ctx, subscription, func(ctx context.Context, channel string, payload []byte) error {
+		params, err := subscription.ParamValues(channel)
+		if err != nil {
+			return err
+		}
+{{ else }}
+	return transport.Subscribe(ctx, {{ .SubscriptionExpr }}, func(ctx context.Context, channel string, payload []byte) error {
+{{- end }}
+{{- if .Discriminator }}
+		var envelope map[string]interface{}
+		if err := json.Unmarshal(payload, &envelope); err != nil {
+			return fmt.Errorf("failed to unmarshal message of channel '%s': %w", channel, err)
+		}
+
+		tag, _ := envelope[{{ printf "%q" .Discriminator }}].(string)
+
+		switch tag {
+{{- range .Subscribe }}
+		case {{ printf "%q" .DiscriminatorValue }}:
+			var message {{ .Type }}
+			if err := json.Unmarshal(payload, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal {{ .Name }} message: %w", err)
+			}
+
+			return subscriber.Handle{{ .Name }}(ctx, {{ $channel.ParamArgs }}message)
+{{- end }}
+		default:
+			return fmt.Errorf("unknown {{ .Discriminator }} '%s' of message of channel '%s'", tag, channel)
+		}
+{{- else }}
+{{- with index .Subscribe 0 }}
+		var message {{ .Type }}
+		if err := json.Unmarshal(payload, &message); err != nil {
+			return fmt.Errorf("failed to unmarshal {{ .Name }} message: %w", err)
+		}
+
+		return subscriber.Handle{{ .Name }}(ctx, {{ $channel.ParamArgs }}message)
+{{- end }}
+{{- end }}
+	})
+}
+{{ end }}
+{{- end }}
+`
@@ -0,0 +1,237 @@
+package gen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	asyncSpec "github.com/swaggest/go-asyncapi/spec-2.4.0"
+)
+
+func TestAsyncGoIdentifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		paramName  string
+	}{
+		{name: "orders", identifier: "Orders", paramName: "orders"},
+		{name: "orders.created", identifier: "OrdersCreated", paramName: "ordersCreated"},
+		{name: "tenants/{tenantId}/orders", identifier: "TenantsTenantIdOrders", paramName: "tenantsTenantIdOrders"},
+		{name: "2fa-codes", identifier: "X2faCodes", paramName: "x2faCodes"},
+		{name: "type", identifier: "Type", paramName: "typeParam"},
+		{name: "message", identifier: "Message", paramName: "messageParam"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.identifier, asyncGoIdentifier(tt.name))
+			assert.Equal(t, tt.paramName, asyncGoParamName(tt.name))
+		})
+	}
+}
+
+func TestNewAsyncGoDoc(t *testing.T) {
+	message := func(messageID, contentType string, payload map[string]interface{}) asyncSpec.Message {
+		return *(&asyncSpec.Message{}).WithOneOf1(asyncSpec.MessageOneOf1{MessageEntity: &asyncSpec.MessageEntity{
+			MessageID:   messageID,
+			ContentType: contentType,
+			Payload:     payload,
+		}})
+	}
+
+	reference := func(messageID string) asyncSpec.Message {
+		return *(&asyncSpec.Message{}).WithReference(asyncSpec.Reference{Ref: asyncAPIMessagePrefix + messageID})
+	}
+
+	oneOf := asyncSpec.Message{}
+	oneOf.OneOf1Ens().WithOneOf0(asyncSpec.MessageOneOf1OneOf0{OneOf: []asyncSpec.Message{reference("Count"), reference("Names")}})
+
+	tests := []struct {
+		name        string
+		channels    map[string]asyncSpec.ChannelItem
+		expected    []asyncGoChannel
+		expectedErr string
+	}{
+		{
+			name: "primitive payloads",
+			channels: map[string]asyncSpec.ChannelItem{
+				"counters/{name}": {
+					Subscribe: (&asyncSpec.Operation{}).WithMessage(reference("Count")),
+					Publish:   (&asyncSpec.Operation{}).WithMessage(reference("Names")),
+				},
+			},
+			expected: []asyncGoChannel{{
+				Name:      "CountersName",
+				Address:   "counters/{name}",
+				Params:    []asyncGoParam{{Name: "name", Parameter: "name", Placeholder: "{name}"}},
+				Publish:   []asyncGoMessage{{Name: "Count", Type: "int64"}},
+				Subscribe: []asyncGoMessage{{Name: "Names", Type: "map[string][]string"}},
+			}},
+		},
+		{
+			name: "several received messages without discriminator",
+			channels: map[string]asyncSpec.ChannelItem{
+				"counters": {Publish: (&asyncSpec.Operation{}).WithMessage(oneOf)},
+			},
+			expectedErr: "channel 'counters' is invalid: several message types are received without a discriminator, " +
+				"add a field with a distinct constant value to their payloads",
+		},
+		{
+			name: "payload which isn't JSON",
+			channels: map[string]asyncSpec.ChannelItem{
+				"files": {Subscribe: (&asyncSpec.Operation{}).WithMessage(reference("File"))},
+			},
+			expectedErr: "channel 'files' is invalid: message 'File' uses the content type 'application/octet-stream', only JSON payloads are supported",
+		},
+		{
+			name: "channels with the same identifier",
+			channels: map[string]asyncSpec.ChannelItem{
+				"orders.created": {},
+				"orders/created": {},
+			},
+			expectedErr: "channels 'orders.created' and 'orders/created' map to the same Go identifier 'OrdersCreated'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asyncAPI := &asyncSpec.AsyncAPI{
+				Channels: tt.channels,
+				Components: &asyncSpec.Components{Messages: map[string]asyncSpec.Message{
+					"Count": message("Count", "application/json", map[string]interface{}{"type": "integer", "format": "int64"}),
+					"Names": message("Names", "", map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+					}),
+					"File": message("File", "application/octet-stream", map[string]interface{}{"type": "string"}),
+				}},
+			}
+
+			doc, err := newAsyncGoDoc(asyncAPI, nil)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, doc.Channels)
+			assert.Empty(t, doc.Imports)
+		})
+	}
+}
+
+const asyncGoRuntimeTest = `package docs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yalochat/swag/asynctransport"
+	"github.com/yalochat/swag/testdata/simple_async/api"
+)
+
+type ordersSubscriber struct {
+	created   []api.OrderCreated
+	cancelled []api.OrderCancelled
+}
+
+func (subscriber *ordersSubscriber) HandleOrderCreated(ctx context.Context, message api.OrderCreated) error {
+	subscriber.created = append(subscriber.created, message)
+	return nil
+}
+
+func (subscriber *ordersSubscriber) HandleOrderCancelled(ctx context.Context, message api.OrderCancelled) error {
+	subscriber.cancelled = append(subscriber.cancelled, message)
+	return nil
+}
+
+type tenantOrdersSubscriber struct {
+	tenants []string
+}
+
+func (subscriber *tenantOrdersSubscriber) HandleOrderCreated(ctx context.Context, tenantId string, message api.OrderCreated) error {
+	subscriber.tenants = append(subscriber.tenants, tenantId)
+	return nil
+}
+
+func TestOrders(t *testing.T) {
+	transport := asynctransport.NewMemory()
+	subscriber := &ordersSubscriber{}
+
+	if err := SubscribeOrders(context.Background(), transport, subscriber); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := NewOrdersPublisher(transport)
+	if err := publisher.PublishOrderCreated(context.Background(), api.OrderCreated{Type: "order.created", OrderID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.PublishOrderCancelled(context.Background(), api.OrderCancelled{Type: "order.cancelled", OrderID: "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.PublishOrderShipped(context.Background(), api.OrderShipped{Type: "order.shipped", OrderID: "3"}); err == nil {
+		t.Fatal("expected an error for a message without handler")
+	}
+
+	if len(subscriber.created) != 1 || subscriber.created[0].OrderID != "1" {
+		t.Fatalf("unexpected created orders %v", subscriber.created)
+	}
+	if len(subscriber.cancelled) != 1 || subscriber.cancelled[0].OrderID != "2" {
+		t.Fatalf("unexpected cancelled orders %v", subscriber.cancelled)
+	}
+
+	tenantSubscriber := &tenantOrdersSubscriber{}
+	if err := SubscribeTenantsTenantIdOrders(context.Background(), transport, tenantSubscriber); err != nil {
+		t.Fatal(err)
+	}
+
+	tenantPublisher := NewTenantsTenantIdOrdersPublisher(transport)
+	if err := tenantPublisher.PublishOrderCreated(context.Background(), "acme", api.OrderCreated{}); err != nil {
+		t.Fatal(err)
+	}
+
+	published := transport.Published()
+	if channel := published[len(published)-1].Channel; channel != "tenants/acme/orders" {
+		t.Fatalf("unexpected channel %s", channel)
+	}
+	if len(tenantSubscriber.tenants) != 1 || tenantSubscriber.tenants[0] != "acme" {
+		t.Fatalf("unexpected tenants %v", tenantSubscriber.tenants)
+	}
+}
+`
+
+func TestGen_GeneratedAsyncGo(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple_async",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple_async/docs",
+		OutputTypes: []string{"json", "async"},
+	}
+
+	require.NoError(t, New().Build(config))
+
+	generatedFile := filepath.Join(config.OutputDir, "asyncapi.go")
+	testFile := filepath.Join(config.OutputDir, "asyncapi_test.go")
+
+	defer func() {
+		for _, file := range []string{generatedFile, testFile, filepath.Join(config.OutputDir, "swagger.json"), filepath.Join(config.OutputDir, "asyncapi.yaml")} {
+			_ = os.Remove(file)
+		}
+	}()
+
+	require.FileExists(t, generatedFile)
+	require.NoError(t, os.WriteFile(testFile, []byte(asyncGoRuntimeTest), 0644))
+
+	goCMD, err := exec.LookPath("go")
+	require.NoError(t, err)
+
+	cmd := exec.Command(goCMD, "test", "./docs")
+	cmd.Dir = "../testdata/simple_async"
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	assert.NoError(t, cmd.Run())
+}
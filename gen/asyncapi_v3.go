@@ -0,0 +1,333 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	asyncSpec "github.com/swaggest/go-asyncapi/spec-2.4.0"
+	"github.com/yalochat/swag"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	asyncAPIVersion2 = "2.4.0"
+	asyncAPIVersion3 = "3.0.0"
+
+	asyncAPIMessagePrefix = "#/components/messages/"
+	asyncAPISchemaPrefix  = "#/components/schemas/"
+)
+
+// asyncAPIV3 is the root document object of an AsyncAPI 3.0 specification.
+type asyncAPIV3 struct {
+	AsyncAPI   string                         `json:"asyncapi"`
+	Info       asyncSpec.Info                 `json:"info"`
+	Servers    map[string]asyncAPIV3Server    `json:"servers,omitempty"`
+	Channels   map[string]asyncAPIV3Channel   `json:"channels,omitempty"`
+	Operations map[string]asyncAPIV3Operation `json:"operations,omitempty"`
+	Components *asyncAPIV3Components          `json:"components,omitempty"`
+}
+
+type asyncAPIV3Reference struct {
+	Ref string `json:"$ref"`
+}
+
+type asyncAPIV3Server struct {
+	Host            string                              `json:"host"`
+	Protocol        string                              `json:"protocol"`
+	ProtocolVersion string                              `json:"protocolVersion,omitempty"`
+	Pathname        string                              `json:"pathname,omitempty"`
+	Description     string                              `json:"description,omitempty"`
+	Variables       map[string]asyncSpec.ServerVariable `json:"variables,omitempty"`
+	Security        []asyncAPIV3Reference               `json:"security,omitempty"`
+	Bindings        *asyncSpec.ServerBindingsObject     `json:"bindings,omitempty"`
+}
+
+type asyncAPIV3Channel struct {
+	Address     string                           `json:"address"`
+	Description string                           `json:"description,omitempty"`
+	Servers     []asyncAPIV3Reference            `json:"servers,omitempty"`
+	Parameters  map[string]asyncAPIV3Parameter   `json:"parameters,omitempty"`
+	Messages    map[string]asyncAPIV3Reference   `json:"messages,omitempty"`
+	Bindings    *asyncSpec.ChannelBindingsObject `json:"bindings,omitempty"`
+}
+
+// asyncAPIV3Parameter is a channel parameter, which AsyncAPI 3.0 describes with string values instead of a schema.
+type asyncAPIV3Parameter struct {
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+	Location    string   `json:"location,omitempty"`
+}
+
+type asyncAPIV3Operation struct {
+	Action      swag.OperationAction               `json:"action"`
+	Channel     asyncAPIV3Reference                `json:"channel"`
+	Summary     string                             `json:"summary,omitempty"`
+	Description string                             `json:"description,omitempty"`
+	Security    []asyncAPIV3Reference              `json:"security,omitempty"`
+	Messages    []asyncAPIV3Reference              `json:"messages,omitempty"`
+	Bindings    *asyncSpec.OperationBindingsObject `json:"bindings,omitempty"`
+}
+
+type asyncAPIV3Components struct {
+	Schemas         map[string]map[string]interface{}    `json:"schemas,omitempty"`
+	Messages        map[string]map[string]interface{}    `json:"messages,omitempty"`
+	SecuritySchemes *asyncSpec.ComponentsSecuritySchemes `json:"securitySchemes,omitempty"`
+}
+
+// newAsyncAPIV3 converts the AsyncAPI 2.4.0 document built by the parser into an AsyncAPI 3.0 document,
+// turning every parsed operation into a top-level operation that references its channel and messages.
+func newAsyncAPIV3(asyncAPI *asyncSpec.AsyncAPI, operations map[string]*swag.OperationWithChannel) (*asyncAPIV3, error) {
+	doc := &asyncAPIV3{
+		AsyncAPI:   asyncAPIVersion3,
+		Info:       asyncAPI.Info,
+		Servers:    make(map[string]asyncAPIV3Server),
+		Channels:   make(map[string]asyncAPIV3Channel),
+		Operations: make(map[string]asyncAPIV3Operation),
+		Components: &asyncAPIV3Components{
+			Messages: make(map[string]map[string]interface{}),
+		},
+	}
+
+	if asyncAPI.Components != nil {
+		doc.Components.Schemas = asyncAPI.Components.Schemas
+		doc.Components.SecuritySchemes = asyncAPI.Components.SecuritySchemes
+
+		for messageID, message := range asyncAPI.Components.Messages {
+			if message.OneOf1 == nil || message.OneOf1.MessageEntity == nil {
+				continue
+			}
+
+			messageV3, err := newAsyncAPIV3Message(*message.OneOf1.MessageEntity)
+			if err != nil {
+				return nil, fmt.Errorf("message '%s' is invalid: %w", messageID, err)
+			}
+
+			doc.Components.Messages[messageID] = messageV3
+		}
+	}
+
+	for serverName, server := range asyncAPI.Servers {
+		if server.Server == nil {
+			continue
+		}
+
+		doc.Servers[serverName] = newAsyncAPIV3Server(server.Server)
+	}
+
+	channelIDs := make(map[string]string, len(asyncAPI.Channels))
+	addresses := make(map[string]string, len(asyncAPI.Channels))
+
+	for channelName, channel := range asyncAPI.Channels {
+		channelID := asyncAPIV3ChannelID(channelName)
+		if address, ok := addresses[channelID]; ok {
+			return nil, fmt.Errorf("channels '%s' and '%s' map to the same AsyncAPI 3.0 channel ID '%s'", address, channelName, channelID)
+		}
+
+		addresses[channelID] = channelName
+		channelIDs[channelName] = channelID
+
+		channelV3 := asyncAPIV3Channel{
+			Address:     channelName,
+			Description: channel.Description,
+			Parameters:  newAsyncAPIV3Parameters(channel.Parameters),
+			Messages:    make(map[string]asyncAPIV3Reference),
+			Bindings:    channel.Bindings,
+		}
+
+		for _, serverName := range channel.Servers {
+			channelV3.Servers = append(channelV3.Servers, asyncAPIV3Reference{Ref: "#/servers/" + serverName})
+		}
+
+		doc.Channels[channelID] = channelV3
+	}
+
+	for operationID, operation := range operations {
+		channelID, ok := channelIDs[operation.Channel()]
+		if !ok {
+			return nil, fmt.Errorf("operation '%s' is using channel '%s' that was not defined", operationID, operation.Channel())
+		}
+
+		operationV3 := asyncAPIV3Operation{
+			Action:      operation.Action(),
+			Channel:     asyncAPIV3Reference{Ref: "#/channels/" + channelID},
+			Summary:     operation.Summary,
+			Description: operation.Description,
+			Security:    newAsyncAPIV3SecurityReferences(operation.Security),
+			Bindings:    operation.Bindings,
+		}
+
+		messageIDs, err := operationMessageIDs(&operation.Operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation '%s' is invalid: %w", operationID, err)
+		}
+
+		for _, messageID := range messageIDs {
+			if _, ok := doc.Components.Messages[messageID]; !ok {
+				return nil, fmt.Errorf("operation '%s' is using message '%s' that was not defined", operationID, messageID)
+			}
+
+			doc.Channels[channelID].Messages[messageID] = asyncAPIV3Reference{Ref: asyncAPIMessagePrefix + messageID}
+
+			operationV3.Messages = append(operationV3.Messages, asyncAPIV3Reference{
+				Ref: "#/channels/" + channelID + "/messages/" + messageID,
+			})
+		}
+
+		doc.Operations[operationID] = operationV3
+	}
+
+	return doc, nil
+}
+
+func newAsyncAPIV3Parameters(parameters map[string]asyncSpec.Parameter) map[string]asyncAPIV3Parameter {
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	parametersV3 := make(map[string]asyncAPIV3Parameter, len(parameters))
+
+	for paramName, parameter := range parameters {
+		parameterV3 := asyncAPIV3Parameter{
+			Description: parameter.Description,
+			Location:    parameter.Location,
+		}
+
+		if enum, ok := parameter.Schema["enum"].([]interface{}); ok {
+			for _, value := range enum {
+				parameterV3.Enum = append(parameterV3.Enum, fmt.Sprint(value))
+			}
+		}
+
+		if value, ok := parameter.Schema["default"]; ok {
+			parameterV3.Default = fmt.Sprint(value)
+		}
+
+		if value, ok := parameter.Schema["example"]; ok {
+			parameterV3.Examples = []string{fmt.Sprint(value)}
+		}
+
+		parametersV3[paramName] = parameterV3
+	}
+
+	return parametersV3
+}
+
+func newAsyncAPIV3Server(server *asyncSpec.Server) asyncAPIV3Server {
+	host, pathname := splitAsyncAPIServerURL(server.URL)
+
+	return asyncAPIV3Server{
+		Host:            host,
+		Protocol:        server.Protocol,
+		ProtocolVersion: server.ProtocolVersion,
+		Pathname:        pathname,
+		Description:     server.Description,
+		Variables:       server.Variables,
+		Security:        newAsyncAPIV3SecurityReferences(server.Security),
+		Bindings:        server.Bindings,
+	}
+}
+
+// splitAsyncAPIServerURL splits a 2.4.0 server URL (e.g., "mqtt://broker.hivemq.com/events") into
+// the host and pathname used by AsyncAPI 3.0, dropping the scheme which is carried by the protocol.
+func splitAsyncAPIServerURL(url string) (string, string) {
+	if i := strings.Index(url, "://"); i != -1 {
+		url = url[i+3:]
+	}
+
+	if i := strings.Index(url, "/"); i != -1 {
+		return url[:i], url[i:]
+	}
+
+	return url, ""
+}
+
+func newAsyncAPIV3SecurityReferences(security []map[string][]string) []asyncAPIV3Reference {
+	var references []asyncAPIV3Reference
+
+	for _, requirement := range security {
+		schemeNames := make([]string, 0, len(requirement))
+		for schemeName := range requirement {
+			schemeNames = append(schemeNames, schemeName)
+		}
+
+		sort.Strings(schemeNames)
+
+		for _, schemeName := range schemeNames {
+			references = append(references, asyncAPIV3Reference{Ref: "#/components/securitySchemes/" + schemeName})
+		}
+	}
+
+	return references
+}
+
+// newAsyncAPIV3Message converts a 2.4.0 message into a 3.0 message object, which no longer has a messageId
+// because messages are identified by their key in components.
+func newAsyncAPIV3Message(entity asyncSpec.MessageEntity) (map[string]interface{}, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var message map[string]interface{}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, err
+	}
+
+	delete(message, "messageId")
+
+	return message, nil
+}
+
+// operationMessageIDs returns the IDs of the component messages referenced by an operation.
+func operationMessageIDs(operation *asyncSpec.Operation) ([]string, error) {
+	var messageIDs []string
+
+	for _, message := range swag.AsyncAPIMessages(operation.Message) {
+		if message.Reference == nil {
+			continue
+		}
+
+		ref := message.Reference.Ref
+		if !strings.HasPrefix(ref, asyncAPIMessagePrefix) {
+			return nil, fmt.Errorf("message reference '%s' does not point to %s", ref, asyncAPIMessagePrefix)
+		}
+
+		messageIDs = append(messageIDs, strings.TrimPrefix(ref, asyncAPIMessagePrefix))
+	}
+
+	return messageIDs, nil
+}
+
+var invalidAsyncAPIV3IDChars = regexp.MustCompile(`[^A-Za-z0-9_\-]+`)
+
+// asyncAPIV3ChannelID builds a channel key that satisfies the AsyncAPI 3.0 ID pattern; the original
+// channel name is kept as the channel address.
+func asyncAPIV3ChannelID(channelName string) string {
+	return strings.Trim(invalidAsyncAPIV3IDChars.ReplaceAllString(channelName, "_"), "_")
+}
+
+// writeDocAsyncAPIV3 creates the AsyncAPI 3.0 spec file.
+func writeDocAsyncAPIV3(doc *asyncAPIV3, outputFile string) error {
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal AsyncAPI 3.0 spec: %w", err)
+	}
+
+	yamlData, err := yaml.JSONToYAML(jsonData)
+	if err != nil {
+		return fmt.Errorf("failed to convert AsyncAPI 3.0 spec to yaml: %w", err)
+	}
+
+	if err := writeFileIfChanged(outputFile, yamlData); err != nil {
+		return fmt.Errorf("failed to write AsyncAPI 3.0 spec file: %w", err)
+	}
+
+	log.Printf("asyncAPI 3.0 spec written to %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,208 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	asyncSpec "github.com/swaggest/go-asyncapi/spec-2.4.0"
+	"github.com/yalochat/swag"
+	"sigs.k8s.io/yaml"
+)
+
+func TestNewAsyncAPIV3(t *testing.T) {
+	p := swag.New()
+	require.NoError(t, p.ParseAPI("../testdata/simple_async", "./main.go", 100))
+
+	asyncAPI := p.GetAsyncAPI()
+	updateAsyncAPIInfo(asyncAPI, p.GetSwagger())
+	require.NoError(t, processAsyncAPIDefinitions(p, asyncAPI, p.GetSwagger()))
+
+	doc, err := newAsyncAPIV3(asyncAPI, p.GetAsyncAPIOperations())
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.0.0", doc.AsyncAPI)
+	assert.Equal(t, "Swagger Example AsyncAPI", doc.Info.Title)
+
+	require.Contains(t, doc.Servers, "myServer")
+	assert.Equal(t, "broker.hivemq.com", doc.Servers["myServer"].Host)
+	assert.Equal(t, "mqtt", doc.Servers["myServer"].Protocol)
+	assert.Equal(t, "5", doc.Servers["myServer"].ProtocolVersion)
+	assert.Equal(t, []asyncAPIV3Reference{{Ref: "#/components/securitySchemes/userPassword"}}, doc.Servers["myServer"].Security)
+	require.NotNil(t, doc.Components.SecuritySchemes)
+	assert.Contains(t, doc.Components.SecuritySchemes.MapOfComponentsSecuritySchemesWDValues, "userPassword")
+
+	require.Contains(t, doc.Channels, "myChannel")
+	channel := doc.Channels["myChannel"]
+	assert.Equal(t, "myChannel", channel.Address)
+	assert.Equal(t, []asyncAPIV3Reference{{Ref: "#/servers/myServer"}}, channel.Servers)
+	assert.Equal(t, map[string]asyncAPIV3Reference{
+		"MyMessage": {Ref: "#/components/messages/MyMessage"},
+	}, channel.Messages)
+
+	require.Contains(t, doc.Operations, "OnMessageReceived")
+	operation := doc.Operations["OnMessageReceived"]
+	assert.Equal(t, swag.Send, operation.Action)
+	assert.Equal(t, asyncAPIV3Reference{Ref: "#/channels/myChannel"}, operation.Channel)
+	assert.Equal(t, []asyncAPIV3Reference{{Ref: "#/channels/myChannel/messages/MyMessage"}}, operation.Messages)
+
+	require.Contains(t, doc.Components.Messages, "MyMessage")
+	assert.NotContains(t, doc.Components.Messages["MyMessage"], "messageId")
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/api.MyMessage"}, doc.Components.Messages["MyMessage"]["payload"])
+	assert.Contains(t, doc.Components.Messages["MyMessage"], "headers")
+	assert.Equal(t, "application/json", doc.Components.Messages["MyMessage"]["contentType"])
+	assert.Contains(t, doc.Components.Schemas, "api.MyMessage")
+
+	require.Contains(t, doc.Operations, "OnMessageForwarded")
+	assert.Equal(t, swag.Receive, doc.Operations["OnMessageForwarded"].Action)
+	assert.Equal(t, operation.Messages, doc.Operations["OnMessageForwarded"].Messages)
+
+	require.Contains(t, doc.Channels, "orders")
+	assert.Equal(t, map[string]asyncAPIV3Reference{
+		"OrderCreated":   {Ref: "#/components/messages/OrderCreated"},
+		"OrderCancelled": {Ref: "#/components/messages/OrderCancelled"},
+		"OrderShipped":   {Ref: "#/components/messages/OrderShipped"},
+	}, doc.Channels["orders"].Messages)
+	assert.Equal(t, []asyncAPIV3Reference{
+		{Ref: "#/channels/orders/messages/OrderCreated"},
+		{Ref: "#/channels/orders/messages/OrderCancelled"},
+	}, doc.Operations["OnOrderChanged"].Messages)
+}
+
+func TestOperationMessageIDs(t *testing.T) {
+	operation := asyncSpec.Operation{}
+	operation.WithMessage(*(&asyncSpec.Message{}).WithReference(asyncSpec.Reference{Ref: "#/components/messages/MyMessage"}))
+
+	messageIDs, err := operationMessageIDs(&operation)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MyMessage"}, messageIDs)
+
+	oneOf := asyncSpec.Message{}
+	oneOf.OneOf1Ens().WithOneOf0(asyncSpec.MessageOneOf1OneOf0{OneOf: []asyncSpec.Message{
+		*(&asyncSpec.Message{}).WithReference(asyncSpec.Reference{Ref: "#/components/messages/OrderCreated"}),
+		*(&asyncSpec.Message{}).WithReference(asyncSpec.Reference{Ref: "#/components/messages/OrderCancelled"}),
+	}})
+
+	messageIDs, err = operationMessageIDs((&asyncSpec.Operation{}).WithMessage(oneOf))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OrderCreated", "OrderCancelled"}, messageIDs)
+
+	operation.Message.Reference.Ref = "#/components/schemas/MyMessage"
+
+	_, err = operationMessageIDs(&operation)
+	assert.EqualError(t, err, "message reference '#/components/schemas/MyMessage' does not point to #/components/messages/")
+}
+
+func TestNewAsyncAPIV3_ChannelIDs(t *testing.T) {
+	asyncAPI := &asyncSpec.AsyncAPI{
+		Channels: map[string]asyncSpec.ChannelItem{
+			"orders.created": {Servers: []string{"kafka"}},
+		},
+	}
+
+	doc, err := newAsyncAPIV3(asyncAPI, nil)
+	require.NoError(t, err)
+	require.Contains(t, doc.Channels, "orders_created")
+	assert.Equal(t, "orders.created", doc.Channels["orders_created"].Address)
+
+	asyncAPI.Channels["orders_created"] = asyncSpec.ChannelItem{Servers: []string{"kafka"}}
+
+	_, err = newAsyncAPIV3(asyncAPI, nil)
+	assert.Error(t, err)
+}
+
+func TestNewAsyncAPIV3_ChannelParameters(t *testing.T) {
+	asyncAPI := &asyncSpec.AsyncAPI{
+		Channels: map[string]asyncSpec.ChannelItem{
+			"orders/{region}": {
+				Servers: []string{"kafka"},
+				Parameters: map[string]asyncSpec.Parameter{
+					"region": {
+						Description: "Region of the order",
+						Schema:      map[string]interface{}{"type": "string", "enum": []interface{}{"eu", "us"}, "default": "eu", "example": "us"},
+					},
+				},
+			},
+		},
+	}
+
+	doc, err := newAsyncAPIV3(asyncAPI, nil)
+	require.NoError(t, err)
+
+	require.Contains(t, doc.Channels, asyncAPIV3ChannelID("orders/{region}"))
+
+	channel := doc.Channels[asyncAPIV3ChannelID("orders/{region}")]
+	assert.Equal(t, "orders/{region}", channel.Address)
+	assert.Equal(t, map[string]asyncAPIV3Parameter{
+		"region": {Description: "Region of the order", Enum: []string{"eu", "us"}, Default: "eu", Examples: []string{"us"}},
+	}, channel.Parameters)
+}
+
+func TestSplitAsyncAPIServerURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		host     string
+		pathname string
+	}{
+		{url: "mqtt://broker.hivemq.com", host: "broker.hivemq.com"},
+		{url: "kafka.internal:9092", host: "kafka.internal:9092"},
+		{url: "ws://localhost:8080/events/v1", host: "localhost:8080", pathname: "/events/v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			host, pathname := splitAsyncAPIServerURL(tt.url)
+			assert.Equal(t, tt.host, host)
+			assert.Equal(t, tt.pathname, pathname)
+		})
+	}
+}
+
+func TestGen_GeneratedAsyncDocV3(t *testing.T) {
+	config := &Config{
+		SearchDir:        "../testdata/simple_async",
+		MainAPIFile:      "./main.go",
+		OutputDir:        "../testdata/simple_async/docs",
+		OutputTypes:      []string{"json"},
+		AsyncAPIVersions: []string{"2.4.0", "3.0.0"},
+	}
+
+	require.NoError(t, New().Build(config))
+
+	expectedFiles := []string{
+		filepath.Join(config.OutputDir, "swagger.json"),
+		filepath.Join(config.OutputDir, "asyncapi.yaml"),
+		filepath.Join(config.OutputDir, "asyncapi_v3.yaml"),
+	}
+	t.Cleanup(func() {
+		for _, expectedFile := range expectedFiles {
+			_ = os.Remove(expectedFile)
+		}
+	})
+
+	for _, expectedFile := range expectedFiles {
+		_, err := os.Stat(expectedFile)
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(config.OutputDir, "asyncapi_v3.yaml"))
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &doc))
+	assert.Equal(t, "3.0.0", doc["asyncapi"])
+	assert.Contains(t, doc, "operations")
+}
+
+func TestGen_UnsupportedAsyncAPIVersion(t *testing.T) {
+	config := &Config{
+		SearchDir:        "../testdata/simple_async",
+		MainAPIFile:      "./main.go",
+		OutputDir:        "../testdata/simple_async/docs",
+		AsyncAPIVersions: []string{"1.0.0"},
+	}
+
+	assert.EqualError(t, New().Build(config), "not supported 1.0.0 AsyncAPI version")
+}
@@ -0,0 +1,1045 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/yalochat/swag"
+	"sigs.k8s.io/yaml"
+)
+
+// Change is a difference between two versions of a Swagger or AsyncAPI document.
+type Change struct {
+	// Location of the change: an operation, a parameter, a response, a channel or a message
+	Location string
+
+	// Message describes the change
+	Message string
+
+	// Breaking tells whether clients written for the old document may fail with the new one
+	Breaking bool
+}
+
+// String returns the change as a single line prefixed with its kind.
+func (change Change) String() string {
+	kind := "non-breaking"
+	if change.Breaking {
+		kind = "breaking"
+	}
+
+	return fmt.Sprintf("%s: %s: %s", kind, change.Location, change.Message)
+}
+
+// HasBreakingChanges reports whether one of the changes is breaking.
+func HasBreakingChanges(changes []Change) bool {
+	for _, change := range changes {
+		if change.Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffDirection tells in which direction data described by a schema flows, which decides whether a change of the
+// schema is breaking: a client may send less but must understand everything it receives.
+type diffDirection int
+
+const (
+	// diffRequest describes data sent by clients: HTTP requests and AsyncAPI messages the application receives.
+	diffRequest diffDirection = iota
+
+	// diffResponse describes data received by clients: HTTP responses and AsyncAPI messages the application sends.
+	diffResponse
+)
+
+// Kinds of documents compared by Diff.
+const (
+	diffSwagger   = "Swagger 2.0"
+	diffOpenAPI3  = "OpenAPI 3"
+	diffAsyncAPI2 = "AsyncAPI 2"
+	diffAsyncAPI3 = "AsyncAPI 3"
+)
+
+// DiffFiles compares two Swagger 2.0 (swagger.json, swagger.yaml), OpenAPI 3 (openapi.json, openapi.yaml),
+// AsyncAPI 2 (asyncapi.yaml) or AsyncAPI 3 (asyncapi_v3.yaml) files.
+func DiffFiles(oldFile, newFile string) ([]Change, error) {
+	oldDoc, err := os.ReadFile(oldFile)
+	if err != nil {
+		return nil, err
+	}
+
+	newDoc, err := os.ReadFile(newFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return Diff(oldDoc, newDoc)
+}
+
+// Diff compares two Swagger 2.0, OpenAPI 3, AsyncAPI 2 or AsyncAPI 3 documents of the same kind, encoded as JSON or
+// YAML, and returns their changes sorted by location. Changes of schemas are reported where the schemas are used,
+// references are resolved.
+func Diff(oldDoc, newDoc []byte) ([]Change, error) {
+	oldJSON, err := yaml.YAMLToJSON(oldDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode old document: %w", err)
+	}
+
+	newJSON, err := yaml.YAMLToJSON(newDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode new document: %w", err)
+	}
+
+	oldKind, err := diffDocumentKind(oldJSON)
+	if err != nil {
+		return nil, fmt.Errorf("old document is invalid: %w", err)
+	}
+
+	newKind, err := diffDocumentKind(newJSON)
+	if err != nil {
+		return nil, fmt.Errorf("new document is invalid: %w", err)
+	}
+
+	if oldKind != newKind {
+		return nil, fmt.Errorf("can't compare a %s document with a %s document", oldKind, newKind)
+	}
+
+	differ := &schemaDiffer{
+		oldSchemas: make(map[string]map[string]interface{}),
+		newSchemas: make(map[string]map[string]interface{}),
+		refs:       make(map[string]bool),
+	}
+
+	switch oldKind {
+	case diffAsyncAPI2, diffAsyncAPI3:
+		oldAsyncAPI, err := decodeDiffAsyncAPI(oldJSON, oldKind)
+		if err != nil {
+			return nil, fmt.Errorf("old document is invalid: %w", err)
+		}
+
+		newAsyncAPI, err := decodeDiffAsyncAPI(newJSON, newKind)
+		if err != nil {
+			return nil, fmt.Errorf("new document is invalid: %w", err)
+		}
+
+		differ.diffAsyncAPI(oldAsyncAPI, newAsyncAPI)
+	default:
+		oldPaths, err := decodeDiffPaths(oldJSON, oldKind, differ.oldSchemas)
+		if err != nil {
+			return nil, fmt.Errorf("old document is invalid: %w", err)
+		}
+
+		newPaths, err := decodeDiffPaths(newJSON, newKind, differ.newSchemas)
+		if err != nil {
+			return nil, fmt.Errorf("new document is invalid: %w", err)
+		}
+
+		differ.diffPaths(oldPaths, newPaths)
+	}
+
+	sort.SliceStable(differ.changes, func(i, j int) bool {
+		return differ.changes[i].Location < differ.changes[j].Location
+	})
+
+	return differ.changes, nil
+}
+
+func diffDocumentKind(doc []byte) (string, error) {
+	var header struct {
+		Swagger  string `json:"swagger"`
+		OpenAPI  string `json:"openapi"`
+		AsyncAPI string `json:"asyncapi"`
+	}
+
+	if err := json.Unmarshal(doc, &header); err != nil {
+		return "", err
+	}
+
+	switch {
+	case header.Swagger == openAPIVersion2:
+		return diffSwagger, nil
+	case strings.HasPrefix(header.OpenAPI, "3."):
+		return diffOpenAPI3, nil
+	case strings.HasPrefix(header.AsyncAPI, "2."):
+		return diffAsyncAPI2, nil
+	case strings.HasPrefix(header.AsyncAPI, "3."):
+		return diffAsyncAPI3, nil
+	case header.Swagger != "":
+		return "", fmt.Errorf("unsupported Swagger version %s", header.Swagger)
+	case header.OpenAPI != "":
+		return "", fmt.Errorf("unsupported OpenAPI version %s", header.OpenAPI)
+	case header.AsyncAPI != "":
+		return "", fmt.Errorf("unsupported AsyncAPI version %s", header.AsyncAPI)
+	default:
+		return "", fmt.Errorf("not a Swagger, OpenAPI or AsyncAPI document")
+	}
+}
+
+type schemaDiffer struct {
+	// oldSchemas and newSchemas hold the schemas which can be referenced, by reference
+	oldSchemas map[string]map[string]interface{}
+	newSchemas map[string]map[string]interface{}
+	// refs holds the references being compared, to stop on recursive schemas
+	refs    map[string]bool
+	changes []Change
+}
+
+func (d *schemaDiffer) add(location string, breaking bool, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+		Breaking: breaking,
+	})
+}
+
+// diffOperation holds the parts of an HTTP operation which are compared, whatever the version of its document.
+type diffOperation struct {
+	// params holds the parameters by location and name, the request body is the body parameter
+	params map[string]diffParameter
+	// responses holds the content of the responses by status code
+	responses map[string]diffContent
+}
+
+type diffParameter struct {
+	in       string
+	required bool
+	content  diffContent
+}
+
+// diffContent holds the schemas of a body by media type. Swagger 2.0 bodies and the parameters which aren't bodies
+// have a single schema, held by the empty media type.
+type diffContent map[string]map[string]interface{}
+
+// decodeDiffPaths decodes the operations of a Swagger 2.0 or OpenAPI 3 document by path and method, and adds its
+// schemas to schemas by reference.
+func decodeDiffPaths(doc []byte, kind string, schemas map[string]map[string]interface{}) (map[string]map[string]diffOperation, error) {
+	if kind == diffOpenAPI3 {
+		var openAPI openAPIV3
+		if err := json.Unmarshal(doc, &openAPI); err != nil {
+			return nil, err
+		}
+
+		return openAPIV3DiffPaths(&openAPI, schemas)
+	}
+
+	var swagger spec.Swagger
+	if err := json.Unmarshal(doc, &swagger); err != nil {
+		return nil, err
+	}
+
+	return swaggerDiffPaths(&swagger, schemas)
+}
+
+func (d *schemaDiffer) diffPaths(oldPaths, newPaths map[string]map[string]diffOperation) {
+	for _, path := range unionKeys(oldPaths, newPaths) {
+		oldOperations, inOld := oldPaths[path]
+		newOperations, inNew := newPaths[path]
+
+		switch {
+		case !inNew:
+			d.add(path, true, "path was removed")
+		case !inOld:
+			d.add(path, false, "path was added")
+		default:
+			d.diffOperations(path, oldOperations, newOperations)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffOperations(path string, oldOperations, newOperations map[string]diffOperation) {
+	for _, method := range unionKeys(oldOperations, newOperations) {
+		location := method + " " + path
+
+		oldOperation, inOld := oldOperations[method]
+		newOperation, inNew := newOperations[method]
+
+		switch {
+		case !inNew:
+			d.add(location, true, "operation was removed")
+		case !inOld:
+			d.add(location, false, "operation was added")
+		default:
+			d.diffParameters(location, oldOperation.params, newOperation.params)
+			d.diffResponses(location, oldOperation.responses, newOperation.responses)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffParameters(location string, oldParams, newParams map[string]diffParameter) {
+	for _, key := range unionKeys(oldParams, newParams) {
+		oldParam, inOld := oldParams[key]
+		newParam, inNew := newParams[key]
+
+		switch {
+		case !inNew:
+			// Clients may keep sending an optional parameter which is ignored, unless it's a segment of the path.
+			d.add(location, oldParam.required || oldParam.in == "path", "parameter '%s' was removed", key)
+		case !inOld && newParam.required:
+			d.add(location, true, "required parameter '%s' was added", key)
+		case !inOld:
+			d.add(location, false, "parameter '%s' was added", key)
+		default:
+			if newParam.required && !oldParam.required {
+				d.add(location, true, "parameter '%s' became required", key)
+			} else if oldParam.required && !newParam.required {
+				d.add(location, false, "parameter '%s' became optional", key)
+			}
+
+			d.diffContent(location+" parameter "+key, oldParam.content, newParam.content, diffRequest)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffResponses(location string, oldResponses, newResponses map[string]diffContent) {
+	for _, code := range unionKeys(oldResponses, newResponses) {
+		oldContent, inOld := oldResponses[code]
+		newContent, inNew := newResponses[code]
+
+		switch {
+		case !inNew:
+			d.add(location, true, "response %s was removed", code)
+		case !inOld:
+			d.add(location, false, "response %s was added", code)
+		case len(oldContent) > 0 && len(newContent) == 0:
+			d.add(location, true, "body of response %s was removed", code)
+		case len(oldContent) == 0 && len(newContent) > 0:
+			d.add(location, false, "body of response %s was added", code)
+		case len(oldContent) > 0:
+			d.diffContent(location+" response "+code+" body", oldContent, newContent, diffResponse)
+		}
+	}
+}
+
+// diffContent compares the schemas of a body by media type: a client may use any media type of the old document.
+func (d *schemaDiffer) diffContent(location string, oldContent, newContent diffContent, direction diffDirection) {
+	for _, mediaType := range unionKeys(oldContent, newContent) {
+		oldSchema, inOld := oldContent[mediaType]
+		newSchema, inNew := newContent[mediaType]
+
+		switch {
+		case !inNew:
+			d.add(location, true, "media type '%s' was removed", mediaType)
+		case !inOld:
+			d.add(location, false, "media type '%s' was added", mediaType)
+		case mediaType == "":
+			d.diffSchema(location, oldSchema, newSchema, direction)
+		default:
+			d.diffSchema(location+" "+mediaType, oldSchema, newSchema, direction)
+		}
+	}
+}
+
+func swaggerDiffPaths(swagger *spec.Swagger, schemas map[string]map[string]interface{}) (map[string]map[string]diffOperation, error) {
+	for name, definition := range swagger.Definitions {
+		schema, err := toJSONObject(definition)
+		if err != nil {
+			return nil, err
+		}
+
+		schemas["#/definitions/"+name] = schema
+	}
+
+	paths := make(map[string]map[string]diffOperation)
+
+	for path, pathItem := range swaggerPaths(swagger) {
+		operations := make(map[string]diffOperation)
+
+		for method, operation := range swaggerOperations(pathItem) {
+			params, err := swaggerParameters(swagger, pathItem, operation)
+			if err != nil {
+				return nil, err
+			}
+
+			responses, err := swaggerResponses(operation.Responses)
+			if err != nil {
+				return nil, err
+			}
+
+			operations[method] = diffOperation{params: params, responses: responses}
+		}
+
+		paths[path] = operations
+	}
+
+	return paths, nil
+}
+
+func swaggerPaths(swagger *spec.Swagger) map[string]spec.PathItem {
+	if swagger.Paths == nil {
+		return nil
+	}
+
+	return swagger.Paths.Paths
+}
+
+func swaggerOperations(pathItem spec.PathItem) map[string]*spec.Operation {
+	operations := make(map[string]*spec.Operation)
+
+	for method, operation := range map[string]*spec.Operation{
+		http.MethodGet:     pathItem.Get,
+		http.MethodPut:     pathItem.Put,
+		http.MethodPost:    pathItem.Post,
+		http.MethodDelete:  pathItem.Delete,
+		http.MethodOptions: pathItem.Options,
+		http.MethodHead:    pathItem.Head,
+		http.MethodPatch:   pathItem.Patch,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+
+	return operations
+}
+
+// swaggerParameters returns the parameters of an operation, including the ones of its path item, by location
+// and name. The body parameter is named body whatever its name, since an operation has a single one.
+func swaggerParameters(swagger *spec.Swagger, pathItem spec.PathItem, operation *spec.Operation) (map[string]diffParameter, error) {
+	params := make(map[string]diffParameter)
+
+	for _, param := range append(append([]spec.Parameter{}, pathItem.Parameters...), operation.Parameters...) {
+		if ref := param.Ref.String(); ref != "" {
+			globalParam, ok := swagger.Parameters[strings.TrimPrefix(ref, "#/parameters/")]
+			if !ok {
+				return nil, fmt.Errorf("parameter '%s' is not defined", ref)
+			}
+
+			param = globalParam
+		}
+
+		key := param.In + "." + param.Name
+		if param.In == "body" {
+			key = param.In
+		}
+
+		schema, err := swaggerParameterSchema(param)
+		if err != nil {
+			return nil, err
+		}
+
+		params[key] = diffParameter{in: param.In, required: param.Required, content: diffContent{"": schema}}
+	}
+
+	return params, nil
+}
+
+func swaggerParameterSchema(param spec.Parameter) (map[string]interface{}, error) {
+	if param.Schema != nil {
+		return toJSONObject(param.Schema)
+	}
+
+	return toJSONObject(param)
+}
+
+func swaggerResponses(responses *spec.Responses) (map[string]diffContent, error) {
+	if responses == nil {
+		return nil, nil
+	}
+
+	byCode := make(map[string]diffContent, len(responses.StatusCodeResponses)+1)
+
+	add := func(code string, response spec.Response) error {
+		if response.Schema == nil {
+			byCode[code] = nil
+			return nil
+		}
+
+		schema, err := toJSONObject(response.Schema)
+		if err != nil {
+			return err
+		}
+
+		byCode[code] = diffContent{"": schema}
+
+		return nil
+	}
+
+	if responses.Default != nil {
+		if err := add("default", *responses.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	for code, response := range responses.StatusCodeResponses {
+		if err := add(fmt.Sprint(code), response); err != nil {
+			return nil, err
+		}
+	}
+
+	return byCode, nil
+}
+
+func openAPIV3DiffPaths(openAPI *openAPIV3, schemas map[string]map[string]interface{}) (map[string]map[string]diffOperation, error) {
+	components := openAPI.Components
+	if components == nil {
+		components = &openAPIV3Components{}
+	}
+
+	for name, schema := range components.Schemas {
+		schemas[openAPIV3RefPrefixes["#/definitions/"]+name] = schema
+	}
+
+	paths := make(map[string]map[string]diffOperation)
+
+	for path, pathItem := range openAPI.Paths {
+		operations := make(map[string]diffOperation)
+
+		for method, operation := range openAPIV3Operations(pathItem) {
+			params, err := openAPIV3Parameters(components, pathItem, operation)
+			if err != nil {
+				return nil, err
+			}
+
+			responses := make(map[string]diffContent, len(operation.Responses))
+
+			for code, response := range operation.Responses {
+				if response.Ref != "" {
+					component, ok := components.Responses[strings.TrimPrefix(response.Ref, openAPIV3RefPrefixes["#/responses/"])]
+					if !ok {
+						return nil, fmt.Errorf("response '%s' is not defined", response.Ref)
+					}
+
+					response = component
+				}
+
+				responses[code] = openAPIV3Content(response.Content)
+			}
+
+			operations[method] = diffOperation{params: params, responses: responses}
+		}
+
+		paths[path] = operations
+	}
+
+	return paths, nil
+}
+
+func openAPIV3Operations(pathItem openAPIV3PathItem) map[string]*openAPIV3Operation {
+	operations := make(map[string]*openAPIV3Operation)
+
+	for method, operation := range map[string]*openAPIV3Operation{
+		http.MethodGet:     pathItem.Get,
+		http.MethodPut:     pathItem.Put,
+		http.MethodPost:    pathItem.Post,
+		http.MethodDelete:  pathItem.Delete,
+		http.MethodOptions: pathItem.Options,
+		http.MethodHead:    pathItem.Head,
+		http.MethodPatch:   pathItem.Patch,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+
+	return operations
+}
+
+// openAPIV3Parameters returns the parameters of an operation like swaggerParameters, its request body being the
+// body parameter.
+func openAPIV3Parameters(components *openAPIV3Components, pathItem openAPIV3PathItem, operation *openAPIV3Operation) (map[string]diffParameter, error) {
+	params := make(map[string]diffParameter)
+
+	for _, param := range append(append([]map[string]interface{}{}, pathItem.Parameters...), operation.Parameters...) {
+		if ref, ok := param["$ref"].(string); ok {
+			component, ok := components.Parameters[strings.TrimPrefix(ref, openAPIV3RefPrefixes["#/parameters/"])]
+			if !ok {
+				return nil, fmt.Errorf("parameter '%s' is not defined", ref)
+			}
+
+			param = component
+		}
+
+		in, _ := param["in"].(string)
+		name, _ := param["name"].(string)
+		required, _ := param["required"].(bool)
+		schema, _ := param["schema"].(map[string]interface{})
+
+		params[in+"."+name] = diffParameter{in: in, required: required, content: diffContent{"": schema}}
+	}
+
+	if requestBody := operation.RequestBody; requestBody != nil {
+		if requestBody.Ref != "" {
+			component, ok := components.RequestBodies[strings.TrimPrefix(requestBody.Ref, "#/components/requestBodies/")]
+			if !ok {
+				return nil, fmt.Errorf("request body '%s' is not defined", requestBody.Ref)
+			}
+
+			requestBody = &component
+		}
+
+		params["body"] = diffParameter{in: "body", required: requestBody.Required, content: openAPIV3Content(requestBody.Content)}
+	}
+
+	return params, nil
+}
+
+func openAPIV3Content(content map[string]openAPIV3MediaType) diffContent {
+	if len(content) == 0 {
+		return nil
+	}
+
+	schemas := make(diffContent, len(content))
+	for mediaType, media := range content {
+		schemas[mediaType] = media.Schema
+	}
+
+	return schemas
+}
+
+// diffAsyncAPIDoc holds the parts of an AsyncAPI document which are compared. The document isn't decoded with the
+// AsyncAPI spec types, whose oneOf constraints reject message lists. AsyncAPI 3 documents are converted into it,
+// their channels identified by address.
+type diffAsyncAPIDoc struct {
+	Channels   map[string]diffAsyncAPIChannel `json:"channels"`
+	Components struct {
+		Schemas  map[string]map[string]interface{} `json:"schemas"`
+		Messages map[string]diffAsyncAPIMessage    `json:"messages"`
+	} `json:"components"`
+
+	// version3 tells whether the operations are AsyncAPI 3 ones, the send and receive actions of the application
+	version3 bool
+}
+
+type diffAsyncAPIChannel struct {
+	Publish   *diffAsyncAPIOperation `json:"publish"`
+	Subscribe *diffAsyncAPIOperation `json:"subscribe"`
+}
+
+type diffAsyncAPIOperation struct {
+	Message diffAsyncAPIMessage `json:"message"`
+}
+
+type diffAsyncAPIMessage struct {
+	Ref       string                 `json:"$ref"`
+	MessageID string                 `json:"messageId"`
+	Name      string                 `json:"name"`
+	Payload   map[string]interface{} `json:"payload"`
+	OneOf     []diffAsyncAPIMessage  `json:"oneOf"`
+}
+
+// diffAsyncAPIV3Doc holds the parts of an AsyncAPI 3 document which are compared.
+type diffAsyncAPIV3Doc struct {
+	Channels map[string]struct {
+		Address  *string                        `json:"address"`
+		Messages map[string]diffAsyncAPIMessage `json:"messages"`
+	} `json:"channels"`
+	Operations map[string]struct {
+		Action   swag.OperationAction  `json:"action"`
+		Channel  asyncAPIV3Reference   `json:"channel"`
+		Messages []asyncAPIV3Reference `json:"messages"`
+	} `json:"operations"`
+}
+
+func decodeDiffAsyncAPI(doc []byte, kind string) (*diffAsyncAPIDoc, error) {
+	var asyncAPI diffAsyncAPIDoc
+	if err := json.Unmarshal(doc, &asyncAPI); err != nil {
+		return nil, err
+	}
+
+	if kind == diffAsyncAPI2 {
+		return &asyncAPI, nil
+	}
+
+	var asyncAPIV3 diffAsyncAPIV3Doc
+	if err := json.Unmarshal(doc, &asyncAPIV3); err != nil {
+		return nil, err
+	}
+
+	asyncAPI.Channels = make(map[string]diffAsyncAPIChannel, len(asyncAPIV3.Channels))
+	asyncAPI.version3 = true
+
+	// Messages are identified by name, the order of the operations and of their messages doesn't matter.
+	for _, operation := range asyncAPIV3.Operations {
+		ref := strings.TrimPrefix(operation.Channel.Ref, "#/channels/")
+
+		channel, ok := asyncAPIV3.Channels[jsonPointerUnescaper.Replace(ref)]
+		if !ok {
+			return nil, fmt.Errorf("channel '%s' is not defined", operation.Channel.Ref)
+		}
+
+		// An operation without messages sends or receives every message of its channel.
+		messageNames := make([]string, 0, len(channel.Messages))
+		for name := range channel.Messages {
+			messageNames = append(messageNames, name)
+		}
+
+		if len(operation.Messages) > 0 {
+			messageNames = messageNames[:0]
+
+			for _, message := range operation.Messages {
+				messageNames = append(messageNames, jsonPointerUnescaper.Replace(
+					strings.TrimPrefix(message.Ref, operation.Channel.Ref+"/messages/")))
+			}
+		}
+
+		var messages []diffAsyncAPIMessage
+
+		for _, name := range messageNames {
+			message, ok := channel.Messages[name]
+			if !ok {
+				return nil, fmt.Errorf("message '%s' of channel '%s' is not defined", name, operation.Channel.Ref)
+			}
+
+			if message.Ref == "" && message.MessageID == "" && message.Name == "" {
+				message.Name = name
+			}
+
+			messages = append(messages, message)
+		}
+
+		address := jsonPointerUnescaper.Replace(ref)
+		if channel.Address != nil {
+			address = *channel.Address
+		}
+
+		diffChannel := asyncAPI.Channels[address]
+
+		// Operations of the same channel and action are merged, like the messages of an AsyncAPI 2 operation.
+		target := &diffChannel.Subscribe
+		if operation.Action == swag.Receive {
+			target = &diffChannel.Publish
+		}
+
+		if *target == nil {
+			*target = &diffAsyncAPIOperation{}
+		}
+
+		(*target).Message.OneOf = append((*target).Message.OneOf, messages...)
+		asyncAPI.Channels[address] = diffChannel
+	}
+
+	return &asyncAPI, nil
+}
+
+// jsonPointerUnescaper unescapes a token of a JSON pointer, like a channel ID of a reference.
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func (d *schemaDiffer) diffAsyncAPI(oldAsyncAPI, newAsyncAPI *diffAsyncAPIDoc) {
+	for _, schemas := range []struct {
+		asyncAPI *diffAsyncAPIDoc
+		target   map[string]map[string]interface{}
+	}{
+		{oldAsyncAPI, d.oldSchemas},
+		{newAsyncAPI, d.newSchemas},
+	} {
+		for name, schema := range schemas.asyncAPI.Components.Schemas {
+			schemas.target[asyncAPISchemaPrefix+name] = schema
+		}
+	}
+
+	// AsyncAPI 3 operations are the actions of the application: it receives what clients publish and sends what
+	// they subscribe to.
+	publish, subscribe := "publish", "subscribe"
+	if newAsyncAPI.version3 {
+		publish, subscribe = string(swag.Receive), string(swag.Send)
+	}
+
+	for _, channelName := range unionKeys(oldAsyncAPI.Channels, newAsyncAPI.Channels) {
+		location := "channel " + channelName
+
+		oldChannel, inOld := oldAsyncAPI.Channels[channelName]
+		newChannel, inNew := newAsyncAPI.Channels[channelName]
+
+		switch {
+		case !inNew:
+			d.add(location, true, "channel was removed")
+			continue
+		case !inOld:
+			d.add(location, false, "channel was added")
+			continue
+		}
+
+		for _, operation := range []struct {
+			name      string
+			old, new  *diffAsyncAPIOperation
+			direction diffDirection
+		}{
+			// Clients publish the messages the application receives and subscribe to the messages it sends.
+			{name: publish, old: oldChannel.Publish, new: newChannel.Publish, direction: diffRequest},
+			{name: subscribe, old: oldChannel.Subscribe, new: newChannel.Subscribe, direction: diffResponse},
+		} {
+			switch {
+			case operation.old == nil && operation.new == nil:
+			case operation.new == nil:
+				d.add(location, true, "%s operation was removed", operation.name)
+			case operation.old == nil:
+				d.add(location, false, "%s operation was added", operation.name)
+			default:
+				d.diffAsyncAPIMessages(location+" "+operation.name,
+					asyncAPIPayloads(oldAsyncAPI, operation.old), asyncAPIPayloads(newAsyncAPI, operation.new), operation.direction)
+			}
+		}
+	}
+}
+
+func (d *schemaDiffer) diffAsyncAPIMessages(location string, oldPayloads, newPayloads map[string]map[string]interface{}, direction diffDirection) {
+	for _, messageName := range unionKeys(oldPayloads, newPayloads) {
+		oldPayload, inOld := oldPayloads[messageName]
+		newPayload, inNew := newPayloads[messageName]
+
+		switch {
+		case !inNew:
+			d.add(location, true, "message '%s' was removed", messageName)
+		case !inOld:
+			d.add(location, false, "message '%s' was added", messageName)
+		default:
+			d.diffSchema(location+" message "+messageName, oldPayload, newPayload, direction)
+		}
+	}
+}
+
+// asyncAPIPayloads returns the payloads of the messages of an operation by message ID, resolving references to the
+// messages of the components.
+func asyncAPIPayloads(asyncAPI *diffAsyncAPIDoc, operation *diffAsyncAPIOperation) map[string]map[string]interface{} {
+	payloads := make(map[string]map[string]interface{})
+
+	messages := operation.Message.OneOf
+	if len(messages) == 0 {
+		messages = []diffAsyncAPIMessage{operation.Message}
+	}
+
+	for _, message := range messages {
+		messageID := strings.TrimPrefix(message.Ref, asyncAPIMessagePrefix)
+
+		if message.Ref != "" {
+			message = asyncAPI.Components.Messages[messageID]
+		}
+
+		if message.MessageID != "" {
+			messageID = message.MessageID
+		} else if message.Name != "" {
+			messageID = message.Name
+		}
+
+		payloads[messageID] = message.Payload
+	}
+
+	return payloads
+}
+
+// diffSchema compares two schemas in the given direction: types, formats, enums, properties and items.
+func (d *schemaDiffer) diffSchema(location string, oldSchema, newSchema map[string]interface{}, direction diffDirection) {
+	oldSchema, oldRefs := d.flattenSchema(oldSchema, d.oldSchemas)
+	newSchema, newRefs := d.flattenSchema(newSchema, d.newSchemas)
+
+	if len(oldRefs) > 0 || len(newRefs) > 0 {
+		key := fmt.Sprint(oldRefs, newRefs, direction)
+		if d.refs[key] {
+			return
+		}
+
+		d.refs[key] = true
+		defer delete(d.refs, key)
+	}
+
+	oldType, newType := diffSchemaValue(oldSchema, "type"), diffSchemaValue(newSchema, "type")
+	if oldType != newType {
+		d.add(location, true, "type changed from '%s' to '%s'", oldType, newType)
+		return
+	}
+
+	if oldFormat, newFormat := diffSchemaValue(oldSchema, "format"), diffSchemaValue(newSchema, "format"); oldFormat != newFormat {
+		d.add(location, true, "format changed from '%s' to '%s'", oldFormat, newFormat)
+	}
+
+	d.diffEnum(location, oldSchema, newSchema, direction)
+	d.diffProperties(location, oldSchema, newSchema, direction)
+
+	for _, keyword := range []struct {
+		name   string
+		suffix string
+	}{
+		{name: "items", suffix: "[]"},
+		{name: "additionalProperties", suffix: "{}"},
+	} {
+		oldSubSchema, oldOK := oldSchema[keyword.name].(map[string]interface{})
+		newSubSchema, newOK := newSchema[keyword.name].(map[string]interface{})
+
+		if oldOK && newOK {
+			d.diffSchema(location+keyword.suffix, oldSubSchema, newSubSchema, direction)
+		}
+	}
+}
+
+func (d *schemaDiffer) diffEnum(location string, oldSchema, newSchema map[string]interface{}, direction diffDirection) {
+	oldEnum, oldOK := oldSchema["enum"].([]interface{})
+	newEnum, newOK := newSchema["enum"].([]interface{})
+
+	switch {
+	case !oldOK && !newOK:
+	case !oldOK:
+		d.add(location, direction == diffRequest, "enum was added")
+	case !newOK:
+		d.add(location, direction == diffResponse, "enum was removed")
+	default:
+		if removed := diffEnumValues(oldEnum, newEnum); len(removed) > 0 {
+			d.add(location, direction == diffRequest, "enum values %s were removed", strings.Join(removed, ", "))
+		}
+
+		if added := diffEnumValues(newEnum, oldEnum); len(added) > 0 {
+			d.add(location, direction == diffResponse, "enum values %s were added", strings.Join(added, ", "))
+		}
+	}
+}
+
+// diffEnumValues returns the JSON encoding of the values of enum missing from other.
+func diffEnumValues(enum, other []interface{}) []string {
+	otherValues := make(map[string]bool, len(other))
+	for _, value := range other {
+		b, _ := json.Marshal(value)
+		otherValues[string(b)] = true
+	}
+
+	var missing []string
+
+	for _, value := range enum {
+		b, _ := json.Marshal(value)
+		if !otherValues[string(b)] {
+			missing = append(missing, string(b))
+		}
+	}
+
+	return missing
+}
+
+func (d *schemaDiffer) diffProperties(location string, oldSchema, newSchema map[string]interface{}, direction diffDirection) {
+	oldProperties, _ := oldSchema["properties"].(map[string]interface{})
+	newProperties, _ := newSchema["properties"].(map[string]interface{})
+	oldRequired, newRequired := diffRequired(oldSchema), diffRequired(newSchema)
+
+	for _, name := range unionKeys(oldProperties, newProperties) {
+		oldProperty, inOld := oldProperties[name].(map[string]interface{})
+		newProperty, inNew := newProperties[name].(map[string]interface{})
+
+		switch {
+		case !inNew:
+			d.add(location, direction == diffResponse, "property '%s' was removed", name)
+		case !inOld && newRequired[name]:
+			d.add(location, direction == diffRequest, "required property '%s' was added", name)
+		case !inOld:
+			d.add(location, false, "property '%s' was added", name)
+		default:
+			if newRequired[name] && !oldRequired[name] {
+				d.add(location, direction == diffRequest, "property '%s' became required", name)
+			} else if oldRequired[name] && !newRequired[name] {
+				d.add(location, direction == diffResponse, "property '%s' became optional", name)
+			}
+
+			d.diffSchema(location+"."+name, oldProperty, newProperty, direction)
+		}
+	}
+}
+
+// flattenSchema resolves the reference of a schema and merges the schemas it is composed of with allOf. It
+// returns the resolved references, which identify the schema when comparing recursive schemas.
+func (d *schemaDiffer) flattenSchema(schema map[string]interface{}, schemas map[string]map[string]interface{}) (map[string]interface{}, []string) {
+	var refs []string
+
+	if ref, ok := schema["$ref"].(string); ok {
+		refs = append(refs, ref)
+		schema = schemas[ref]
+	}
+
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok {
+		return schema, refs
+	}
+
+	flattened := make(map[string]interface{}, len(schema))
+	properties := make(map[string]interface{})
+
+	var required []interface{}
+
+	merge := func(itemSchema map[string]interface{}) {
+		for key, value := range itemSchema {
+			switch key {
+			case "allOf":
+			case "properties":
+				itemProperties, _ := value.(map[string]interface{})
+				for name, property := range itemProperties {
+					properties[name] = property
+				}
+			case "required":
+				itemRequired, _ := value.([]interface{})
+				required = append(required, itemRequired...)
+			default:
+				flattened[key] = value
+			}
+		}
+	}
+
+	merge(schema)
+
+	for _, item := range allOf {
+		itemSchema, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		itemSchema, itemRefs := d.flattenSchema(itemSchema, schemas)
+		refs = append(refs, itemRefs...)
+
+		merge(itemSchema)
+	}
+
+	if len(properties) > 0 {
+		flattened["properties"] = properties
+	}
+
+	if len(required) > 0 {
+		flattened["required"] = required
+	}
+
+	return flattened, refs
+}
+
+func diffRequired(schema map[string]interface{}) map[string]bool {
+	required := make(map[string]bool)
+
+	items, _ := schema["required"].([]interface{})
+	for _, item := range items {
+		if name, ok := item.(string); ok {
+			required[name] = true
+		}
+	}
+
+	return required
+}
+
+// diffSchemaValue returns a keyword of a schema as text, lists like a type list included.
+func diffSchemaValue(schema map[string]interface{}, keyword string) string {
+	switch value := schema[keyword].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		b, _ := json.Marshal(value)
+		return string(b)
+	}
+}
+
+// unionKeys returns the sorted keys of both maps.
+func unionKeys[V any](oldMap, newMap map[string]V) []string {
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+
+	for key := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,540 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffSwaggerTestDoc = `{
+	"swagger": "2.0",
+	"paths": {
+		"/pets": {
+			"get": {
+				"parameters": [
+					{"name": "limit", "in": "query", "type": "integer"},
+					{"name": "status", "in": "query", "type": "string", "enum": ["available", "sold"]}
+				],
+				"responses": {
+					"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}
+				}
+			},
+			"post": {
+				"parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+				"responses": {"201": {"description": "Created"}}
+			}
+		},
+		"/pets/{id}": {
+			"delete": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "type": "integer"}],
+				"responses": {"204": {"description": "No Content"}}
+			}
+		}
+	},
+	"definitions": {
+		"Pet": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string"},
+				"kind": {"type": "string", "enum": ["cat", "dog"]},
+				"parent": {"$ref": "#/definitions/Pet"}
+			}
+		}
+	}
+}`
+
+func TestDiff_Swagger(t *testing.T) {
+	tests := []struct {
+		name     string
+		newDoc   string
+		expected []Change
+	}{
+		{
+			name:   "same document",
+			newDoc: diffSwaggerTestDoc,
+		},
+		{
+			name: "removed method and path",
+			newDoc: `{
+	"swagger": "2.0",
+	"paths": {
+		"/pets": {
+			"get": {
+				"parameters": [
+					{"name": "limit", "in": "query", "type": "integer"},
+					{"name": "status", "in": "query", "type": "string", "enum": ["available", "sold"]}
+				],
+				"responses": {
+					"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}
+				}
+			}
+		}
+	},
+	"definitions": {
+		"Pet": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string"},
+				"kind": {"type": "string", "enum": ["cat", "dog"]},
+				"parent": {"$ref": "#/definitions/Pet"}
+			}
+		}
+	}
+}`,
+			expected: []Change{
+				{Location: "/pets/{id}", Message: "path was removed", Breaking: true},
+				{Location: "POST /pets", Message: "operation was removed", Breaking: true},
+			},
+		},
+		{
+			name: "parameter and schema changes",
+			newDoc: `{
+	"swagger": "2.0",
+	"paths": {
+		"/pets": {
+			"get": {
+				"parameters": [
+					{"name": "limit", "in": "query", "type": "integer", "required": true},
+					{"name": "status", "in": "query", "type": "string", "enum": ["available"]},
+					{"name": "sort", "in": "query", "type": "string"}
+				],
+				"responses": {
+					"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+					"404": {"description": "Not Found"}
+				}
+			},
+			"post": {
+				"parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+				"responses": {"201": {"description": "Created"}}
+			}
+		},
+		"/pets/{id}": {
+			"delete": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+				"responses": {"204": {"description": "No Content"}}
+			}
+		}
+	},
+	"definitions": {
+		"Pet": {
+			"type": "object",
+			"required": ["name", "age"],
+			"properties": {
+				"age": {"type": "integer"},
+				"kind": {"type": "string", "enum": ["cat", "dog", "bird"]},
+				"parent": {"$ref": "#/definitions/Pet"}
+			}
+		}
+	}
+}`,
+			expected: []Change{
+				{Location: "DELETE /pets/{id} parameter path.id", Message: "type changed from 'integer' to 'string'", Breaking: true},
+				{Location: "GET /pets", Message: "parameter 'query.limit' became required", Breaking: true},
+				{Location: "GET /pets", Message: "parameter 'query.sort' was added", Breaking: false},
+				{Location: "GET /pets", Message: "response 404 was added", Breaking: false},
+				{Location: "GET /pets parameter query.status", Message: "enum values \"sold\" were removed", Breaking: true},
+				{Location: "GET /pets response 200 body[]", Message: "required property 'age' was added", Breaking: false},
+				{Location: "GET /pets response 200 body[]", Message: "property 'name' was removed", Breaking: true},
+				{Location: "GET /pets response 200 body[].kind", Message: "enum values \"bird\" were added", Breaking: true},
+				{Location: "POST /pets parameter body", Message: "required property 'age' was added", Breaking: true},
+				{Location: "POST /pets parameter body", Message: "property 'name' was removed", Breaking: false},
+				{Location: "POST /pets parameter body.kind", Message: "enum values \"bird\" were added", Breaking: false},
+			},
+		},
+		{
+			name: "removed parameters",
+			newDoc: `{
+	"swagger": "2.0",
+	"paths": {
+		"/pets": {
+			"get": {
+				"parameters": [{"name": "limit", "in": "query", "type": "integer"}],
+				"responses": {
+					"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}
+				}
+			},
+			"post": {
+				"responses": {"201": {"description": "Created"}}
+			}
+		},
+		"/pets/{id}": {
+			"delete": {
+				"responses": {"204": {"description": "No Content"}}
+			}
+		}
+	},
+	"definitions": {
+		"Pet": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string"},
+				"kind": {"type": "string", "enum": ["cat", "dog"]},
+				"parent": {"$ref": "#/definitions/Pet"}
+			}
+		}
+	}
+}`,
+			expected: []Change{
+				{Location: "DELETE /pets/{id}", Message: "parameter 'path.id' was removed", Breaking: true},
+				{Location: "GET /pets", Message: "parameter 'query.status' was removed", Breaking: false},
+				{Location: "POST /pets", Message: "parameter 'body' was removed", Breaking: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff([]byte(diffSwaggerTestDoc), []byte(tt.newDoc))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, changes)
+			assert.Equal(t, len(tt.expected) > 0, HasBreakingChanges(changes))
+		})
+	}
+}
+
+const diffAsyncAPITestDoc = `asyncapi: 2.4.0
+info:
+  title: Orders
+  version: "1.0"
+channels:
+  orders:
+    publish:
+      message:
+        $ref: '#/components/messages/OrderCancelled'
+    subscribe:
+      message:
+        oneOf:
+        - $ref: '#/components/messages/OrderCreated'
+        - $ref: '#/components/messages/OrderShipped'
+  audits:
+    subscribe:
+      message:
+        $ref: '#/components/messages/OrderCreated'
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        id:
+          type: string
+        total:
+          type: number
+  messages:
+    OrderCreated:
+      messageId: OrderCreated
+      payload:
+        $ref: '#/components/schemas/Order'
+    OrderShipped:
+      messageId: OrderShipped
+      payload:
+        type: object
+    OrderCancelled:
+      messageId: OrderCancelled
+      payload:
+        type: object
+        properties:
+          reason:
+            type: string
+`
+
+func TestDiff_AsyncAPI(t *testing.T) {
+	newDoc := `asyncapi: 2.4.0
+info:
+  title: Orders
+  version: "1.0"
+channels:
+  orders:
+    publish:
+      message:
+        $ref: '#/components/messages/OrderCancelled'
+    subscribe:
+      message:
+        $ref: '#/components/messages/OrderCreated'
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        id:
+          type: integer
+  messages:
+    OrderCreated:
+      messageId: OrderCreated
+      payload:
+        $ref: '#/components/schemas/Order'
+    OrderCancelled:
+      messageId: OrderCancelled
+      payload:
+        type: object
+        required: [reason]
+        properties:
+          reason:
+            type: string
+`
+
+	changes, err := Diff([]byte(diffAsyncAPITestDoc), []byte(newDoc))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Location: "channel audits", Message: "channel was removed", Breaking: true},
+		{Location: "channel orders publish message OrderCancelled", Message: "property 'reason' became required", Breaking: true},
+		{Location: "channel orders subscribe", Message: "message 'OrderShipped' was removed", Breaking: true},
+		{Location: "channel orders subscribe message OrderCreated", Message: "property 'total' was removed", Breaking: true},
+		{Location: "channel orders subscribe message OrderCreated.id", Message: "type changed from 'string' to 'integer'", Breaking: true},
+	}, changes)
+}
+
+const diffOpenAPIV3TestDoc = `{
+	"openapi": "3.1.0",
+	"paths": {
+		"/pets": {
+			"get": {
+				"parameters": [
+					{"$ref": "#/components/parameters/limit"},
+					{"name": "status", "in": "query", "schema": {"type": "string"}}
+				],
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}
+					}
+				}
+			},
+			"post": {
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}},
+						"application/xml": {"schema": {"$ref": "#/components/schemas/Pet"}}
+					}
+				},
+				"responses": {"201": {"$ref": "#/components/responses/Created"}}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"Pet": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {"name": {"type": "string"}, "tag": {"type": ["string", "null"]}}
+			}
+		},
+		"parameters": {
+			"limit": {"name": "limit", "in": "query", "required": true, "schema": {"type": "integer"}}
+		},
+		"responses": {
+			"Created": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}
+		}
+	}
+}`
+
+func TestDiff_OpenAPIV3(t *testing.T) {
+	newDoc := `openapi: 3.1.0
+paths:
+  /pets:
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "201":
+          description: Created
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name, age]
+      properties:
+        name:
+          type: string
+        age:
+          type: integer
+        tag:
+          type: string
+`
+
+	changes, err := Diff([]byte(diffOpenAPIV3TestDoc), []byte(newDoc))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Location: "GET /pets", Message: "parameter 'query.limit' became optional", Breaking: false},
+		{Location: "GET /pets", Message: "parameter 'query.status' was removed", Breaking: false},
+		{Location: "GET /pets parameter query.limit", Message: "type changed from 'integer' to 'string'", Breaking: true},
+		{Location: "GET /pets response 200 body application/json[]", Message: "required property 'age' was added", Breaking: false},
+		{Location: "GET /pets response 200 body application/json[].tag", Message: "type changed from '[\"string\",\"null\"]' to 'string'", Breaking: true},
+		{Location: "POST /pets", Message: "body of response 201 was removed", Breaking: true},
+		{Location: "POST /pets parameter body", Message: "media type 'application/xml' was removed", Breaking: true},
+		{Location: "POST /pets parameter body application/json", Message: "required property 'age' was added", Breaking: true},
+		{Location: "POST /pets parameter body application/json.tag", Message: "type changed from '[\"string\",\"null\"]' to 'string'", Breaking: true},
+	}, changes)
+}
+
+func TestDiff_AsyncAPIV3(t *testing.T) {
+	oldDoc := `asyncapi: 3.0.0
+channels:
+  orders:
+    address: orders
+    messages:
+      OrderCreated:
+        $ref: '#/components/messages/OrderCreated'
+      OrderCancelled:
+        $ref: '#/components/messages/OrderCancelled'
+  tenants_tenantId_orders:
+    address: tenants/{tenantId}/orders
+    messages:
+      OrderCreated:
+        $ref: '#/components/messages/OrderCreated'
+operations:
+  OnOrderChanged:
+    action: send
+    channel:
+      $ref: '#/channels/orders'
+    messages:
+    - $ref: '#/channels/orders/messages/OrderCreated'
+    - $ref: '#/channels/orders/messages/OrderCancelled'
+  OnOrderAudited:
+    action: receive
+    channel:
+      $ref: '#/channels/orders'
+  OnTenantOrderCreated:
+    action: send
+    channel:
+      $ref: '#/channels/tenants_tenantId_orders'
+components:
+  messages:
+    OrderCreated:
+      name: OrderCreated
+      payload:
+        $ref: '#/components/schemas/Order'
+    OrderCancelled:
+      name: OrderCancelled
+      payload:
+        type: object
+  schemas:
+    Order:
+      type: object
+      properties:
+        id:
+          type: string
+`
+
+	newDoc := `asyncapi: 3.0.0
+channels:
+  orders:
+    address: orders
+    messages:
+      OrderCreated:
+        $ref: '#/components/messages/OrderCreated'
+operations:
+  OnOrderChanged:
+    action: send
+    channel:
+      $ref: '#/channels/orders'
+    messages:
+    - $ref: '#/channels/orders/messages/OrderCreated'
+  OnOrderAudited:
+    action: receive
+    channel:
+      $ref: '#/channels/orders'
+components:
+  messages:
+    OrderCreated:
+      name: OrderCreated
+      payload:
+        $ref: '#/components/schemas/Order'
+  schemas:
+    Order:
+      type: object
+      properties:
+        id:
+          type: integer
+`
+
+	changes, err := Diff([]byte(oldDoc), []byte(newDoc))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Location: "channel orders receive", Message: "message 'OrderCancelled' was removed", Breaking: true},
+		{Location: "channel orders receive message OrderCreated.id", Message: "type changed from 'string' to 'integer'", Breaking: true},
+		{Location: "channel orders send", Message: "message 'OrderCancelled' was removed", Breaking: true},
+		{Location: "channel orders send message OrderCreated.id", Message: "type changed from 'string' to 'integer'", Breaking: true},
+		{Location: "channel tenants/{tenantId}/orders", Message: "channel was removed", Breaking: true},
+	}, changes)
+}
+
+func TestDiff_InvalidDocuments(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldDoc      string
+		newDoc      string
+		expectedErr string
+	}{
+		{
+			name:        "different kinds",
+			oldDoc:      diffSwaggerTestDoc,
+			newDoc:      diffAsyncAPITestDoc,
+			expectedErr: "can't compare a Swagger 2.0 document with a AsyncAPI 2 document",
+		},
+		{
+			name:        "different versions",
+			oldDoc:      diffSwaggerTestDoc,
+			newDoc:      diffOpenAPIV3TestDoc,
+			expectedErr: "can't compare a Swagger 2.0 document with a OpenAPI 3 document",
+		},
+		{
+			name:        "unsupported version",
+			oldDoc:      `{"asyncapi": "1.2.0"}`,
+			newDoc:      diffAsyncAPITestDoc,
+			expectedErr: "old document is invalid: unsupported AsyncAPI version 1.2.0",
+		},
+		{
+			name:        "unknown document",
+			oldDoc:      diffSwaggerTestDoc,
+			newDoc:      `{"info": {"title": "Pets"}}`,
+			expectedErr: "new document is invalid: not a Swagger, OpenAPI or AsyncAPI document",
+		},
+		{
+			name:        "undefined parameter",
+			oldDoc:      diffSwaggerTestDoc,
+			newDoc:      `{"swagger": "2.0", "paths": {"/pets": {"get": {"parameters": [{"$ref": "#/parameters/limit"}]}}}}`,
+			expectedErr: "new document is invalid: parameter '#/parameters/limit' is not defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Diff([]byte(tt.oldDoc), []byte(tt.newDoc))
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestChange_String(t *testing.T) {
+	assert.Equal(t, "breaking: GET /pets: operation was removed",
+		Change{Location: "GET /pets", Message: "operation was removed", Breaking: true}.String())
+	assert.Equal(t, "non-breaking: channel orders: channel was added",
+		Change{Location: "channel orders", Message: "channel was added"}.String())
+}
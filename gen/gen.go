@@ -11,6 +11,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -19,6 +21,7 @@ import (
 	asyncReflector "github.com/swaggest/go-asyncapi/reflector/asyncapi-2.4.0"
 	asyncSpec "github.com/swaggest/go-asyncapi/spec-2.4.0"
 	"github.com/yalochat/swag"
+	"github.com/yalochat/swag/asyncswag"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"sigs.k8s.io/yaml"
@@ -38,6 +41,8 @@ type Gen struct {
 	jsonToYAML    func(data []byte) ([]byte, error)
 	outputTypeMap map[string]genTypeWriter
 	debug         Debugger
+	// parser is the parser of the last build, used by the writers of the AsyncAPI output types
+	parser *swag.Parser
 }
 
 // Debugger is the interface that wraps the basic Printf method.
@@ -61,6 +66,8 @@ func New() *Gen {
 		"json": gen.writeJSONSwagger,
 		"yaml": gen.writeYAMLSwagger,
 		"yml":  gen.writeYAMLSwagger,
+
+		asyncGoOutputType: gen.writeAsyncGo,
 	}
 
 	return &gen
@@ -116,6 +123,25 @@ type Config struct {
 	// Strict whether swag should error or warn when it detects cases which are most likely user errors
 	Strict bool
 
+	// CollectErrors whether swag should keep parsing after an invalid annotation and report all the errors
+	CollectErrors bool
+
+	// InferRoutes whether swag should bind the routes registered with gin, echo, chi or net/http routers to the
+	// handlers without @Router
+	InferRoutes bool
+
+	// InferSchemas whether swag should infer the request body and the responses of the handlers by type-checking
+	// their code
+	InferSchemas bool
+
+	// CacheDir the directory where swag caches the operations and the definitions parsed from every file, the
+	// cache is disabled when empty
+	CacheDir string
+
+	// ParseWorkers the number of workers parsing the Go source files and collecting their operations concurrently, the
+	// number of CPUs when it isn't positive
+	ParseWorkers int
+
 	// GeneratedTime whether swag should generate the timestamp at the top of docs.go
 	GeneratedTime bool
 
@@ -151,6 +177,15 @@ type Config struct {
 
 	// ParseFuncBody whether swag should parse api info inside of funcs
 	ParseFuncBody bool
+
+	// AsyncAPIVersions define which AsyncAPI specification versions should be generated: 2.4.0 (asyncapi.yaml),
+	// 3.0.0 (asyncapi_v3.yaml). The default value is 2.4.0.
+	AsyncAPIVersions []string
+
+	// OpenAPIVersion defines which OpenAPI specification version the json and yaml output types describe: 2.0
+	// (swagger.json, swagger.yaml) or 3.1.0 (openapi.json, openapi.yaml). The default value is 2.0, 3.1.0 can't be
+	// used with the go output type.
+	OpenAPIVersion string
 }
 
 // Build builds swagger json file  for given searchDir and mainAPIFile. Returns json.
@@ -162,6 +197,24 @@ func (g *Gen) Build(config *Config) error {
 		config.InstanceName = swag.Name
 	}
 
+	if len(config.AsyncAPIVersions) == 0 {
+		config.AsyncAPIVersions = []string{asyncAPIVersion2}
+	}
+
+	for _, version := range config.AsyncAPIVersions {
+		if version != asyncAPIVersion2 && version != asyncAPIVersion3 {
+			return fmt.Errorf("not supported %s AsyncAPI version", version)
+		}
+	}
+
+	if config.OpenAPIVersion == "" {
+		config.OpenAPIVersion = openAPIVersion2
+	}
+
+	if config.OpenAPIVersion != openAPIVersion2 && config.OpenAPIVersion != openAPIVersion31 {
+		return fmt.Errorf("not supported %s OpenAPI version", config.OpenAPIVersion)
+	}
+
 	searchDirs := strings.Split(config.SearchDir, ",")
 	for _, searchDir := range searchDirs {
 		if _, err := os.Stat(searchDir); os.IsNotExist(err) {
@@ -177,53 +230,21 @@ func (g *Gen) Build(config *Config) error {
 		config.RightTemplateDelim = "}}"
 	}
 
-	var overrides map[string]string
-
-	if config.OverridesFile != "" {
-		overridesFile, err := open(config.OverridesFile)
-		if err != nil {
-			// Don't bother reporting if the default file is missing; assume there are no overrides
-			if !(config.OverridesFile == DefaultOverridesFile && os.IsNotExist(err)) {
-				return fmt.Errorf("could not open overrides file: %w", err)
-			}
-		} else {
-			g.debug.Printf("Using overrides from %s", config.OverridesFile)
-
-			overrides, err = parseOverrides(overridesFile)
-			if err != nil {
-				return err
-			}
-		}
+	overrides, err := g.readOverrides(config)
+	if err != nil {
+		return err
 	}
 
 	g.debug.Printf("Generate swagger docs....")
 
-	p := swag.New(
-		swag.SetParseDependency(config.ParseDependency),
-		swag.SetMarkdownFileDirectory(config.MarkdownFilesDir),
-		swag.SetDebugger(config.Debugger),
-		swag.SetExcludedDirsAndFiles(config.Excludes),
-		swag.SetParseExtension(config.ParseExtension),
-		swag.SetCodeExamplesDirectory(config.CodeExampleFilesDir),
-		swag.SetStrict(config.Strict),
-		swag.SetOverrides(overrides),
-		swag.ParseUsingGoList(config.ParseGoList),
-		swag.SetTags(config.Tags),
-		swag.SetCollectionFormat(config.CollectionFormat),
-		swag.SetPackagePrefix(config.PackagePrefix),
-	)
-
-	p.PropNamingStrategy = config.PropNamingStrategy
-	p.ParseVendor = config.ParseVendor
-	p.ParseInternal = config.ParseInternal
-	p.RequiredByDefault = config.RequiredByDefault
-	p.HostState = config.State
-	p.ParseFuncBody = config.ParseFuncBody
+	p := newParser(config, overrides)
 
 	if err := p.ParseAPIMultiSearchDir(searchDirs, config.MainAPIFile, config.ParseDepth); err != nil {
 		return err
 	}
 
+	g.parser = p
+
 	swagger := p.GetSwagger()
 
 	if err := os.MkdirAll(config.OutputDir, os.ModePerm); err != nil {
@@ -250,6 +271,59 @@ func (g *Gen) Build(config *Config) error {
 	return nil
 }
 
+// readOverrides reads the global type overrides of the configuration, a missing default file isn't an error.
+func (g *Gen) readOverrides(config *Config) (map[string]string, error) {
+	if config.OverridesFile == "" {
+		return nil, nil
+	}
+
+	overridesFile, err := open(config.OverridesFile)
+	if err != nil {
+		// Don't bother reporting if the default file is missing; assume there are no overrides
+		if !(config.OverridesFile == DefaultOverridesFile && os.IsNotExist(err)) {
+			return nil, fmt.Errorf("could not open overrides file: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	g.debug.Printf("Using overrides from %s", config.OverridesFile)
+
+	return parseOverrides(overridesFile)
+}
+
+// newParser creates the parser of the configuration.
+func newParser(config *Config, overrides map[string]string, options ...func(*swag.Parser)) *swag.Parser {
+	p := swag.New(append([]func(*swag.Parser){
+		swag.SetParseDependency(config.ParseDependency),
+		swag.SetMarkdownFileDirectory(config.MarkdownFilesDir),
+		swag.SetDebugger(config.Debugger),
+		swag.SetExcludedDirsAndFiles(config.Excludes),
+		swag.SetParseExtension(config.ParseExtension),
+		swag.SetCodeExamplesDirectory(config.CodeExampleFilesDir),
+		swag.SetStrict(config.Strict),
+		swag.SetCollectErrors(config.CollectErrors),
+		swag.SetInferRoutes(config.InferRoutes),
+		swag.SetInferSchemas(config.InferSchemas),
+		swag.SetCacheDir(config.CacheDir),
+		swag.SetParseWorkers(config.ParseWorkers),
+		swag.SetOverrides(overrides),
+		swag.ParseUsingGoList(config.ParseGoList),
+		swag.SetTags(config.Tags),
+		swag.SetCollectionFormat(config.CollectionFormat),
+		swag.SetPackagePrefix(config.PackagePrefix),
+	}, options...)...)
+
+	p.PropNamingStrategy = config.PropNamingStrategy
+	p.ParseVendor = config.ParseVendor
+	p.ParseInternal = config.ParseInternal
+	p.RequiredByDefault = config.RequiredByDefault
+	p.HostState = config.State
+	p.ParseFuncBody = config.ParseFuncBody
+
+	return p
+}
+
 func processAsyncAPI(p *swag.Parser, swagger *spec.Swagger, config *Config) error {
 	asyncAPI := p.GetAsyncAPI()
 
@@ -272,7 +346,70 @@ func processAsyncAPI(p *swag.Parser, swagger *spec.Swagger, config *Config) erro
 		return err
 	}
 
-	return writeDocAsyncAPI(asyncAPI, fmt.Sprintf("%s/asyncapi.yaml", config.OutputDir))
+	processAsyncAPIDiscriminators(asyncAPI)
+
+	for _, version := range config.AsyncAPIVersions {
+		switch version {
+		case asyncAPIVersion2:
+			if err := writeDocAsyncAPI(asyncAPI, fmt.Sprintf("%s/asyncapi.yaml", config.OutputDir)); err != nil {
+				return err
+			}
+		case asyncAPIVersion3:
+			doc, err := newAsyncAPIV3(asyncAPI, p.GetAsyncAPIOperations())
+			if err != nil {
+				return err
+			}
+
+			if err := writeDocAsyncAPIV3(doc, fmt.Sprintf("%s/asyncapi_v3.yaml", config.OutputDir)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the AsyncAPI document embedded in docs.go, described with the first generated AsyncAPI version. Its info
+// is filled at runtime from the AsyncSpec, like the Swagger document.
+func (g *Gen) asyncAPIDocTemplate(config *Config) ([]byte, error) {
+	if g.parser == nil {
+		return nil, nil
+	}
+
+	asyncAPI := *g.parser.GetAsyncAPI()
+	if len(asyncAPI.Servers) == 0 && len(asyncAPI.Channels) == 0 {
+		return nil, nil
+	}
+
+	asyncAPI.Info.Title = config.LeftTemplateDelim + ".Title" + config.RightTemplateDelim
+	asyncAPI.Info.Description = config.LeftTemplateDelim + "escape .Description" + config.RightTemplateDelim
+	asyncAPI.Info.Version = config.LeftTemplateDelim + ".Version" + config.RightTemplateDelim
+
+	if len(config.AsyncAPIVersions) > 0 && config.AsyncAPIVersions[0] == asyncAPIVersion3 {
+		doc, err := newAsyncAPIV3(&asyncAPI, g.parser.GetAsyncAPIOperations())
+		if err != nil {
+			return nil, err
+		}
+
+		return g.jsonIndent(doc)
+	}
+
+	return g.jsonIndent(&asyncAPI)
+}
+
+// Returns the name the AsyncAPI document of docs.go is registered with.
+func asyncAPIInstanceName(config *Config) string {
+	name := asyncswag.Name
+
+	if config.State != "" {
+		name = config.State + "_" + name
+	}
+
+	if config.InstanceName != swag.Name {
+		name = config.InstanceName + "_" + name
+	}
+
+	return name
 }
 
 // Updates the AsyncAPI `Info` object with information from the Swagger spec.
@@ -287,7 +424,7 @@ func processAsyncAPIChannels(asyncAPI *asyncSpec.AsyncAPI) error {
 	reflector := &asyncReflector.Reflector{Schema: asyncAPI}
 
 	for channelName, channel := range asyncAPI.Channels {
-		if err := validateAsyncAPIChannel(asyncAPI, &channel); err != nil {
+		if err := validateAsyncAPIChannel(asyncAPI, channelName, &channel); err != nil {
 			return fmt.Errorf("channel '%s' is invalid: %w", channelName, err)
 		}
 
@@ -306,7 +443,7 @@ func processAsyncAPIDefinitions(p *swag.Parser, asyncAPI *asyncSpec.AsyncAPI, sw
 		schemaInParsed, _ := findSchemaInParsedSchemas(p, definitionKey)
 
 		if schemaInParsed.UsedForAsyncAPI {
-			schema, err := createAsyncAPISchema(&definition)
+			schema, err := swag.CreateAsyncAPISchema(&definition)
 			if err != nil {
 				return err
 			}
@@ -322,21 +459,126 @@ func processAsyncAPIDefinitions(p *swag.Parser, asyncAPI *asyncSpec.AsyncAPI, sw
 	return nil
 }
 
-// Creates an AsyncAPI schema from a Swagger definition.
-func createAsyncAPISchema(definition *spec.Schema) (map[string]interface{}, error) {
-	schema := map[string]interface{}{
-		"type": definition.Type[0],
+// Adds a discriminator to the payload schemas of the operations carrying several message types, when the payloads
+// share a string property holding a distinct constant value (a single enum value) for every message type.
+func processAsyncAPIDiscriminators(asyncAPI *asyncSpec.AsyncAPI) {
+	channelNames := make([]string, 0, len(asyncAPI.Channels))
+	for channelName := range asyncAPI.Channels {
+		channelNames = append(channelNames, channelName)
 	}
 
-	if definition.Type[0] == swag.OBJECT {
-		properties, err := swag.GetAsyncAPISchemaProperties(definition)
-		if err != nil {
-			return nil, err
+	sort.Strings(channelNames)
+
+	for _, channelName := range channelNames {
+		channel := asyncAPI.Channels[channelName]
+
+		for _, operation := range []*asyncSpec.Operation{channel.Publish, channel.Subscribe} {
+			if operation == nil {
+				continue
+			}
+
+			messages := findAsyncAPIMessages(asyncAPI, operation.Message)
+			if len(messages) < 2 {
+				continue
+			}
+
+			schemas := make([]map[string]interface{}, 0, len(messages))
+			for _, message := range messages {
+				schema := findAsyncAPIPayloadSchema(asyncAPI, message.Payload)
+				if schema == nil {
+					break
+				}
+
+				schemas = append(schemas, schema)
+			}
+
+			if len(schemas) != len(messages) {
+				continue
+			}
+
+			discriminator := findAsyncAPIDiscriminator(schemas)
+			if discriminator == "" {
+				continue
+			}
+
+			for _, schema := range schemas {
+				// A payload shared by several channels keeps the discriminator found first.
+				if _, ok := schema["discriminator"]; !ok {
+					schema["discriminator"] = discriminator
+				}
+			}
+		}
+	}
+}
+
+// Returns the object schema of a message payload, resolving references to the schemas of the components.
+func findAsyncAPIPayloadSchema(asyncAPI *asyncSpec.AsyncAPI, payload map[string]interface{}) map[string]interface{} {
+	if ref, ok := payload["$ref"].(string); ok {
+		if asyncAPI.Components == nil {
+			return nil
 		}
-		schema["properties"] = properties
+
+		payload = asyncAPI.Components.Schemas[strings.TrimPrefix(ref, asyncAPISchemaPrefix)]
 	}
 
-	return schema, nil
+	if _, ok := payload["properties"].(map[string]interface{}); !ok {
+		return nil
+	}
+
+	return payload
+}
+
+// Returns the first property, in alphabetical order, that tells the given object schemas apart.
+func findAsyncAPIDiscriminator(schemas []map[string]interface{}) string {
+	properties, _ := schemas[0]["properties"].(map[string]interface{})
+
+	propertyNames := make([]string, 0, len(properties))
+	for propertyName := range properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+
+	sort.Strings(propertyNames)
+
+	for _, propertyName := range propertyNames {
+		values := make(map[string]struct{}, len(schemas))
+
+		for _, schema := range schemas {
+			properties, _ := schema["properties"].(map[string]interface{})
+			property, _ := properties[propertyName].(map[string]interface{})
+
+			value, ok := asyncAPITagValue(property)
+			if !ok {
+				break
+			}
+
+			values[value] = struct{}{}
+		}
+
+		if len(values) == len(schemas) {
+			return propertyName
+		}
+	}
+
+	return ""
+}
+
+// Returns the constant value of a string property, declared either with const or with a single enum value.
+func asyncAPITagValue(property map[string]interface{}) (string, bool) {
+	if property["type"] != "string" {
+		return "", false
+	}
+
+	if value, ok := property["const"].(string); ok {
+		return value, true
+	}
+
+	enum, _ := property["enum"].([]interface{})
+	if len(enum) != 1 {
+		return "", false
+	}
+
+	value, ok := enum[0].(string)
+	return value, ok
 }
 
 func findSchemaInParsedSchemas(parser *swag.Parser, schemaName string) (*swag.Schema, error) {
@@ -349,6 +591,18 @@ func findSchemaInParsedSchemas(parser *swag.Parser, schemaName string) (*swag.Sc
 	return nil, fmt.Errorf("unable to find schema for '%s'", schemaName)
 }
 
+// asyncAPIPlaceholderPattern matches the {name} placeholders of server URLs and channel names.
+var asyncAPIPlaceholderPattern = regexp.MustCompile(`{([^{}]+)}`)
+
+func hasAsyncAPISecurityScheme(asyncAPI *asyncSpec.AsyncAPI, schemeName string) bool {
+	if asyncAPI.Components == nil || asyncAPI.Components.SecuritySchemes == nil {
+		return false
+	}
+
+	_, ok := asyncAPI.Components.SecuritySchemes.MapOfComponentsSecuritySchemesWDValues[schemeName]
+	return ok
+}
+
 func validateAsyncAPIServers(asyncAPI *asyncSpec.AsyncAPI) error {
 	if len(asyncAPI.Servers) == 0 {
 		return fmt.Errorf("AsyncAPI spec must have at least one server")
@@ -361,12 +615,26 @@ func validateAsyncAPIServers(asyncAPI *asyncSpec.AsyncAPI) error {
 		if server.Server.Protocol == "" {
 			return fmt.Errorf("protocol for server '%s' not provided", serverName)
 		}
+
+		for _, match := range asyncAPIPlaceholderPattern.FindAllStringSubmatch(server.Server.URL, -1) {
+			if _, ok := server.Server.Variables[match[1]]; !ok {
+				return fmt.Errorf("variable '%s' used by server '%s' is not declared", match[1], serverName)
+			}
+		}
+
+		for _, requirement := range server.Server.Security {
+			for schemeName := range requirement {
+				if !hasAsyncAPISecurityScheme(asyncAPI, schemeName) {
+					return fmt.Errorf("security scheme '%s' used by server '%s' is not declared", schemeName, serverName)
+				}
+			}
+		}
 	}
 
 	return nil
 }
 
-func validateAsyncAPIChannel(asyncAPI *asyncSpec.AsyncAPI, channel *asyncSpec.ChannelItem) error {
+func validateAsyncAPIChannel(asyncAPI *asyncSpec.AsyncAPI, channelName string, channel *asyncSpec.ChannelItem) error {
 	if len(channel.Servers) == 0 {
 		return fmt.Errorf("some operation is using a channel that was not defined")
 	}
@@ -375,16 +643,115 @@ func validateAsyncAPIChannel(asyncAPI *asyncSpec.AsyncAPI, channel *asyncSpec.Ch
 	if _, ok := asyncAPI.Servers[channelServer]; !ok {
 		return fmt.Errorf("server '%s' not defined in AsyncAPI spec", channelServer)
 	}
+
+	if err := validateAsyncAPIChannelParameters(channelName, channel); err != nil {
+		return err
+	}
+
+	return validateAsyncAPIBindings(asyncAPI, channel)
+}
+
+// Validates that every {param} placeholder of a channel name is declared, and every declared parameter is used.
+func validateAsyncAPIChannelParameters(channelName string, channel *asyncSpec.ChannelItem) error {
+	placeholders := make(map[string]struct{})
+
+	for _, match := range asyncAPIPlaceholderPattern.FindAllStringSubmatch(channelName, -1) {
+		placeholders[match[1]] = struct{}{}
+
+		if _, ok := channel.Parameters[match[1]]; !ok {
+			return fmt.Errorf("parameter '%s' is not declared, add a @channel.param line for it", match[1])
+		}
+	}
+
+	paramNames := make([]string, 0, len(channel.Parameters))
+	for paramName := range channel.Parameters {
+		paramNames = append(paramNames, paramName)
+	}
+
+	sort.Strings(paramNames)
+
+	for _, paramName := range paramNames {
+		if _, ok := placeholders[paramName]; !ok {
+			return fmt.Errorf("parameter '%s' is declared but not used in the channel name", paramName)
+		}
+	}
+
+	return nil
+}
+
+// Validates that the bindings of a channel, of its operations and of their messages match the protocol of the channel servers.
+func validateAsyncAPIBindings(asyncAPI *asyncSpec.AsyncAPI, channel *asyncSpec.ChannelItem) error {
+	bindings := []interface{}{channel.Bindings}
+
+	for _, operation := range []*asyncSpec.Operation{channel.Publish, channel.Subscribe} {
+		if operation == nil {
+			continue
+		}
+
+		bindings = append(bindings, operation.Bindings)
+
+		for _, message := range findAsyncAPIMessages(asyncAPI, operation.Message) {
+			bindings = append(bindings, message.Bindings)
+		}
+	}
+
+	for _, binding := range bindings {
+		data, err := json.Marshal(binding)
+		if err != nil {
+			return err
+		}
+
+		var protocols map[string]interface{}
+		if err := json.Unmarshal(data, &protocols); err != nil {
+			return err
+		}
+
+		for protocol := range protocols {
+			for _, serverName := range channel.Servers {
+				server := asyncAPI.Servers[serverName].Server
+				if server != nil && !swag.IsAsyncAPIBindingCompatible(protocol, server.Protocol) {
+					return fmt.Errorf("%s bindings can't be used with server '%s' using protocol '%s'", protocol, serverName, server.Protocol)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
+// Returns the messages of an operation, resolving references to the messages of the components.
+func findAsyncAPIMessages(asyncAPI *asyncSpec.AsyncAPI, message *asyncSpec.Message) []*asyncSpec.MessageEntity {
+	var entities []*asyncSpec.MessageEntity
+
+	for _, item := range swag.AsyncAPIMessages(message) {
+		if item.Reference != nil {
+			if asyncAPI.Components == nil {
+				continue
+			}
+
+			component, ok := asyncAPI.Components.Messages[strings.TrimPrefix(item.Reference.Ref, asyncAPIMessagePrefix)]
+			if !ok {
+				continue
+			}
+
+			item = component
+		}
+
+		if item.OneOf1 != nil && item.OneOf1.MessageEntity != nil {
+			entities = append(entities, item.OneOf1.MessageEntity)
+		}
+	}
+
+	return entities
+}
+
 // Generate creates the AsyncAPI spec file.
 func writeDocAsyncAPI(asyncAPI *asyncSpec.AsyncAPI, outputFile string) error {
 	yaml, err := asyncAPI.MarshalYAML()
 	if err != nil {
 		return fmt.Errorf("failed to marshal AsyncAPI spec: %w", err)
 	}
-	if err := os.WriteFile(outputFile, yaml, 0644); err != nil {
+	if err := writeFileIfChanged(outputFile, yaml); err != nil {
 		return fmt.Errorf("failed to write AsyncAPI spec file: %w", err)
 	}
 
@@ -405,27 +772,20 @@ func (g *Gen) writeDocSwagger(config *Config, swagger *spec.Swagger) error {
 
 	docFileName := path.Join(config.OutputDir, filename)
 
-	absOutputDir, err := filepath.Abs(config.OutputDir)
+	packageName, err := goPackageName(config)
 	if err != nil {
 		return err
 	}
 
-	var packageName string
-	if len(config.PackageName) > 0 {
-		packageName = config.PackageName
-	} else {
-		packageName = filepath.Base(absOutputDir)
-		packageName = strings.ReplaceAll(packageName, "-", "_")
-	}
+	var docs bytes.Buffer
 
-	docs, err := os.Create(docFileName)
+	// Write doc
+	err = g.writeGoDoc(packageName, &docs, swagger, config)
 	if err != nil {
 		return err
 	}
-	defer docs.Close()
 
-	// Write doc
-	err = g.writeGoDoc(packageName, docs, swagger, config)
+	err = g.writeFile(docs.Bytes(), docFileName)
 	if err != nil {
 		return err
 	}
@@ -435,8 +795,42 @@ func (g *Gen) writeDocSwagger(config *Config, swagger *spec.Swagger) error {
 	return nil
 }
 
+// goPackageName returns the package name of the generated Go files.
+func goPackageName(config *Config) (string, error) {
+	if len(config.PackageName) > 0 {
+		return config.PackageName, nil
+	}
+
+	absOutputDir, err := filepath.Abs(config.OutputDir)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(filepath.Base(absOutputDir), "-", "_"), nil
+}
+
+// openAPIDoc returns the document written by the json and yaml output types and the base name of its file: the
+// Swagger 2.0 document itself or its OpenAPI 3.1 conversion.
+func openAPIDoc(config *Config, swagger *spec.Swagger) (interface{}, string, error) {
+	if config.OpenAPIVersion != openAPIVersion31 {
+		return swagger, "swagger", nil
+	}
+
+	doc, err := newOpenAPIV3(swagger)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to convert to OpenAPI %s: %w", openAPIVersion31, err)
+	}
+
+	return doc, "openapi", nil
+}
+
 func (g *Gen) writeJSONSwagger(config *Config, swagger *spec.Swagger) error {
-	var filename = "swagger.json"
+	doc, name, err := openAPIDoc(config, swagger)
+	if err != nil {
+		return err
+	}
+
+	var filename = name + ".json"
 
 	if config.State != "" {
 		filename = config.State + "_" + filename
@@ -448,7 +842,7 @@ func (g *Gen) writeJSONSwagger(config *Config, swagger *spec.Swagger) error {
 
 	jsonFileName := path.Join(config.OutputDir, filename)
 
-	b, err := g.jsonIndent(swagger)
+	b, err := g.jsonIndent(doc)
 	if err != nil {
 		return err
 	}
@@ -458,13 +852,18 @@ func (g *Gen) writeJSONSwagger(config *Config, swagger *spec.Swagger) error {
 		return err
 	}
 
-	g.debug.Printf("create swagger.json at %+v", jsonFileName)
+	g.debug.Printf("create %s.json at %+v", name, jsonFileName)
 
 	return nil
 }
 
 func (g *Gen) writeYAMLSwagger(config *Config, swagger *spec.Swagger) error {
-	var filename = "swagger.yaml"
+	doc, name, err := openAPIDoc(config, swagger)
+	if err != nil {
+		return err
+	}
+
+	var filename = name + ".yaml"
 
 	if config.State != "" {
 		filename = config.State + "_" + filename
@@ -476,7 +875,7 @@ func (g *Gen) writeYAMLSwagger(config *Config, swagger *spec.Swagger) error {
 
 	yamlFileName := path.Join(config.OutputDir, filename)
 
-	b, err := g.json(swagger)
+	b, err := g.json(doc)
 	if err != nil {
 		return err
 	}
@@ -491,12 +890,22 @@ func (g *Gen) writeYAMLSwagger(config *Config, swagger *spec.Swagger) error {
 		return err
 	}
 
-	g.debug.Printf("create swagger.yaml at %+v", yamlFileName)
+	g.debug.Printf("create %s.yaml at %+v", name, yamlFileName)
 
 	return nil
 }
 
 func (g *Gen) writeFile(b []byte, file string) error {
+	return writeFileIfChanged(file, b)
+}
+
+// writeFileIfChanged writes a file unless it already has the content, so that the tools watching the output, like
+// hot reloaders, aren't triggered when the docs don't change.
+func writeFileIfChanged(file string, b []byte) error {
+	if current, err := os.ReadFile(file); err == nil && bytes.Equal(current, b) {
+		return nil
+	}
+
 	f, err := os.Create(file)
 	if err != nil {
 		return err
@@ -571,11 +980,25 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 			// Sanitize backticks
 			return strings.Replace(v, "`", "`+\"`\"+`", -1)
 		},
+		"printAsyncDoc": func(v string) string {
+			// Sanitize backticks
+			return strings.Replace(v, "`", "`+\"`\"+`", -1)
+		},
 	}).Parse(packageTemplate)
 	if err != nil {
 		return err
 	}
 
+	asyncAPIDoc, err := g.asyncAPIDocTemplate(config)
+	if err != nil {
+		return err
+	}
+
+	var asyncAPIInfo asyncSpec.Info
+	if g.parser != nil {
+		asyncAPIInfo = g.parser.GetAsyncAPI().Info
+	}
+
 	swaggerSpec := &spec.Swagger{
 		VendorExtensible: swagger.VendorExtensible,
 		SwaggerProps: spec.SwaggerProps{
@@ -635,6 +1058,10 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 		GeneratedTime      bool
 		LeftTemplateDelim  string
 		RightTemplateDelim string
+
+		AsyncAPIDoc          string
+		AsyncAPIInstanceName string
+		AsyncAPIInfo         asyncSpec.Info
 	}{
 		Timestamp:          time.Now(),
 		GeneratedTime:      config.GeneratedTime,
@@ -650,6 +1077,10 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 		InstanceName:       config.InstanceName,
 		LeftTemplateDelim:  config.LeftTemplateDelim,
 		RightTemplateDelim: config.RightTemplateDelim,
+
+		AsyncAPIDoc:          string(asyncAPIDoc),
+		AsyncAPIInstanceName: asyncAPIInstanceName(config),
+		AsyncAPIInfo:         asyncAPIInfo,
 	})
 	if err != nil {
 		return err
@@ -666,8 +1097,12 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 var packageTemplate = `// Package {{.PackageName}} Code generated by swaggo/swag{{ if .GeneratedTime }} at {{ .Timestamp }}{{ end }}. DO NOT EDIT
 package {{.PackageName}}
 
-import "github.com/swaggo/swag"
-
+{{ if .AsyncAPIDoc }}import (
+	"github.com/swaggo/swag"
+	"github.com/yalochat/swag/asyncswag"
+)
+{{ else }}import "github.com/swaggo/swag"
+{{ end }}
 const docTemplate{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }}{{ .State }} = ` + "`{{ printDoc .Doc}}`" + `
 
 // Swagger{{ .State }}Info{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }} holds exported Swagger Info so clients can modify it
@@ -683,8 +1118,27 @@ var Swagger{{ .State }}Info{{ if ne .InstanceName "swagger" }}{{ .InstanceName }
 	LeftDelim:        {{ printf "%q" .LeftTemplateDelim}},
 	RightDelim:       {{ printf "%q" .RightTemplateDelim}},
 }
+{{- if .AsyncAPIDoc }}
+
+const asyncAPITemplate{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }}{{ .State }} = ` + "`{{ printAsyncDoc .AsyncAPIDoc }}`" + `
+
+// AsyncAPI{{ .State }}Info{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }} holds exported AsyncAPI Info so clients can modify it
+var AsyncAPI{{ .State }}Info{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }} = &asyncswag.AsyncSpec{
+	Version:          {{ printf "%q" .AsyncAPIInfo.Version}},
+	Title:            {{ printf "%q" .AsyncAPIInfo.Title}},
+	Description:      {{ printf "%q" .AsyncAPIInfo.Description}},
+	Host:             "",
+	InfoInstanceName: {{ printf "%q" .AsyncAPIInstanceName }},
+	AsyncAPITemplate: asyncAPITemplate{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }}{{ .State }},
+	LeftDelim:        {{ printf "%q" .LeftTemplateDelim}},
+	RightDelim:       {{ printf "%q" .RightTemplateDelim}},
+}
+{{- end }}
 
 func init() {
 	swag.Register(Swagger{{ .State }}Info{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }}.InstanceName(), Swagger{{ .State }}Info{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }})
+{{- if .AsyncAPIDoc }}
+	swag.Register(AsyncAPI{{ .State }}Info{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }}.InstanceName(), AsyncAPI{{ .State }}Info{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }})
+{{- end }}
 }
 `
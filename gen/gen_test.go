@@ -17,6 +17,7 @@ import (
 	"github.com/go-openapi/spec"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	asyncSpec "github.com/swaggest/go-asyncapi/spec-2.4.0"
 	"github.com/yalochat/swag"
 )
 
@@ -48,6 +49,88 @@ func TestGen_Build(t *testing.T) {
 	}
 }
 
+func TestGen_BuildOpenAPIV31(t *testing.T) {
+	config := &Config{
+		SearchDir:      searchDir,
+		MainAPIFile:    "./main.go",
+		OutputDir:      "../testdata/simple/docs",
+		OutputTypes:    []string{"json", "yaml"},
+		OpenAPIVersion: "3.1.0",
+	}
+	require.NoError(t, New().Build(config))
+
+	jsonFile := filepath.Join(config.OutputDir, "openapi.json")
+	yamlFile := filepath.Join(config.OutputDir, "openapi.yaml")
+
+	defer func() {
+		_ = os.Remove(jsonFile)
+		_ = os.Remove(yamlFile)
+	}()
+
+	require.FileExists(t, yamlFile)
+	assert.NoFileExists(t, filepath.Join(config.OutputDir, "swagger.json"))
+
+	b, err := os.ReadFile(jsonFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "#/definitions/")
+
+	var doc struct {
+		OpenAPI    string                            `json:"openapi"`
+		Servers    []openAPIV3Server                 `json:"servers"`
+		Components map[string]json.RawMessage        `json:"components"`
+		Paths      map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(b, &doc))
+
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Equal(t, []openAPIV3Server{{URL: "//petstore.swagger.io/v2"}}, doc.Servers)
+	assert.Contains(t, doc.Components, "schemas")
+	assert.Contains(t, doc.Components, "securitySchemes")
+	assert.Contains(t, doc.Paths["/file/upload"]["post"], "requestBody")
+}
+
+func TestGen_BuildUnsupportedOpenAPIVersion(t *testing.T) {
+	config := &Config{
+		SearchDir:      searchDir,
+		MainAPIFile:    "./main.go",
+		OutputDir:      "../testdata/simple/docs",
+		OutputTypes:    []string{"json"},
+		OpenAPIVersion: "3.0.0",
+	}
+
+	assert.EqualError(t, New().Build(config), "not supported 3.0.0 OpenAPI version")
+}
+
+func TestGen_BuildOpenAPIV31GoOutputType(t *testing.T) {
+	config := &Config{
+		SearchDir:      searchDir,
+		MainAPIFile:    "./main.go",
+		OutputDir:      "../testdata/simple/docs",
+		OutputTypes:    []string{"go", "json"},
+		OpenAPIVersion: "3.1.0",
+	}
+
+	require.NoError(t, New().Build(config))
+
+	goFile := filepath.Join(config.OutputDir, "docs.go")
+	jsonFile := filepath.Join(config.OutputDir, "openapi.json")
+
+	defer func() {
+		_ = os.Remove(goFile)
+		_ = os.Remove(jsonFile)
+	}()
+
+	// docs.go keeps embedding the Swagger 2.0 document, its host, base path and schemes are overridden at runtime.
+	b, err := os.ReadFile(goFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"swagger": "2.0"`)
+
+	b, err = os.ReadFile(jsonFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"openapi": "3.1.0"`)
+	assert.NoFileExists(t, filepath.Join(config.OutputDir, "swagger.json"))
+}
+
 func TestGen_SpecificOutputTypes(t *testing.T) {
 	config := &Config{
 		SearchDir:          searchDir,
@@ -691,6 +774,331 @@ func TestGen_GeneratedAsyncDoc(t *testing.T) {
 	}
 }
 
+const asyncAPIDocRuntimeTest = `package docs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/swaggo/swag"
+)
+
+func TestAsyncAPIDoc(t *testing.T) {
+	AsyncAPIInfo.Host = "broker.staging:1883"
+	AsyncAPIInfo.Version = "2.0"
+
+	doc, err := swag.ReadDoc("asyncapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(doc, "mqtt://broker.staging:1883") || !strings.Contains(doc, "\"version\": \"2.0\"") {
+		t.Fatalf("overrides are not applied to %s", doc)
+	}
+}
+`
+
+func TestGen_GeneratedAsyncDocRegistered(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/simple_async",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/simple_async/docs",
+		OutputTypes: []string{"go"},
+	}
+
+	require.NoError(t, New().Build(config))
+
+	docFile := filepath.Join(config.OutputDir, "docs.go")
+	testFile := filepath.Join(config.OutputDir, "docs_test.go")
+
+	defer func() {
+		for _, file := range []string{docFile, testFile, filepath.Join(config.OutputDir, "asyncapi.yaml")} {
+			_ = os.Remove(file)
+		}
+	}()
+
+	docs, err := os.ReadFile(docFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(docs), `InfoInstanceName: "asyncapi",`)
+	assert.NotContains(t, string(docs), `"github.com/yalochat/swag"`)
+	assert.Contains(t, string(docs), `"title": "{{.Title}}",`)
+
+	require.NoError(t, os.WriteFile(testFile, []byte(asyncAPIDocRuntimeTest), 0644))
+
+	goCMD, err := exec.LookPath("go")
+	require.NoError(t, err)
+
+	cmd := exec.Command(goCMD, "test", "./docs")
+	cmd.Dir = "../testdata/simple_async"
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	assert.NoError(t, cmd.Run())
+}
+
+func TestGen_asyncAPIDocTemplate(t *testing.T) {
+	p := swag.New()
+	require.NoError(t, p.ParseAPI("../testdata/simple_async", "./main.go", 100))
+
+	g := New()
+	g.parser = p
+
+	config := &Config{LeftTemplateDelim: "{%", RightTemplateDelim: "%}", AsyncAPIVersions: []string{"3.0.0"}}
+
+	doc, err := g.asyncAPIDocTemplate(config)
+	require.NoError(t, err)
+
+	var asyncAPI map[string]interface{}
+	require.NoError(t, json.Unmarshal(doc, &asyncAPI))
+	assert.Equal(t, "3.0.0", asyncAPI["asyncapi"])
+	assert.Equal(t, map[string]interface{}{
+		"title":       "{%.Title%}",
+		"description": "{%escape .Description%}",
+		"version":     "{%.Version%}",
+	}, asyncAPI["info"])
+
+	g.parser = swag.New()
+
+	doc, err = g.asyncAPIDocTemplate(config)
+	require.NoError(t, err)
+	assert.Nil(t, doc)
+}
+
+func TestAsyncAPIInstanceName(t *testing.T) {
+	assert.Equal(t, "asyncapi", asyncAPIInstanceName(&Config{InstanceName: swag.Name}))
+	assert.Equal(t, "orders_asyncapi", asyncAPIInstanceName(&Config{InstanceName: "orders"}))
+	assert.Equal(t, "orders_admin_asyncapi", asyncAPIInstanceName(&Config{InstanceName: "orders", State: "admin"}))
+}
+
+func TestValidateAsyncAPIServers(t *testing.T) {
+	securitySchemes := (&asyncSpec.ComponentsSecuritySchemes{}).WithMapOfComponentsSecuritySchemesWDValuesItem("scram",
+		asyncSpec.ComponentsSecuritySchemesWD{SecurityScheme: &asyncSpec.SecurityScheme{}})
+
+	tests := []struct {
+		name        string
+		server      asyncSpec.Server
+		expectedErr string
+	}{
+		{
+			name: "valid server",
+			server: asyncSpec.Server{
+				URL:       "{env}.kafka.internal:9092",
+				Protocol:  "kafka",
+				Variables: map[string]asyncSpec.ServerVariable{"env": {Default: "prod"}},
+				Security:  []map[string][]string{{"scram": {}}},
+			},
+		},
+		{
+			name:        "missing URL",
+			server:      asyncSpec.Server{Protocol: "kafka"},
+			expectedErr: "URL for server 'kafka' not provided",
+		},
+		{
+			name:        "undeclared variable",
+			server:      asyncSpec.Server{URL: "{env}.kafka.internal:9092", Protocol: "kafka"},
+			expectedErr: "variable 'env' used by server 'kafka' is not declared",
+		},
+		{
+			name: "undeclared security scheme",
+			server: asyncSpec.Server{
+				URL:      "kafka.internal:9092",
+				Protocol: "kafka",
+				Security: []map[string][]string{{"oauth": {}}},
+			},
+			expectedErr: "security scheme 'oauth' used by server 'kafka' is not declared",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server
+			asyncAPI := &asyncSpec.AsyncAPI{
+				Servers:    map[string]asyncSpec.ServersAdditionalProperties{"kafka": {Server: &server}},
+				Components: &asyncSpec.Components{SecuritySchemes: securitySchemes},
+			}
+
+			err := validateAsyncAPIServers(asyncAPI)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestValidateAsyncAPIBindings(t *testing.T) {
+	message := asyncSpec.Message{}
+	message.OneOf1Ens().WithMessageEntity(asyncSpec.MessageEntity{
+		Bindings: &asyncSpec.MessageBindingsObject{Amqp: &asyncSpec.AmqpMessage{ContentEncoding: "gzip"}},
+	})
+
+	tests := []struct {
+		name        string
+		channel     asyncSpec.ChannelItem
+		expectedErr string
+	}{
+		{
+			name: "matching protocol",
+			channel: asyncSpec.ChannelItem{
+				Servers:   []string{"kafka"},
+				Subscribe: &asyncSpec.Operation{Bindings: &asyncSpec.OperationBindingsObject{Kafka: &asyncSpec.KafkaOperation{}}},
+			},
+		},
+		{
+			name: "channel binding of another protocol",
+			channel: asyncSpec.ChannelItem{
+				Servers:  []string{"kafka"},
+				Bindings: &asyncSpec.ChannelBindingsObject{Ws: &asyncSpec.WebsocketsChannel{Method: "GET"}},
+			},
+			expectedErr: "ws bindings can't be used with server 'kafka' using protocol 'kafka-secure'",
+		},
+		{
+			name: "message binding of another protocol",
+			channel: asyncSpec.ChannelItem{
+				Servers: []string{"kafka"},
+				Publish: (&asyncSpec.Operation{}).WithMessage(*(&asyncSpec.Message{}).WithReference(asyncSpec.Reference{Ref: "#/components/messages/MyMessage"})),
+			},
+			expectedErr: "amqp bindings can't be used with server 'kafka' using protocol 'kafka-secure'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asyncAPI := &asyncSpec.AsyncAPI{
+				Servers: map[string]asyncSpec.ServersAdditionalProperties{
+					"kafka": {Server: &asyncSpec.Server{URL: "kafka.internal:9092", Protocol: "kafka-secure"}},
+				},
+				Components: &asyncSpec.Components{Messages: map[string]asyncSpec.Message{"MyMessage": message}},
+			}
+
+			err := validateAsyncAPIChannel(asyncAPI, "orders", &tt.channel)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestValidateAsyncAPIChannelParameters(t *testing.T) {
+	tests := []struct {
+		name        string
+		channelName string
+		parameters  map[string]asyncSpec.Parameter
+		expectedErr string
+	}{
+		{
+			name:        "declared parameters",
+			channelName: "orders/{tenantId}/{orderId}",
+			parameters:  map[string]asyncSpec.Parameter{"tenantId": {}, "orderId": {}},
+		},
+		{
+			name:        "undeclared parameter",
+			channelName: "orders/{tenantId}/{orderId}",
+			parameters:  map[string]asyncSpec.Parameter{"tenantId": {}},
+			expectedErr: "parameter 'orderId' is not declared, add a @channel.param line for it",
+		},
+		{
+			name:        "unused parameter",
+			channelName: "orders/{tenantId}",
+			parameters:  map[string]asyncSpec.Parameter{"tenantId": {}, "orderId": {}},
+			expectedErr: "parameter 'orderId' is declared but not used in the channel name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAsyncAPIChannelParameters(tt.channelName, &asyncSpec.ChannelItem{Parameters: tt.parameters})
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestProcessAsyncAPIDiscriminators(t *testing.T) {
+	orderSchema := func(typeProperty map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type":    typeProperty,
+				"orderId": map[string]interface{}{"type": "string"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		schemas       map[string]map[string]interface{}
+		discriminator interface{}
+	}{
+		{
+			name: "single enum values",
+			schemas: map[string]map[string]interface{}{
+				"model.OrderCreated":   orderSchema(map[string]interface{}{"type": "string", "enum": []interface{}{"created"}}),
+				"model.OrderCancelled": orderSchema(map[string]interface{}{"type": "string", "const": "cancelled"}),
+			},
+			discriminator: "type",
+		},
+		{
+			name: "same value",
+			schemas: map[string]map[string]interface{}{
+				"model.OrderCreated":   orderSchema(map[string]interface{}{"type": "string", "enum": []interface{}{"order"}}),
+				"model.OrderCancelled": orderSchema(map[string]interface{}{"type": "string", "enum": []interface{}{"order"}}),
+			},
+		},
+		{
+			name: "values are not constant",
+			schemas: map[string]map[string]interface{}{
+				"model.OrderCreated":   orderSchema(map[string]interface{}{"type": "string"}),
+				"model.OrderCancelled": orderSchema(map[string]interface{}{"type": "string", "enum": []interface{}{"created", "cancelled"}}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := func(name string) asyncSpec.Message {
+				return *(&asyncSpec.Message{}).WithOneOf1(asyncSpec.MessageOneOf1{MessageEntity: &asyncSpec.MessageEntity{
+					Payload: map[string]interface{}{"$ref": "#/components/schemas/model." + name},
+				}})
+			}
+
+			operation := asyncSpec.Message{}
+			operation.OneOf1Ens().WithOneOf0(asyncSpec.MessageOneOf1OneOf0{OneOf: []asyncSpec.Message{
+				*(&asyncSpec.Message{}).WithReference(asyncSpec.Reference{Ref: "#/components/messages/OrderCreated"}),
+				*(&asyncSpec.Message{}).WithReference(asyncSpec.Reference{Ref: "#/components/messages/OrderCancelled"}),
+			}})
+
+			asyncAPI := &asyncSpec.AsyncAPI{
+				Channels: map[string]asyncSpec.ChannelItem{
+					"orders": {Subscribe: (&asyncSpec.Operation{}).WithMessage(operation)},
+				},
+				Components: &asyncSpec.Components{
+					Schemas: tt.schemas,
+					Messages: map[string]asyncSpec.Message{
+						"OrderCreated":   message("OrderCreated"),
+						"OrderCancelled": message("OrderCancelled"),
+					},
+				},
+			}
+
+			processAsyncAPIDiscriminators(asyncAPI)
+
+			for schemaName, schema := range tt.schemas {
+				assert.Equal(t, tt.discriminator, schema["discriminator"], schemaName)
+			}
+		})
+	}
+}
+
 func TestGen_cgoImports(t *testing.T) {
 	config := &Config{
 		SearchDir:          "../testdata/simple_cgo",
@@ -1010,3 +1418,41 @@ func TestGen_StateUser(t *testing.T) {
 
 	assert.JSONEq(t, string(expectedJSON), string(jsonOutput))
 }
+
+func TestGen_Polymorphism(t *testing.T) {
+	p := swag.New()
+	require.NoError(t, p.ParseAPI("../testdata/polymorphism", "./main.go", 100))
+
+	asyncAPI := p.GetAsyncAPI()
+	swagger := p.GetSwagger()
+	require.NoError(t, processAsyncAPIDefinitions(p, asyncAPI, swagger))
+
+	// the payload of the message is only used by the AsyncAPI spec, the payments by both
+	assert.NotContains(t, swagger.Definitions, "api.PaymentEvent")
+
+	for _, name := range []string{"payments.Payment", "payments.CardPayment", "payments.Voucher", "bank.Transfer"} {
+		require.Contains(t, swagger.Definitions, name)
+		require.Contains(t, asyncAPI.Components.Schemas, name)
+	}
+
+	assert.Equal(t, "kind", asyncAPI.Components.Schemas["payments.Payment"]["discriminator"])
+	assert.Equal(t, "card", asyncAPI.Components.Schemas["payments.CardPayment"]["x-discriminator-value"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"$ref": "#/components/schemas/payments.Payment"},
+		map[string]interface{}{"type": "object", "properties": map[string]interface{}{"iban": map[string]interface{}{"type": "string"}}},
+	}, asyncAPI.Components.Schemas["bank.Transfer"]["allOf"])
+
+	doc, err := newOpenAPIV3(swagger)
+	require.NoError(t, err)
+
+	payment := doc.Components.Schemas["payments.Payment"]
+	assert.Equal(t, map[string]interface{}{
+		"propertyName": "kind",
+		"mapping": map[string]interface{}{
+			"card":    "#/components/schemas/payments.CardPayment",
+			"bank":    "#/components/schemas/bank.Transfer",
+			"Voucher": "#/components/schemas/payments.Voucher",
+		},
+	}, payment["discriminator"])
+	assert.Len(t, payment["oneOf"], 3)
+}
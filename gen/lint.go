@@ -0,0 +1,219 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yalochat/swag"
+)
+
+// Output formats of the diagnostics.
+const (
+	LintFormatText  = "text"
+	LintFormatJSON  = "json"
+	LintFormatSARIF = "sarif"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// Lint parses the annotations like Build, without writing any file, and returns the diagnostics found in them.
+func (g *Gen) Lint(config *Config) ([]swag.Diagnostic, error) {
+	if config.Debugger != nil {
+		g.debug = config.Debugger
+	}
+
+	searchDirs := strings.Split(config.SearchDir, ",")
+	for _, searchDir := range searchDirs {
+		if _, err := os.Stat(searchDir); os.IsNotExist(err) {
+			return nil, fmt.Errorf("dir: %s does not exist", searchDir)
+		}
+	}
+
+	overrides, err := g.readOverrides(config)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newParser(config, overrides, swag.SetLint(true))
+
+	if err := p.ParseAPIMultiSearchDir(searchDirs, config.MainAPIFile, config.ParseDepth); err != nil {
+		return nil, err
+	}
+
+	return p.Diagnostics(), nil
+}
+
+// HasLintErrors reports whether one of the diagnostics has the error severity.
+func HasLintErrors(diagnostics []swag.Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == swag.SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteDiagnostics writes the diagnostics in the text, json or sarif format.
+func WriteDiagnostics(w io.Writer, diagnostics []swag.Diagnostic, format string) error {
+	switch strings.ToLower(format) {
+	case "", LintFormatText:
+		for _, diagnostic := range diagnostics {
+			if _, err := fmt.Fprintln(w, diagnostic); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case LintFormatJSON:
+		return writeJSONDiagnostics(w, diagnostics)
+	case LintFormatSARIF:
+		return writeSARIFDiagnostics(w, diagnostics)
+	default:
+		return fmt.Errorf("not supported %s lint format", format)
+	}
+}
+
+type jsonDiagnostic struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+}
+
+func writeJSONDiagnostics(w io.Writer, diagnostics []swag.Diagnostic) error {
+	items := make([]jsonDiagnostic, 0, len(diagnostics))
+
+	for _, diagnostic := range diagnostics {
+		items = append(items, jsonDiagnostic{
+			Rule:     diagnostic.Rule,
+			Severity: diagnostic.Severity,
+			File:     diagnostic.Position.Filename,
+			Line:     diagnostic.Position.Line,
+			Column:   diagnostic.Position.Column,
+			Message:  diagnostic.Message,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+
+	return encoder.Encode(items)
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func writeSARIFDiagnostics(w io.Writer, diagnostics []swag.Diagnostic) error {
+	rules := make([]sarifRule, 0, len(swag.LintRules))
+	for _, rule := range swag.LintRules {
+		rules = append(rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Severity},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(diagnostics))
+
+	for _, diagnostic := range diagnostics {
+		location := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(diagnostic.Position.Filename)},
+		}
+
+		if diagnostic.Position.Line > 0 {
+			location.Region = &sarifRegion{
+				StartLine:   diagnostic.Position.Line,
+				StartColumn: diagnostic.Position.Column,
+			}
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    diagnostic.Rule,
+			Level:     diagnostic.Severity,
+			Message:   sarifMessage{Text: diagnostic.Message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "swag",
+				Version:        swag.Version,
+				InformationURI: "https://github.com/yalochat/swag",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	})
+}
@@ -0,0 +1,146 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yalochat/swag"
+)
+
+var lintTestDiagnostics = []swag.Diagnostic{
+	{
+		Rule:     swag.RulePathParamNotInRoute,
+		Severity: swag.SeverityError,
+		Position: token.Position{Filename: "api/api.go", Line: 16, Column: 4},
+		Message:  "path parameter 'petId' isn't a placeholder of GET /pets/{id}",
+	},
+	{
+		Rule:     swag.RuleMissingResponse,
+		Severity: swag.SeverityWarning,
+		Position: token.Position{Filename: "api/api.go", Line: 27, Column: 4},
+		Message:  "operation doesn't declare any response",
+	},
+}
+
+func TestGen_Lint(t *testing.T) {
+	config := &Config{
+		SearchDir:   "../testdata/lint",
+		MainAPIFile: "./main.go",
+		OutputDir:   "../testdata/lint/docs",
+	}
+
+	diagnostics, err := New().Lint(config)
+	require.NoError(t, err)
+
+	var rules []string
+	for _, diagnostic := range diagnostics {
+		rules = append(rules, diagnostic.Rule)
+	}
+
+	assert.Equal(t, []string{
+		swag.RulePathParamNotInRoute,
+		swag.RuleDuplicateOperationID,
+		swag.RuleUnknownAttribute,
+		swag.RuleMissingResponse,
+		swag.RuleUndeclaredChannelServer,
+		swag.RuleInvalidAnnotation,
+		swag.RuleUnknownAttribute,
+		swag.RulePathPlaceholderWithoutParam,
+		swag.RulePathPlaceholderWithoutParam,
+		swag.RuleDuplicateRoute,
+	}, rules)
+	assert.True(t, HasLintErrors(diagnostics))
+
+	_, err = os.Stat(config.OutputDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGen_LintInvalidSearchDir(t *testing.T) {
+	_, err := New().Lint(&Config{SearchDir: "../testdata/not_exists", MainAPIFile: "./main.go"})
+	assert.EqualError(t, err, "dir: ../testdata/not_exists does not exist")
+}
+
+func TestHasLintErrors(t *testing.T) {
+	assert.False(t, HasLintErrors(nil))
+	assert.False(t, HasLintErrors(lintTestDiagnostics[1:]))
+	assert.True(t, HasLintErrors(lintTestDiagnostics))
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			name:   "text",
+			format: LintFormatText,
+			expected: "api/api.go:16:4: error: path parameter 'petId' isn't a placeholder of GET /pets/{id} (path-param-not-in-route)\n" +
+				"api/api.go:27:4: warning: operation doesn't declare any response (missing-response)\n",
+		},
+		{
+			name:   "json",
+			format: LintFormatJSON,
+			expected: `[
+    {
+        "rule": "path-param-not-in-route",
+        "severity": "error",
+        "file": "api/api.go",
+        "line": 16,
+        "column": 4,
+        "message": "path parameter 'petId' isn't a placeholder of GET /pets/{id}"
+    },
+    {
+        "rule": "missing-response",
+        "severity": "warning",
+        "file": "api/api.go",
+        "line": 27,
+        "column": 4,
+        "message": "operation doesn't declare any response"
+    }
+]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteDiagnostics(&buf, lintTestDiagnostics, tt.format))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteDiagnostics_SARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiagnostics(&buf, lintTestDiagnostics[:1], LintFormatSARIF))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	assert.Equal(t, "swag", log.Runs[0].Tool.Driver.Name)
+	assert.Equal(t, swag.Version, log.Runs[0].Tool.Driver.Version)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, len(swag.LintRules))
+	assert.Equal(t, []sarifResult{{
+		RuleID:  swag.RulePathParamNotInRoute,
+		Level:   "error",
+		Message: sarifMessage{Text: "path parameter 'petId' isn't a placeholder of GET /pets/{id}"},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: "api/api.go"},
+			Region:           &sarifRegion{StartLine: 16, StartColumn: 4},
+		}}},
+	}}, log.Runs[0].Results)
+}
+
+func TestWriteDiagnostics_UnsupportedFormat(t *testing.T) {
+	assert.EqualError(t, WriteDiagnostics(&bytes.Buffer{}, nil, "xml"), "not supported xml lint format")
+}
@@ -0,0 +1,864 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/yalochat/swag/internal/jsonschema"
+)
+
+const (
+	openAPIVersion2  = "2.0"
+	openAPIVersion31 = "3.1.0"
+
+	openAPIDefaultMediaType = "application/json"
+	openAPIFileMediaType    = "application/octet-stream"
+	openAPIMultipartForm    = "multipart/form-data"
+	openAPIURLEncodedForm   = "application/x-www-form-urlencoded"
+
+	openAPIV3SchemaRefPrefix = "#/components/schemas/"
+
+	// discriminatorValueExtension holds the discriminator value of a Swagger 2.0 polymorphic subtype.
+	discriminatorValueExtension = "x-discriminator-value"
+)
+
+// openAPIV3RefPrefixes maps the Swagger 2.0 reference prefixes to the OpenAPI 3.1 components.
+var openAPIV3RefPrefixes = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/parameters/":  "#/components/parameters/",
+	"#/responses/":   "#/components/responses/",
+}
+
+// openAPIV3 is the root document object of an OpenAPI 3.1 specification.
+type openAPIV3 struct {
+	OpenAPI      string                       `json:"openapi"`
+	Info         *spec.Info                   `json:"info"`
+	ExternalDocs *spec.ExternalDocumentation  `json:"externalDocs,omitempty"`
+	Servers      []openAPIV3Server            `json:"servers,omitempty"`
+	Security     []map[string][]string        `json:"security,omitempty"`
+	Tags         []spec.Tag                   `json:"tags,omitempty"`
+	Paths        map[string]openAPIV3PathItem `json:"paths,omitempty"`
+	Components   *openAPIV3Components         `json:"components,omitempty"`
+	Extensions   spec.Extensions              `json:"-"`
+}
+
+type openAPIV3Server struct {
+	URL string `json:"url"`
+}
+
+type openAPIV3PathItem struct {
+	Ref        string                   `json:"$ref,omitempty"`
+	Get        *openAPIV3Operation      `json:"get,omitempty"`
+	Put        *openAPIV3Operation      `json:"put,omitempty"`
+	Post       *openAPIV3Operation      `json:"post,omitempty"`
+	Delete     *openAPIV3Operation      `json:"delete,omitempty"`
+	Options    *openAPIV3Operation      `json:"options,omitempty"`
+	Head       *openAPIV3Operation      `json:"head,omitempty"`
+	Patch      *openAPIV3Operation      `json:"patch,omitempty"`
+	Parameters []map[string]interface{} `json:"parameters,omitempty"`
+	Extensions spec.Extensions          `json:"-"`
+}
+
+type openAPIV3Operation struct {
+	Tags         []string                     `json:"tags,omitempty"`
+	Summary      string                       `json:"summary,omitempty"`
+	Description  string                       `json:"description,omitempty"`
+	ExternalDocs *spec.ExternalDocumentation  `json:"externalDocs,omitempty"`
+	OperationID  string                       `json:"operationId,omitempty"`
+	Parameters   []map[string]interface{}     `json:"parameters,omitempty"`
+	RequestBody  *openAPIV3RequestBody        `json:"requestBody,omitempty"`
+	Responses    map[string]openAPIV3Response `json:"responses,omitempty"`
+	Deprecated   bool                         `json:"deprecated,omitempty"`
+	Security     []map[string][]string        `json:"security,omitempty"`
+	Extensions   spec.Extensions              `json:"-"`
+}
+
+type openAPIV3RequestBody struct {
+	Ref         string                        `json:"$ref,omitempty"`
+	Description string                        `json:"description,omitempty"`
+	Content     map[string]openAPIV3MediaType `json:"content,omitempty"`
+	Required    bool                          `json:"required,omitempty"`
+}
+
+type openAPIV3Response struct {
+	Ref         string                            `json:"$ref,omitempty"`
+	Description string                            `json:"description,omitempty"`
+	Headers     map[string]map[string]interface{} `json:"headers,omitempty"`
+	Content     map[string]openAPIV3MediaType     `json:"content,omitempty"`
+	Extensions  spec.Extensions                   `json:"-"`
+}
+
+type openAPIV3MediaType struct {
+	Schema  map[string]interface{} `json:"schema,omitempty"`
+	Example interface{}            `json:"example,omitempty"`
+}
+
+type openAPIV3Components struct {
+	Schemas         map[string]map[string]interface{}  `json:"schemas,omitempty"`
+	Responses       map[string]openAPIV3Response       `json:"responses,omitempty"`
+	Parameters      map[string]map[string]interface{}  `json:"parameters,omitempty"`
+	RequestBodies   map[string]openAPIV3RequestBody    `json:"requestBodies,omitempty"`
+	SecuritySchemes map[string]openAPIV3SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type openAPIV3SecurityScheme struct {
+	Type        string               `json:"type"`
+	Description string               `json:"description,omitempty"`
+	Name        string               `json:"name,omitempty"`
+	In          string               `json:"in,omitempty"`
+	Scheme      string               `json:"scheme,omitempty"`
+	Flows       *openAPIV3OAuthFlows `json:"flows,omitempty"`
+	Extensions  spec.Extensions      `json:"-"`
+}
+
+type openAPIV3OAuthFlows struct {
+	Implicit          *openAPIV3OAuthFlow `json:"implicit,omitempty"`
+	Password          *openAPIV3OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *openAPIV3OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *openAPIV3OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+type openAPIV3OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// MarshalJSON marshals the document followed by its vendor extensions.
+func (doc openAPIV3) MarshalJSON() ([]byte, error) {
+	type plain openAPIV3
+	return marshalOpenAPIV3Object(plain(doc), doc.Extensions)
+}
+
+// MarshalJSON marshals the path item followed by its vendor extensions.
+func (pathItem openAPIV3PathItem) MarshalJSON() ([]byte, error) {
+	type plain openAPIV3PathItem
+	return marshalOpenAPIV3Object(plain(pathItem), pathItem.Extensions)
+}
+
+// MarshalJSON marshals the operation followed by its vendor extensions.
+func (operation openAPIV3Operation) MarshalJSON() ([]byte, error) {
+	type plain openAPIV3Operation
+	return marshalOpenAPIV3Object(plain(operation), operation.Extensions)
+}
+
+// MarshalJSON marshals the response followed by its vendor extensions.
+func (response openAPIV3Response) MarshalJSON() ([]byte, error) {
+	type plain openAPIV3Response
+	return marshalOpenAPIV3Object(plain(response), response.Extensions)
+}
+
+// MarshalJSON marshals the security scheme followed by its vendor extensions.
+func (scheme openAPIV3SecurityScheme) MarshalJSON() ([]byte, error) {
+	type plain openAPIV3SecurityScheme
+	return marshalOpenAPIV3Object(plain(scheme), scheme.Extensions)
+}
+
+func marshalOpenAPIV3Object(object interface{}, extensions spec.Extensions) ([]byte, error) {
+	objectJSON, err := json.Marshal(object)
+	if err != nil || len(extensions) == 0 {
+		return objectJSON, err
+	}
+
+	extensionsJSON, err := json.Marshal(extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(objectJSON) == "{}" {
+		return extensionsJSON, nil
+	}
+
+	return append(append(objectJSON[:len(objectJSON)-1], ','), extensionsJSON[1:]...), nil
+}
+
+// newOpenAPIV3 converts the Swagger 2.0 document built by the parser into an OpenAPI 3.1 document. Body and form
+// parameters become request bodies, schemas are described once per consumed or produced media type and the
+// Swagger 2.0 extensions which have a native JSON Schema equivalent, like x-nullable, are translated.
+func newOpenAPIV3(swagger *spec.Swagger) (*openAPIV3, error) {
+	doc := &openAPIV3{
+		OpenAPI:      openAPIVersion31,
+		Info:         swagger.Info,
+		ExternalDocs: swagger.ExternalDocs,
+		Servers:      newOpenAPIV3Servers(swagger.Host, swagger.BasePath, swagger.Schemes),
+		Security:     swagger.Security,
+		Tags:         swagger.Tags,
+		Extensions:   swagger.Extensions,
+	}
+
+	if doc.Info == nil {
+		doc.Info = &spec.Info{}
+	}
+
+	components, err := newOpenAPIV3Components(swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(components.Schemas) > 0 || len(components.Responses) > 0 || len(components.Parameters) > 0 ||
+		len(components.RequestBodies) > 0 || len(components.SecuritySchemes) > 0 {
+		doc.Components = components
+	}
+
+	if swagger.Paths == nil {
+		return doc, nil
+	}
+
+	doc.Paths = make(map[string]openAPIV3PathItem, len(swagger.Paths.Paths))
+
+	for path, pathItem := range swagger.Paths.Paths {
+		pathItemV3, err := newOpenAPIV3PathItem(swagger, pathItem)
+		if err != nil {
+			return nil, fmt.Errorf("path '%s' is invalid: %w", path, err)
+		}
+
+		doc.Paths[path] = pathItemV3
+	}
+
+	return doc, nil
+}
+
+// newOpenAPIV3Servers describes the host, base path and schemes as servers, one per scheme.
+func newOpenAPIV3Servers(host, basePath string, schemes []string) []openAPIV3Server {
+	basePath = strings.TrimSuffix(basePath, "/")
+
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+
+		return []openAPIV3Server{{URL: basePath}}
+	}
+
+	if len(schemes) == 0 {
+		return []openAPIV3Server{{URL: "//" + host + basePath}}
+	}
+
+	servers := make([]openAPIV3Server, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, openAPIV3Server{URL: scheme + "://" + host + basePath})
+	}
+
+	return servers
+}
+
+func newOpenAPIV3Components(swagger *spec.Swagger) (*openAPIV3Components, error) {
+	components := &openAPIV3Components{
+		Schemas:         make(map[string]map[string]interface{}, len(swagger.Definitions)),
+		Responses:       make(map[string]openAPIV3Response, len(swagger.Responses)),
+		Parameters:      make(map[string]map[string]interface{}),
+		RequestBodies:   make(map[string]openAPIV3RequestBody),
+		SecuritySchemes: make(map[string]openAPIV3SecurityScheme, len(swagger.SecurityDefinitions)),
+	}
+
+	for name, definition := range swagger.Definitions {
+		schema, err := newOpenAPIV3Schema(definition)
+		if err != nil {
+			return nil, fmt.Errorf("definition '%s' is invalid: %w", name, err)
+		}
+
+		components.Schemas[name] = schema
+	}
+
+	convertOpenAPIV3Polymorphisms(components.Schemas)
+
+	for name, param := range swagger.Parameters {
+		switch param.In {
+		case "body":
+			requestBody, err := newOpenAPIV3RequestBody(param, openAPIV3MediaTypes(swagger.Consumes))
+			if err != nil {
+				return nil, fmt.Errorf("parameter '%s' is invalid: %w", name, err)
+			}
+
+			components.RequestBodies[name] = *requestBody
+		case "formData":
+			// Form parameters are merged into the request body of every operation using them.
+		default:
+			parameter, err := newOpenAPIV3Parameter(param)
+			if err != nil {
+				return nil, fmt.Errorf("parameter '%s' is invalid: %w", name, err)
+			}
+
+			components.Parameters[name] = parameter
+		}
+	}
+
+	for name, response := range swagger.Responses {
+		responseV3, err := newOpenAPIV3Response(response, openAPIV3MediaTypes(swagger.Produces))
+		if err != nil {
+			return nil, fmt.Errorf("response '%s' is invalid: %w", name, err)
+		}
+
+		components.Responses[name] = responseV3
+	}
+
+	for name, scheme := range swagger.SecurityDefinitions {
+		components.SecuritySchemes[name] = newOpenAPIV3SecurityScheme(scheme)
+	}
+
+	return components, nil
+}
+
+func newOpenAPIV3SecurityScheme(scheme *spec.SecurityScheme) openAPIV3SecurityScheme {
+	schemeV3 := openAPIV3SecurityScheme{
+		Type:        scheme.Type,
+		Description: scheme.Description,
+		Extensions:  scheme.Extensions,
+	}
+
+	switch scheme.Type {
+	case "basic":
+		schemeV3.Type = "http"
+		schemeV3.Scheme = "basic"
+	case "apiKey":
+		schemeV3.Name = scheme.Name
+		schemeV3.In = scheme.In
+	case "oauth2":
+		scopes := scheme.Scopes
+		if scopes == nil {
+			scopes = make(map[string]string)
+		}
+
+		schemeV3.Flows = &openAPIV3OAuthFlows{}
+
+		switch scheme.Flow {
+		case "implicit":
+			schemeV3.Flows.Implicit = &openAPIV3OAuthFlow{AuthorizationURL: scheme.AuthorizationURL, Scopes: scopes}
+		case "password":
+			schemeV3.Flows.Password = &openAPIV3OAuthFlow{TokenURL: scheme.TokenURL, Scopes: scopes}
+		case "application":
+			schemeV3.Flows.ClientCredentials = &openAPIV3OAuthFlow{TokenURL: scheme.TokenURL, Scopes: scopes}
+		case "accessCode":
+			schemeV3.Flows.AuthorizationCode = &openAPIV3OAuthFlow{
+				AuthorizationURL: scheme.AuthorizationURL,
+				TokenURL:         scheme.TokenURL,
+				Scopes:           scopes,
+			}
+		}
+	}
+
+	return schemeV3
+}
+
+func newOpenAPIV3PathItem(swagger *spec.Swagger, pathItem spec.PathItem) (openAPIV3PathItem, error) {
+	pathItemV3 := openAPIV3PathItem{
+		Ref:        openAPIV3Ref(pathItem.Ref.String()),
+		Extensions: pathItem.Extensions,
+	}
+
+	// Body and form parameters of the path item describe the request body of each of its operations.
+	var bodyParameters []spec.Parameter
+
+	for _, param := range pathItem.Parameters {
+		if param.In == "body" || param.In == "formData" {
+			bodyParameters = append(bodyParameters, param)
+			continue
+		}
+
+		parameter, err := newOpenAPIV3Parameter(param)
+		if err != nil {
+			return pathItemV3, err
+		}
+
+		pathItemV3.Parameters = append(pathItemV3.Parameters, parameter)
+	}
+
+	for _, method := range []struct {
+		operation   *spec.Operation
+		operationV3 **openAPIV3Operation
+	}{
+		{pathItem.Get, &pathItemV3.Get},
+		{pathItem.Put, &pathItemV3.Put},
+		{pathItem.Post, &pathItemV3.Post},
+		{pathItem.Delete, &pathItemV3.Delete},
+		{pathItem.Options, &pathItemV3.Options},
+		{pathItem.Head, &pathItemV3.Head},
+		{pathItem.Patch, &pathItemV3.Patch},
+	} {
+		if method.operation == nil {
+			continue
+		}
+
+		operationV3, err := newOpenAPIV3Operation(swagger, method.operation, bodyParameters)
+		if err != nil {
+			return pathItemV3, err
+		}
+
+		*method.operationV3 = operationV3
+	}
+
+	return pathItemV3, nil
+}
+
+func newOpenAPIV3Operation(swagger *spec.Swagger, operation *spec.Operation, bodyParameters []spec.Parameter) (*openAPIV3Operation, error) {
+	operationV3 := &openAPIV3Operation{
+		Tags:         operation.Tags,
+		Summary:      operation.Summary,
+		Description:  operation.Description,
+		ExternalDocs: operation.ExternalDocs,
+		OperationID:  operation.ID,
+		Deprecated:   operation.Deprecated,
+		Security:     operation.Security,
+		Extensions:   operation.Extensions,
+	}
+
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = swagger.Produces
+	}
+
+	var formParameters []spec.Parameter
+
+	for _, param := range append(append([]spec.Parameter{}, bodyParameters...), operation.Parameters...) {
+		if ref := param.Ref.String(); ref != "" {
+			name := strings.TrimPrefix(ref, "#/parameters/")
+
+			globalParam, ok := swagger.Parameters[name]
+			if !ok {
+				return nil, fmt.Errorf("parameter '%s' is not defined", ref)
+			}
+
+			switch globalParam.In {
+			case "body":
+				operationV3.RequestBody = &openAPIV3RequestBody{Ref: "#/components/requestBodies/" + name}
+				continue
+			case "formData":
+				param = globalParam
+			default:
+				operationV3.Parameters = append(operationV3.Parameters, map[string]interface{}{"$ref": openAPIV3Ref(ref)})
+				continue
+			}
+		}
+
+		switch param.In {
+		case "body":
+			requestBody, err := newOpenAPIV3RequestBody(param, openAPIV3MediaTypes(consumes))
+			if err != nil {
+				return nil, fmt.Errorf("parameter '%s' is invalid: %w", param.Name, err)
+			}
+
+			operationV3.RequestBody = requestBody
+		case "formData":
+			formParameters = append(formParameters, param)
+		default:
+			parameter, err := newOpenAPIV3Parameter(param)
+			if err != nil {
+				return nil, fmt.Errorf("parameter '%s' is invalid: %w", param.Name, err)
+			}
+
+			operationV3.Parameters = append(operationV3.Parameters, parameter)
+		}
+	}
+
+	if len(formParameters) > 0 {
+		requestBody, err := newOpenAPIV3FormRequestBody(formParameters, consumes)
+		if err != nil {
+			return nil, err
+		}
+
+		operationV3.RequestBody = requestBody
+	}
+
+	if operation.Responses == nil {
+		return operationV3, nil
+	}
+
+	operationV3.Responses = make(map[string]openAPIV3Response, len(operation.Responses.StatusCodeResponses)+1)
+
+	if operation.Responses.Default != nil {
+		response, err := newOpenAPIV3Response(*operation.Responses.Default, openAPIV3MediaTypes(produces))
+		if err != nil {
+			return nil, fmt.Errorf("default response is invalid: %w", err)
+		}
+
+		operationV3.Responses["default"] = response
+	}
+
+	for code, response := range operation.Responses.StatusCodeResponses {
+		responseV3, err := newOpenAPIV3Response(response, openAPIV3MediaTypes(produces))
+		if err != nil {
+			return nil, fmt.Errorf("response %d is invalid: %w", code, err)
+		}
+
+		operationV3.Responses[fmt.Sprint(code)] = responseV3
+	}
+
+	return operationV3, nil
+}
+
+// openAPIV3MediaTypes returns the consumed or produced media types, JSON when none was declared.
+func openAPIV3MediaTypes(mediaTypes []string) []string {
+	if len(mediaTypes) == 0 {
+		return []string{openAPIDefaultMediaType}
+	}
+
+	return mediaTypes
+}
+
+func newOpenAPIV3RequestBody(param spec.Parameter, mediaTypes []string) (*openAPIV3RequestBody, error) {
+	requestBody := &openAPIV3RequestBody{
+		Description: param.Description,
+		Required:    param.Required,
+		Content:     make(map[string]openAPIV3MediaType, len(mediaTypes)),
+	}
+
+	var schema map[string]interface{}
+
+	if param.Schema != nil {
+		var err error
+		if schema, err = newOpenAPIV3Schema(param.Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, mediaType := range mediaTypes {
+		requestBody.Content[mediaType] = openAPIV3MediaType{Schema: schema}
+	}
+
+	return requestBody, nil
+}
+
+// newOpenAPIV3FormRequestBody describes the form parameters of an operation as the properties of an object.
+// Forms are sent as multipart/form-data when the operation doesn't consume a form media type and uploads a file.
+func newOpenAPIV3FormRequestBody(params []spec.Parameter, consumes []string) (*openAPIV3RequestBody, error) {
+	schema := map[string]interface{}{"type": "object"}
+	properties := make(map[string]interface{}, len(params))
+
+	var (
+		required []interface{}
+		hasFile  bool
+	)
+
+	for _, param := range params {
+		fields, property, err := splitOpenAPIV3Parameter(param)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s' is invalid: %w", param.Name, err)
+		}
+
+		if description, ok := fields["description"]; ok {
+			property["description"] = description
+		}
+
+		if param.Required {
+			required = append(required, param.Name)
+		}
+
+		hasFile = hasFile || param.Type == "file"
+		properties[param.Name] = property
+	}
+
+	schema["properties"] = properties
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		if mediaType == openAPIMultipartForm || mediaType == openAPIURLEncodedForm {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{openAPIURLEncodedForm}
+		if hasFile {
+			mediaTypes = []string{openAPIMultipartForm}
+		}
+	}
+
+	requestBody := &openAPIV3RequestBody{
+		Required: len(required) > 0,
+		Content:  make(map[string]openAPIV3MediaType, len(mediaTypes)),
+	}
+
+	for _, mediaType := range mediaTypes {
+		requestBody.Content[mediaType] = openAPIV3MediaType{Schema: schema}
+	}
+
+	return requestBody, nil
+}
+
+func newOpenAPIV3Parameter(param spec.Parameter) (map[string]interface{}, error) {
+	if ref := param.Ref.String(); ref != "" {
+		return map[string]interface{}{"$ref": openAPIV3Ref(ref)}, nil
+	}
+
+	parameter, schema, err := splitOpenAPIV3Parameter(param)
+	if err != nil {
+		return nil, err
+	}
+
+	parameter["schema"] = schema
+
+	if param.Type != "array" {
+		return parameter, nil
+	}
+
+	// Swagger 2.0 collection formats are serialization styles, csv is the default of Swagger 2.0 and multi the
+	// default of query parameters in OpenAPI 3.
+	switch collectionFormat := param.CollectionFormat; {
+	case param.In != "query":
+	case collectionFormat == "multi":
+		parameter["style"], parameter["explode"] = "form", true
+	case collectionFormat == "ssv":
+		parameter["style"], parameter["explode"] = "spaceDelimited", false
+	case collectionFormat == "pipes":
+		parameter["style"], parameter["explode"] = "pipeDelimited", false
+	case collectionFormat == "" || collectionFormat == "csv":
+		parameter["style"], parameter["explode"] = "form", false
+	}
+
+	return parameter, nil
+}
+
+// splitOpenAPIV3Parameter splits a Swagger 2.0 parameter into the fields of an OpenAPI 3.1 parameter and the
+// schema of its value.
+func splitOpenAPIV3Parameter(param spec.Parameter) (map[string]interface{}, map[string]interface{}, error) {
+	schema, err := toJSONObject(param)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := make(map[string]interface{})
+
+	for key, value := range schema {
+		switch {
+		case key == "name", key == "in", key == "description", key == "required", key == "allowEmptyValue",
+			strings.HasPrefix(key, "x-") && key != "x-nullable":
+			fields[key] = value
+			delete(schema, key)
+		}
+	}
+
+	convertOpenAPIV3Schema(schema)
+
+	return fields, schema, nil
+}
+
+func newOpenAPIV3Response(response spec.Response, mediaTypes []string) (openAPIV3Response, error) {
+	if ref := response.Ref.String(); ref != "" {
+		return openAPIV3Response{Ref: openAPIV3Ref(ref)}, nil
+	}
+
+	responseV3 := openAPIV3Response{
+		Description: response.Description,
+		Extensions:  response.Extensions,
+	}
+
+	for name, header := range response.Headers {
+		schema, err := toJSONObject(header)
+		if err != nil {
+			return responseV3, err
+		}
+
+		headerV3 := make(map[string]interface{})
+
+		for key, value := range schema {
+			if key == "description" || strings.HasPrefix(key, "x-") && key != "x-nullable" {
+				headerV3[key] = value
+				delete(schema, key)
+			}
+		}
+
+		convertOpenAPIV3Schema(schema)
+		headerV3["schema"] = schema
+
+		if responseV3.Headers == nil {
+			responseV3.Headers = make(map[string]map[string]interface{}, len(response.Headers))
+		}
+
+		responseV3.Headers[name] = headerV3
+	}
+
+	if response.Schema == nil && len(response.Examples) == 0 {
+		return responseV3, nil
+	}
+
+	responseV3.Content = make(map[string]openAPIV3MediaType, len(mediaTypes))
+
+	if response.Schema != nil {
+		schema, err := newOpenAPIV3Schema(response.Schema)
+		if err != nil {
+			return responseV3, err
+		}
+
+		for _, mediaType := range mediaTypes {
+			responseV3.Content[mediaType] = openAPIV3MediaType{Schema: schema}
+		}
+	}
+
+	for mediaType, example := range response.Examples {
+		content := responseV3.Content[mediaType]
+		content.Example = example
+		responseV3.Content[mediaType] = content
+	}
+
+	return responseV3, nil
+}
+
+// newOpenAPIV3Schema converts a Swagger 2.0 schema into an OpenAPI 3.1 schema object.
+func newOpenAPIV3Schema(schema interface{}) (map[string]interface{}, error) {
+	schemaV3, err := toJSONObject(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	convertOpenAPIV3Schema(schemaV3)
+
+	return schemaV3, nil
+}
+
+// toJSONObject decodes the JSON encoding of a value as a generic JSON object.
+func toJSONObject(value interface{}) (map[string]interface{}, error) {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(jsonData, &object); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// openAPIV3Ref converts a Swagger 2.0 reference into a reference to the OpenAPI 3.1 components.
+func openAPIV3Ref(ref string) string {
+	for prefix, prefixV3 := range openAPIV3RefPrefixes {
+		if strings.HasPrefix(ref, prefix) {
+			return prefixV3 + strings.TrimPrefix(ref, prefix)
+		}
+	}
+
+	return ref
+}
+
+// convertOpenAPIV3Schema translates a decoded Swagger 2.0 schema in place into a JSON Schema 2020-12 schema.
+func convertOpenAPIV3Schema(schema map[string]interface{}) {
+	jsonschema.Convert(schema, openAPIV3Ref, convertOpenAPIV3Keywords)
+}
+
+// convertOpenAPIV3Keywords translates the keywords whose meaning differs between Swagger 2.0 and OpenAPI 3.1.
+func convertOpenAPIV3Keywords(schema map[string]interface{}) {
+	// Swagger 2.0 describes a discriminator with the name of its property only.
+	if propertyName, ok := schema["discriminator"].(string); ok {
+		schema["discriminator"] = map[string]interface{}{"propertyName": propertyName}
+	}
+
+	if schema["type"] == "file" {
+		schema["type"] = "string"
+		schema["contentMediaType"] = openAPIFileMediaType
+	}
+
+	// collectionFormat of parameter items is described by the parameter style.
+	delete(schema, "collectionFormat")
+
+	// x-nullable fields allow null natively.
+	if xNullable, ok := schema["x-nullable"].(bool); ok {
+		delete(schema, "x-nullable")
+
+		if xNullable {
+			schema["nullable"] = true
+		}
+	}
+}
+
+// convertOpenAPIV3Polymorphisms turns the Swagger 2.0 polymorphic schemas, whose subtypes extend the schema holding
+// the discriminator with allOf and tell their value with x-discriminator-value, into a oneOf of the subtypes with a
+// discriminator mapping. The subtypes copy the properties of the base schema instead of referencing it, so that the
+// schemas don't reference each other.
+func convertOpenAPIV3Polymorphisms(schemas map[string]map[string]interface{}) {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	subtypes := make(map[string][]string)
+
+	for _, name := range names {
+		schema := schemas[name]
+
+		value, ok := schema[discriminatorValueExtension].(string)
+		if !ok {
+			continue
+		}
+
+		allOf, _ := schema["allOf"].([]interface{})
+		for i, item := range allOf {
+			itemSchema, _ := item.(map[string]interface{})
+			ref, _ := itemSchema["$ref"].(string)
+
+			baseName := strings.TrimPrefix(ref, openAPIV3SchemaRefPrefix)
+			base, ok := schemas[baseName]
+			if !ok || baseName == ref {
+				continue
+			}
+
+			discriminator, ok := base["discriminator"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			allOf[i] = openAPIV3BaseProperties(base, discriminator["propertyName"], value)
+			delete(schema, discriminatorValueExtension)
+
+			mapping, _ := discriminator["mapping"].(map[string]interface{})
+			if mapping == nil {
+				mapping = make(map[string]interface{})
+				discriminator["mapping"] = mapping
+			}
+
+			mapping[value] = openAPIV3SchemaRefPrefix + name
+			subtypes[baseName] = append(subtypes[baseName], name)
+
+			break
+		}
+	}
+
+	for baseName, names := range subtypes {
+		base := schemas[baseName]
+
+		oneOf := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			oneOf = append(oneOf, map[string]interface{}{"$ref": openAPIV3SchemaRefPrefix + name})
+		}
+
+		delete(base, "type")
+		delete(base, "properties")
+		delete(base, "required")
+		base["oneOf"] = oneOf
+	}
+}
+
+// openAPIV3BaseProperties returns the properties of a polymorphic base schema to be copied into a subtype, with the
+// discriminator property restricted to the value of the subtype, so that a value matches a single subtype.
+func openAPIV3BaseProperties(base map[string]interface{}, propertyName interface{}, value string) map[string]interface{} {
+	properties := make(map[string]interface{})
+
+	baseProperties, _ := base["properties"].(map[string]interface{})
+	for name, property := range baseProperties {
+		properties[name] = property
+	}
+
+	if name, ok := propertyName.(string); ok {
+		properties[name] = map[string]interface{}{"type": "string", "const": value}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if required, ok := base["required"]; ok {
+		schema["required"] = required
+	}
+
+	return schema
+}
@@ -0,0 +1,329 @@
+package gen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOpenAPIV3Servers(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		basePath string
+		schemes  []string
+		expected []openAPIV3Server
+	}{
+		{name: "nothing"},
+		{name: "base path only", basePath: "/v1", expected: []openAPIV3Server{{URL: "/v1"}}},
+		{name: "host without scheme", host: "example.com", basePath: "/", expected: []openAPIV3Server{{URL: "//example.com"}}},
+		{
+			name:     "one server per scheme",
+			host:     "example.com:8080",
+			basePath: "/v1/",
+			schemes:  []string{"https", "http"},
+			expected: []openAPIV3Server{{URL: "https://example.com:8080/v1"}, {URL: "http://example.com:8080/v1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, newOpenAPIV3Servers(tt.host, tt.basePath, tt.schemes))
+		})
+	}
+}
+
+func TestNewOpenAPIV3SecurityScheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		scheme   *spec.SecurityScheme
+		expected string
+	}{
+		{
+			name:     "basic",
+			scheme:   spec.BasicAuth(),
+			expected: `{"type": "http", "scheme": "basic"}`,
+		},
+		{
+			name:     "api key",
+			scheme:   spec.APIKeyAuth("X-API-Key", "header"),
+			expected: `{"type": "apiKey", "name": "X-API-Key", "in": "header"}`,
+		},
+		{
+			name:     "implicit",
+			scheme:   spec.OAuth2Implicit("https://example.com/authorize"),
+			expected: `{"type": "oauth2", "flows": {"implicit": {"authorizationUrl": "https://example.com/authorize", "scopes": {}}}}`,
+		},
+		{
+			name:     "password",
+			scheme:   spec.OAuth2Password("https://example.com/token"),
+			expected: `{"type": "oauth2", "flows": {"password": {"tokenUrl": "https://example.com/token", "scopes": {}}}}`,
+		},
+		{
+			name: "application",
+			scheme: func() *spec.SecurityScheme {
+				scheme := spec.OAuth2Application("https://example.com/token")
+				scheme.AddScope("read", "Grants read access")
+				scheme.AddExtension("x-tokenName", "id_token")
+				return scheme
+			}(),
+			expected: `{"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": "https://example.com/token", "scopes": {"read": "Grants read access"}}}, "x-tokenname": "id_token"}`,
+		},
+		{
+			name:     "access code",
+			scheme:   spec.OAuth2AccessToken("https://example.com/authorize", "https://example.com/token"),
+			expected: `{"type": "oauth2", "flows": {"authorizationCode": {"authorizationUrl": "https://example.com/authorize", "tokenUrl": "https://example.com/token", "scopes": {}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(newOpenAPIV3SecurityScheme(tt.scheme))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestConvertOpenAPIV3Schema(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{
+			name:     "definition reference",
+			schema:   `{"type": "array", "items": {"$ref": "#/definitions/web.Pet"}}`,
+			expected: `{"type": "array", "items": {"$ref": "#/components/schemas/web.Pet"}}`,
+		},
+		{
+			name:     "nullable type",
+			schema:   `{"type": "object", "properties": {"name": {"type": "string", "enum": ["a", "b"], "x-nullable": true}}}`,
+			expected: `{"type": "object", "properties": {"name": {"type": ["string", "null"], "enum": ["a", "b", null]}}}`,
+		},
+		{
+			name:     "nullable reference",
+			schema:   `{"$ref": "#/definitions/web.Pet", "description": "Parent pet", "nullable": true}`,
+			expected: `{"oneOf": [{"$ref": "#/components/schemas/web.Pet"}, {"type": "null"}], "description": "Parent pet"}`,
+		},
+		{
+			name:     "not nullable",
+			schema:   `{"type": "string", "x-nullable": false}`,
+			expected: `{"type": "string"}`,
+		},
+		{
+			name:     "exclusive bounds",
+			schema:   `{"type": "integer", "minimum": 1, "exclusiveMinimum": true, "maximum": 10, "exclusiveMaximum": false}`,
+			expected: `{"type": "integer", "exclusiveMinimum": 1, "maximum": 10}`,
+		},
+		{
+			name:     "discriminator",
+			schema:   `{"type": "object", "discriminator": "kind", "allOf": [{"$ref": "#/definitions/web.Base"}]}`,
+			expected: `{"type": "object", "discriminator": {"propertyName": "kind"}, "allOf": [{"$ref": "#/components/schemas/web.Base"}]}`,
+		},
+		{
+			name:     "file",
+			schema:   `{"type": "file"}`,
+			expected: `{"type": "string", "contentMediaType": "application/octet-stream"}`,
+		},
+		{
+			name:     "example values are kept",
+			schema:   `{"type": "object", "example": {"$ref": "#/definitions/web.Pet", "nullable": true}}`,
+			expected: `{"type": "object", "example": {"$ref": "#/definitions/web.Pet", "nullable": true}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.schema), &schema))
+
+			convertOpenAPIV3Schema(schema)
+
+			b, err := json.Marshal(schema)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestNewOpenAPIV3(t *testing.T) {
+	swagger := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+	"swagger": "2.0",
+	"info": {"title": "Pets", "version": "1.0"},
+	"host": "example.com",
+	"basePath": "/api",
+	"schemes": ["https"],
+	"produces": ["application/json", "application/xml"],
+	"paths": {
+		"/pets/{id}": {
+			"parameters": [{"name": "id", "in": "path", "required": true, "type": "integer"}],
+			"put": {
+				"operationId": "updatePet",
+				"consumes": ["application/json"],
+				"parameters": [
+					{"name": "pet", "in": "body", "description": "Pet to update", "required": true, "schema": {"$ref": "#/definitions/Pet"}},
+					{"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+					{"$ref": "#/parameters/trace"}
+				],
+				"responses": {
+					"200": {
+						"description": "OK",
+						"headers": {"X-Rate-Limit": {"type": "integer", "description": "Calls per hour"}},
+						"schema": {"$ref": "#/definitions/Pet"}
+					},
+					"default": {"$ref": "#/responses/Error"}
+				},
+				"x-codeSamples": [{"lang": "curl"}]
+			}
+		},
+		"/pets/{id}/photo": {
+			"post": {
+				"parameters": [
+					{"name": "id", "in": "path", "required": true, "type": "integer"},
+					{"name": "photo", "in": "formData", "required": true, "type": "file"},
+					{"name": "caption", "in": "formData", "type": "string", "description": "Photo caption"}
+				],
+				"responses": {"204": {"description": "No Content"}}
+			}
+		}
+	},
+	"definitions": {
+		"Pet": {"type": "object", "properties": {"parent": {"$ref": "#/definitions/Pet", "x-nullable": true}}}
+	},
+	"parameters": {
+		"trace": {"name": "X-Trace", "in": "header", "type": "string"}
+	},
+	"responses": {
+		"Error": {"description": "Error", "schema": {"type": "string"}}
+	},
+	"x-logo": "logo.png"
+}`), swagger))
+
+	doc, err := newOpenAPIV3(swagger)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+	"openapi": "3.1.0",
+	"info": {"title": "Pets", "version": "1.0"},
+	"servers": [{"url": "https://example.com/api"}],
+	"paths": {
+		"/pets/{id}": {
+			"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+			"put": {
+				"operationId": "updatePet",
+				"parameters": [
+					{"name": "tags", "in": "query", "style": "form", "explode": true, "schema": {"type": "array", "items": {"type": "string"}}},
+					{"$ref": "#/components/parameters/trace"}
+				],
+				"requestBody": {
+					"description": "Pet to update",
+					"required": true,
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+				},
+				"responses": {
+					"200": {
+						"description": "OK",
+						"headers": {"X-Rate-Limit": {"description": "Calls per hour", "schema": {"type": "integer"}}},
+						"content": {
+							"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}},
+							"application/xml": {"schema": {"$ref": "#/components/schemas/Pet"}}
+						}
+					},
+					"default": {"$ref": "#/components/responses/Error"}
+				},
+				"x-codeSamples": [{"lang": "curl"}]
+			}
+		},
+		"/pets/{id}/photo": {
+			"post": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"multipart/form-data": {
+							"schema": {
+								"type": "object",
+								"properties": {
+									"photo": {"type": "string", "contentMediaType": "application/octet-stream"},
+									"caption": {"type": "string", "description": "Photo caption"}
+								},
+								"required": ["photo"]
+							}
+						}
+					}
+				},
+				"responses": {"204": {"description": "No Content"}}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"Pet": {
+				"type": "object",
+				"properties": {"parent": {"oneOf": [{"$ref": "#/components/schemas/Pet"}, {"type": "null"}]}}
+			}
+		},
+		"parameters": {
+			"trace": {"name": "X-Trace", "in": "header", "schema": {"type": "string"}}
+		},
+		"responses": {
+			"Error": {
+				"description": "Error",
+				"content": {
+					"application/json": {"schema": {"type": "string"}},
+					"application/xml": {"schema": {"type": "string"}}
+				}
+			}
+		}
+	},
+	"x-logo": "logo.png"
+}`, string(b))
+}
+
+func TestNewOpenAPIV3_UndefinedParameter(t *testing.T) {
+	swagger := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+	"paths": {"/pets": {"get": {"parameters": [{"$ref": "#/parameters/limit"}]}}}
+}`), swagger))
+
+	_, err := newOpenAPIV3(swagger)
+	assert.EqualError(t, err, "path '/pets' is invalid: parameter '#/parameters/limit' is not defined")
+}
+
+func TestConvertOpenAPIV3Polymorphisms(t *testing.T) {
+	var schemas map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+	"web.Pet": {"type": "object", "description": "A pet", "required": ["type"],
+		"properties": {"type": {"type": "string", "enum": ["cat", "Dog"]}}, "discriminator": {"propertyName": "type"}},
+	"web.Cat": {"type": "object", "x-discriminator-value": "cat",
+		"allOf": [{"$ref": "#/components/schemas/web.Pet"}, {"type": "object", "properties": {"lives": {"type": "integer"}}}]},
+	"web.Dog": {"type": "object", "x-discriminator-value": "Dog",
+		"allOf": [{"$ref": "#/components/schemas/web.Pet"}, {"type": "object", "properties": {"friend": {"$ref": "#/components/schemas/web.Pet"}}}]},
+	"web.Owner": {"type": "object", "allOf": [{"$ref": "#/components/schemas/web.Pet"}]}
+}`), &schemas))
+
+	convertOpenAPIV3Polymorphisms(schemas)
+
+	b, err := json.Marshal(schemas)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+	"web.Pet": {"description": "A pet", "oneOf": [{"$ref": "#/components/schemas/web.Cat"}, {"$ref": "#/components/schemas/web.Dog"}],
+		"discriminator": {"propertyName": "type",
+			"mapping": {"cat": "#/components/schemas/web.Cat", "Dog": "#/components/schemas/web.Dog"}}},
+	"web.Cat": {"type": "object",
+		"allOf": [{"type": "object", "required": ["type"], "properties": {"type": {"type": "string", "const": "cat"}}},
+			{"type": "object", "properties": {"lives": {"type": "integer"}}}]},
+	"web.Dog": {"type": "object",
+		"allOf": [{"type": "object", "required": ["type"], "properties": {"type": {"type": "string", "const": "Dog"}}},
+			{"type": "object", "properties": {"friend": {"$ref": "#/components/schemas/web.Pet"}}}]},
+	"web.Owner": {"type": "object", "allOf": [{"$ref": "#/components/schemas/web.Pet"}]}
+}`, string(b))
+}
@@ -0,0 +1,253 @@
+package gen
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Durations of the watch mode, variables so that tests can shorten them.
+var (
+	// watchDebounce is how long the files must stay unchanged before the docs are built again
+	watchDebounce = 300 * time.Millisecond
+
+	// watchPollInterval is how often the files are checked when inotify is unavailable
+	watchPollInterval = time.Second
+)
+
+// errInotifyUnavailable is returned when the files can't be watched with inotify, they are polled instead.
+var errInotifyUnavailable = errors.New("inotify is unavailable")
+
+// watcher notifies the changes of the Go files of the watched dirs.
+type watcher interface {
+	// Changes returns the channel notified after the Go files change.
+	Changes() <-chan struct{}
+
+	// Close stops watching.
+	Close() error
+}
+
+// Watch builds the docs, then builds them again whenever the Go files of the search dirs change, until the context
+// is done. The changes are debounced, the errors are printed without stopping to watch, and the unchanged output
+// files aren't written again.
+func (g *Gen) Watch(ctx context.Context, config *Config) error {
+	if err := g.Build(config); err != nil {
+		log.Printf("%s", err)
+	}
+
+	w, err := newWatcher(config)
+	if err != nil {
+		return err
+	}
+
+	defer w.Close()
+
+	g.debug.Printf("Watching %s for changes....", config.SearchDir)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.Changes():
+		}
+
+		if !debounce(ctx, w.Changes(), watchDebounce) {
+			return nil
+		}
+
+		if err := g.Build(config); err != nil {
+			log.Printf("%s", err)
+		}
+	}
+}
+
+// debounce waits until nothing is received on changes for the delay, it returns false if the context is done first.
+func debounce(ctx context.Context, changes <-chan struct{}, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-changes:
+			if !timer.Stop() {
+				<-timer.C
+			}
+
+			timer.Reset(delay)
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// newWatcher watches the search dirs with inotify, or polls them where inotify is unavailable.
+func newWatcher(config *Config) (watcher, error) {
+	dirs, err := newWatchedDirs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := newInotifyWatcher(dirs)
+	if err == nil {
+		return w, nil
+	}
+
+	log.Printf("cannot watch the files with inotify, polling them instead: %s", err)
+
+	return newPollWatcher(dirs, watchPollInterval), nil
+}
+
+// watchedDirs are the search dirs, without the excluded dirs and the output dir.
+type watchedDirs struct {
+	searchDirs []string
+	skip       func(path string, f os.FileInfo) error
+	outputDir  string
+}
+
+func newWatchedDirs(config *Config) (*watchedDirs, error) {
+	outputDir, err := filepath.Abs(config.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &watchedDirs{
+		searchDirs: strings.Split(config.SearchDir, ","),
+		skip:       newParser(config, nil).Skip,
+		outputDir:  outputDir,
+	}, nil
+}
+
+// walk calls fn with every watched dir and Go file under root.
+func (dirs *watchedDirs) walk(root string, fn func(path string, f os.FileInfo)) {
+	_ = filepath.Walk(root, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if f.IsDir() && dirs.isOutputDir(path) {
+			return filepath.SkipDir
+		}
+
+		if err := dirs.skip(path, f); err != nil {
+			return err
+		}
+
+		if f.IsDir() || isWatchedFile(path) {
+			fn(path, f)
+		}
+
+		return nil
+	})
+}
+
+func (dirs *watchedDirs) isOutputDir(path string) bool {
+	absPath, err := filepath.Abs(path)
+
+	return err == nil && absPath == dirs.outputDir
+}
+
+// isWatchedFile reports whether a change of a file may change the docs.
+func isWatchedFile(path string) bool {
+	return filepath.Ext(path) == ".go" && !strings.HasSuffix(path, "_test.go")
+}
+
+// notify sends a change without blocking, a pending change is enough.
+func notify(changes chan struct{}) {
+	select {
+	case changes <- struct{}{}:
+	default:
+	}
+}
+
+// pollWatcher polls the modification time and the size of the Go files.
+type pollWatcher struct {
+	dirs    *watchedDirs
+	changes chan struct{}
+	done    chan struct{}
+}
+
+type pollState struct {
+	modTime time.Time
+	size    int64
+}
+
+func newPollWatcher(dirs *watchedDirs, interval time.Duration) *pollWatcher {
+	w := &pollWatcher{
+		dirs:    dirs,
+		changes: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	go w.poll(interval)
+
+	return w
+}
+
+func (w *pollWatcher) snapshot() map[string]pollState {
+	files := make(map[string]pollState)
+
+	for _, searchDir := range w.dirs.searchDirs {
+		w.dirs.walk(searchDir, func(path string, f os.FileInfo) {
+			if !f.IsDir() {
+				files[path] = pollState{modTime: f.ModTime(), size: f.Size()}
+			}
+		})
+	}
+
+	return files
+}
+
+func (w *pollWatcher) poll(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous := w.snapshot()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		current := w.snapshot()
+		if !sameSnapshot(previous, current) {
+			notify(w.changes)
+		}
+
+		previous = current
+	}
+}
+
+func sameSnapshot(previous, current map[string]pollState) bool {
+	if len(previous) != len(current) {
+		return false
+	}
+
+	for path, state := range current {
+		if previousState, ok := previous[path]; !ok || !previousState.modTime.Equal(state.modTime) ||
+			previousState.size != state.size {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Changes returns the channel notified after the Go files change.
+func (w *pollWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops polling.
+func (w *pollWatcher) Close() error {
+	close(w.done)
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask are the events of the watched dirs which may change the docs.
+const inotifyMask = unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_MOVED_FROM |
+	unix.IN_MOVED_TO | unix.IN_DELETE_SELF
+
+// inotifyWatcher watches the dirs with inotify, and the dirs created later.
+type inotifyWatcher struct {
+	fd      int
+	dirs    *watchedDirs
+	changes chan struct{}
+	done    chan struct{}
+	stopped sync.WaitGroup
+
+	// paths holds the path of the dirs by watch descriptor
+	paths map[int]string
+}
+
+func newInotifyWatcher(dirs *watchedDirs) (watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInotifyUnavailable, err)
+	}
+
+	w := &inotifyWatcher{
+		fd:      fd,
+		dirs:    dirs,
+		changes: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		paths:   make(map[int]string),
+	}
+
+	for _, searchDir := range dirs.searchDirs {
+		if err = w.addDirs(searchDir); err != nil {
+			_ = unix.Close(fd)
+
+			return nil, fmt.Errorf("%w: %s", errInotifyUnavailable, err)
+		}
+	}
+
+	w.stopped.Add(1)
+
+	go w.read()
+
+	return w, nil
+}
+
+// addDirs watches a dir and the dirs under it.
+func (w *inotifyWatcher) addDirs(root string) error {
+	var err error
+
+	w.dirs.walk(root, func(path string, f os.FileInfo) {
+		if !f.IsDir() || err != nil {
+			return
+		}
+
+		var wd int
+
+		wd, err = unix.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err == nil {
+			w.paths[wd] = path
+		}
+	})
+
+	return err
+}
+
+func (w *inotifyWatcher) read() {
+	defer w.stopped.Done()
+
+	buffer := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+
+	for {
+		select {
+		case <-w.done:
+			return
+		default:
+		}
+
+		// poll with a timeout, so that closing the watcher is noticed
+		fds := []unix.PollFd{{Fd: int32(w.fd), Events: unix.POLLIN}}
+		if n, err := unix.Poll(fds, 100); err != nil || n == 0 {
+			continue
+		}
+
+		n, err := unix.Read(w.fd, buffer)
+		if err != nil || n < unix.SizeofInotifyEvent {
+			continue
+		}
+
+		if w.handleEvents(buffer[:n]) {
+			notify(w.changes)
+		}
+	}
+}
+
+// handleEvents watches the created dirs and reports whether the events may change the docs.
+func (w *inotifyWatcher) handleEvents(buffer []byte) bool {
+	changed := false
+
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buffer); {
+		event := (*unix.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+		nameStart := offset + unix.SizeofInotifyEvent
+		nameEnd := nameStart + int(event.Len)
+		offset = nameEnd
+
+		if nameEnd > len(buffer) {
+			break
+		}
+
+		dir, ok := w.paths[int(event.Wd)]
+		if !ok {
+			continue
+		}
+
+		if event.Mask&unix.IN_DELETE_SELF != 0 {
+			delete(w.paths, int(event.Wd))
+
+			continue
+		}
+
+		name := string(trimNull(buffer[nameStart:nameEnd]))
+		path := filepath.Join(dir, name)
+
+		switch {
+		case event.Mask&unix.IN_ISDIR != 0:
+			if w.dirs.isOutputDir(path) {
+				continue
+			}
+
+			if event.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+				_ = w.addDirs(path)
+			}
+
+			changed = true
+		case isWatchedFile(path):
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func trimNull(name []byte) []byte {
+	for i, b := range name {
+		if b == 0 {
+			return name[:i]
+		}
+	}
+
+	return name
+}
+
+// Changes returns the channel notified after the Go files change.
+func (w *inotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops watching and closes the inotify file descriptor.
+func (w *inotifyWatcher) Close() error {
+	close(w.done)
+	w.stopped.Wait()
+
+	return unix.Close(w.fd)
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gen
+
+// newInotifyWatcher returns errInotifyUnavailable, the files are polled outside of Linux.
+func newInotifyWatcher(_ *watchedDirs) (watcher, error) {
+	return nil, errInotifyUnavailable
+}
@@ -0,0 +1,166 @@
+package gen
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const watchedAPI = `package api
+
+// GetUser
+// @Summary Get a user
+// @Param id path int true "User ID"
+// @Success 200 {string} string
+// @Router /users/{id} [get]
+func GetUser() {}
+`
+
+// writeWatchedModule writes a module with an operation and returns its dir.
+func writeWatchedModule(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/watch\n\ngo 1.18\n",
+		"main.go": `package main
+
+// @title Swagger Example API
+// @version 1.0
+func main() {}
+`,
+		"api/api.go": watchedAPI,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func waitForFile(t *testing.T, path string, condition func(content string) bool) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(path)
+
+		return err == nil && condition(string(content))
+	}, 10*time.Second, 20*time.Millisecond)
+}
+
+func TestGen_Watch(t *testing.T) {
+	dir := writeWatchedModule(t)
+	outputDir := filepath.Join(dir, "docs")
+	swaggerJSON := filepath.Join(outputDir, "swagger.json")
+
+	config := &Config{
+		SearchDir:   dir,
+		MainAPIFile: "./main.go",
+		OutputDir:   outputDir,
+		OutputTypes: []string{"json"},
+		Debugger:    log.New(os.Stderr, "", 0),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- New().Watch(ctx, config)
+	}()
+
+	waitForFile(t, swaggerJSON, func(content string) bool {
+		return strings.Contains(content, "/users/{id}")
+	})
+
+	// an invalid annotation is printed without stopping to watch
+	api := filepath.Join(dir, "api", "api.go")
+	require.NoError(t, os.WriteFile(api, []byte(strings.Replace(watchedAPI, "[get]", "[get", 1)), 0644))
+	time.Sleep(2 * watchDebounce)
+
+	require.NoError(t, os.WriteFile(api, []byte(strings.Replace(watchedAPI, "/users/{id}", "/accounts/{id}", 1)), 0644))
+
+	waitForFile(t, swaggerJSON, func(content string) bool {
+		return strings.Contains(content, "/accounts/{id}")
+	})
+
+	_, err := os.Stat(filepath.Join(outputDir, "docs.go"))
+	assert.True(t, os.IsNotExist(err))
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestPollWatcher(t *testing.T) {
+	dir := writeWatchedModule(t)
+
+	dirs, err := newWatchedDirs(&Config{SearchDir: dir, OutputDir: filepath.Join(dir, "docs")})
+	require.NoError(t, err)
+
+	w := newPollWatcher(dirs, 10*time.Millisecond)
+	defer w.Close()
+
+	// the output dir and the other files are ignored
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "docs.go"), []byte("package docs\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# API\n"), 0644))
+
+	select {
+	case <-w.Changes():
+		t.Fatal("unexpected change")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api", "users.go"), []byte("package api\n"), 0644))
+
+	select {
+	case <-w.Changes():
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change")
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	changes := make(chan struct{})
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			changes <- struct{}{}
+		}
+	}()
+
+	start := time.Now()
+	assert.True(t, debounce(context.Background(), changes, 50*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, debounce(ctx, changes, time.Hour))
+}
+
+func TestWriteFileIfChanged(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "swagger.json")
+	require.NoError(t, writeFileIfChanged(file, []byte("{}")))
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(file, past, past))
+
+	require.NoError(t, writeFileIfChanged(file, []byte("{}")))
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(past))
+
+	require.NoError(t, writeFileIfChanged(file, []byte("{\"swagger\":\"2.0\"}")))
+	info, err = os.Stat(file)
+	require.NoError(t, err)
+	assert.False(t, info.ModTime().Equal(past))
+}
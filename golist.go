@@ -58,21 +58,15 @@ func (parser *Parser) getAllGoFileInfoFromDepsByList(pkg *build.Package, parseFl
 	}
 
 	srcDir := pkg.Dir
-	var err error
+	files := make([]goFile, 0, len(pkg.GoFiles)+len(pkg.CgoFiles))
 	for i := range pkg.GoFiles {
-		err = parser.parseFile(pkg.ImportPath, filepath.Join(srcDir, pkg.GoFiles[i]), nil, parseFlag)
-		if err != nil {
-			return err
-		}
+		files = append(files, goFile{packageDir: pkg.ImportPath, path: filepath.Join(srcDir, pkg.GoFiles[i])})
 	}
 
 	// parse .go source files that import "C"
 	for i := range pkg.CgoFiles {
-		err = parser.parseFile(pkg.ImportPath, filepath.Join(srcDir, pkg.CgoFiles[i]), nil, parseFlag)
-		if err != nil {
-			return err
-		}
+		files = append(files, goFile{packageDir: pkg.ImportPath, path: filepath.Join(srcDir, pkg.CgoFiles[i])})
 	}
 
-	return nil
+	return parser.parseFiles(files, parseFlag)
 }
@@ -0,0 +1,514 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+// inferredHandler holds what is inferred from the code of a handler.
+type inferredHandler struct {
+	// routes registered with the handler, see SetInferRoutes
+	routes []RouteProperties
+
+	// body is the type decoded from the request body, see SetInferSchemas
+	body *inferredType
+
+	// responses are the types encoded in the response by status code, nil for an empty body
+	responses map[int]*inferredType
+
+	// codes are the status codes of the responses, in the order of the code
+	codes []int
+}
+
+// inferredType is a Go type as a type of the annotations, like []Account, resolved in the file of the type.
+type inferredType struct {
+	name string
+	file *ast.File
+}
+
+// handlerKey identifies a handler function of a package, or a method of a receiver type.
+type handlerKey struct {
+	pkgPath  string
+	receiver string
+	name     string
+}
+
+// SetInferSchemas sets whether the request body and the responses of the handlers are inferred by type-checking
+// their code, the annotations win over the inferred schemas.
+func SetInferSchemas(inferSchemas bool) func(*Parser) {
+	return func(p *Parser) {
+		p.inferSchemas = inferSchemas
+	}
+}
+
+// inferHandler returns what is inferred from the code of the handler of a declaration, nil if nothing is.
+func (parser *Parser) inferHandler(fileInfo *AstFileInfo, decl ast.Decl) *inferredHandler {
+	handler := &inferredHandler{}
+
+	if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+		key := handlerKey{pkgPath: fileInfo.PackagePath, receiver: receiverTypeName(funcDecl), name: funcDecl.Name.Name}
+		if schemas, ok := parser.inferredSchemas[key]; ok {
+			*handler = *schemas
+		}
+	}
+
+	handler.routes = parser.registeredRoutesOf(fileInfo, decl)
+
+	if len(handler.routes) == 0 && handler.body == nil && len(handler.codes) == 0 {
+		return nil
+	}
+
+	return handler
+}
+
+// receiverTypeName returns the name of the receiver type of a method, empty for a function.
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := funcDecl.Recv.List[0].Type
+	for {
+		switch typeExpr := expr.(type) {
+		case *ast.StarExpr:
+			expr = typeExpr.X
+		case *ast.IndexExpr:
+			expr = typeExpr.X
+		case *ast.IndexListExpr:
+			expr = typeExpr.X
+		case *ast.Ident:
+			return typeExpr.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// inferHandlerSchemas type-checks the packages with operations of every search directory and infers the request
+// body and the responses of their functions.
+func (parser *Parser) inferHandlerSchemas(searchDirs []string) error {
+	// the package path of the directories with operations, by search directory
+	pkgPaths := make(map[string]string)
+	dirs := make(map[string][]string)
+
+	for _, searchDir := range searchDirs {
+		absSearchDir, err := filepath.Abs(searchDir)
+		if err != nil {
+			return err
+		}
+
+		_ = parser.packages.RangeFiles(func(fileInfo *AstFileInfo) error {
+			dir := filepath.Dir(fileInfo.Path)
+			if _, ok := pkgPaths[dir]; ok || (fileInfo.ParseFlag&ParseOperations) == ParseNone {
+				return nil
+			}
+
+			if relPath, err := filepath.Rel(absSearchDir, dir); err == nil && !strings.HasPrefix(relPath, "..") {
+				pkgPaths[dir] = fileInfo.PackagePath
+				dirs[absSearchDir] = append(dirs[absSearchDir], dir)
+			}
+
+			return nil
+		})
+	}
+
+	fileSet := token.NewFileSet()
+	exports := make(map[string]string)
+	imports := &exportImporter{
+		exports: exports,
+		export: importer.ForCompiler(fileSet, "gc", func(path string) (io.ReadCloser, error) {
+			return os.Open(exports[path])
+		}),
+		source: importer.ForCompiler(fileSet, "source", nil),
+	}
+	conf := types.Config{
+		Importer: imports,
+		Error: func(err error) {
+			parser.debug.Printf("warning: cannot type-check the handlers: %s", err)
+		},
+	}
+
+	for _, searchDir := range searchDirs {
+		absSearchDir, _ := filepath.Abs(searchDir)
+		if len(dirs[absSearchDir]) == 0 {
+			continue
+		}
+
+		// the packages are type-checked below, as go/packages can't read the export data of the recent toolchains
+		// nor size their types, the imports are read from the export data of the build cache listed here
+		pkgs, err := packages.Load(&packages.Config{
+			Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedImports | packages.NeedExportFile,
+			Dir:  absSearchDir,
+			Fset: fileSet,
+		}, dirs[absSearchDir]...)
+		if err != nil {
+			return fmt.Errorf("cannot load the handlers of %s: %w", searchDir, err)
+		}
+
+		for _, pkg := range pkgs {
+			for path, imported := range pkg.Imports {
+				if imported.ExportFile != "" {
+					exports[path] = imported.ExportFile
+				}
+			}
+		}
+
+		for _, pkg := range pkgs {
+			if len(pkg.Errors) > 0 || len(pkg.GoFiles) == 0 {
+				parser.debug.Printf("warning: cannot infer the schemas of the handlers of %s: %v", pkg.PkgPath, pkg.Errors)
+
+				continue
+			}
+
+			info := &types.Info{
+				Types: make(map[ast.Expr]types.TypeAndValue),
+				Defs:  make(map[*ast.Ident]types.Object),
+				Uses:  make(map[*ast.Ident]types.Object),
+			}
+
+			// the errors are reported by conf.Error
+			_, _ = conf.Check(pkg.PkgPath, fileSet, pkg.Syntax, info)
+
+			parser.inferPackageSchemas(pkgPaths[filepath.Dir(pkg.GoFiles[0])], pkg.Syntax, info)
+		}
+	}
+
+	return nil
+}
+
+// exportImporter imports the packages from their export data, and from source when there is none or when it was
+// written by another version of the toolchain.
+type exportImporter struct {
+	// exports are the export data files by import path
+	exports map[string]string
+	export  types.Importer
+	source  types.Importer
+}
+
+func (imports *exportImporter) Import(path string) (*types.Package, error) {
+	if _, ok := imports.exports[path]; ok {
+		if pkg, err := imports.importExport(path); err == nil {
+			return pkg, nil
+		}
+	}
+
+	return imports.source.Import(path)
+}
+
+func (imports *exportImporter) importExport(path string) (pkg *types.Package, err error) {
+	defer func() {
+		// the decoder panics on the export data of an unknown version
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot read the export data of %s: %v", path, r)
+		}
+	}()
+
+	return imports.export.Import(path)
+}
+
+// inferPackageSchemas infers the request body and the responses of the functions of a type-checked package.
+func (parser *Parser) inferPackageSchemas(pkgPath string, files []*ast.File, info *types.Info) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+
+			analyzer := &handlerAnalyzer{
+				parser:  parser,
+				info:    info,
+				handler: &inferredHandler{responses: make(map[int]*inferredType)},
+			}
+
+			ast.Inspect(funcDecl.Body, analyzer.visit)
+
+			if analyzer.handler.body != nil || len(analyzer.handler.codes) > 0 {
+				key := handlerKey{pkgPath: pkgPath, receiver: receiverTypeName(funcDecl), name: funcDecl.Name.Name}
+				parser.inferredSchemas[key] = analyzer.handler
+			}
+		}
+	}
+}
+
+// handlerAnalyzer infers the request body and the responses of a handler from the calls of gin, echo and
+// net/http with encoding/json.
+type handlerAnalyzer struct {
+	parser  *Parser
+	info    *types.Info
+	handler *inferredHandler
+
+	// status written with http.ResponseWriter.WriteHeader, 0 for http.StatusOK
+	status int
+}
+
+// contextTypes are the context types of gin and echo, whose methods decode and encode the bodies, by package.
+var contextTypes = map[string]string{
+	"github.com/gin-gonic/gin":    "Context",
+	"github.com/labstack/echo/v4": "Context",
+	"github.com/labstack/echo":    "Context",
+}
+
+// bindMethods decode the request body: gin ShouldBindJSON(&req), echo Bind(&req).
+var bindMethods = map[string]bool{
+	"Bind":           true,
+	"BindJSON":       true,
+	"ShouldBind":     true,
+	"ShouldBindJSON": true,
+}
+
+// renderMethods encode a response body with a status code: gin and echo JSON(http.StatusOK, resp).
+var renderMethods = map[string]bool{
+	"AbortWithStatusJSON": true,
+	"IndentedJSON":        true,
+	"JSON":                true,
+	"JSONPretty":          true,
+	"PureJSON":            true,
+	"SecureJSON":          true,
+}
+
+func (analyzer *handlerAnalyzer) visit(node ast.Node) bool {
+	call, ok := node.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+
+	name := selector.Sel.Name
+
+	switch {
+	case bindMethods[name] && len(call.Args) == 1 && analyzer.isContextMethod(selector):
+		analyzer.setBody(call.Args[0])
+	case name == "Decode" && len(call.Args) == 1 && analyzer.isNamed(selector.X, "encoding/json", "Decoder"):
+		analyzer.setBody(call.Args[0])
+	case renderMethods[name] && len(call.Args) >= 2 && analyzer.isContextMethod(selector):
+		if code := analyzer.statusCode(call.Args[0]); code != 0 {
+			analyzer.addResponse(code, call.Args[1])
+		}
+	case (name == "NoContent" || name == "AbortWithStatus") && len(call.Args) == 1 && analyzer.isContextMethod(selector):
+		if code := analyzer.statusCode(call.Args[0]); code != 0 {
+			analyzer.addResponse(code, nil)
+		}
+	case name == "WriteHeader" && len(call.Args) == 1:
+		analyzer.status = analyzer.statusCode(call.Args[0])
+	case name == "Encode" && len(call.Args) == 1 && analyzer.isNamed(selector.X, "encoding/json", "Encoder"):
+		code := analyzer.status
+		if code == 0 {
+			code = http.StatusOK
+		}
+
+		analyzer.addResponse(code, call.Args[0])
+		analyzer.status = 0
+	}
+
+	return true
+}
+
+// setBody sets the type decoded from the request body, the first one wins.
+func (analyzer *handlerAnalyzer) setBody(arg ast.Expr) {
+	if analyzer.handler.body != nil {
+		return
+	}
+
+	pointer, ok := analyzer.info.TypeOf(arg).(*types.Pointer)
+	if !ok {
+		return
+	}
+
+	analyzer.handler.body = analyzer.parser.inferredTypeOf(pointer.Elem())
+}
+
+// addResponse adds the response of a status code, the first one wins.
+func (analyzer *handlerAnalyzer) addResponse(code int, arg ast.Expr) {
+	if _, ok := analyzer.handler.responses[code]; ok {
+		return
+	}
+
+	var inferred *inferredType
+
+	if arg != nil {
+		if inferred = analyzer.parser.inferredTypeOf(analyzer.info.TypeOf(arg)); inferred == nil {
+			return
+		}
+	}
+
+	analyzer.handler.responses[code] = inferred
+	analyzer.handler.codes = append(analyzer.handler.codes, code)
+}
+
+// statusCode returns the constant HTTP status code of an expression, 0 if it isn't one.
+func (analyzer *handlerAnalyzer) statusCode(expr ast.Expr) int {
+	value := analyzer.info.Types[expr].Value
+	if value == nil || value.Kind() != constant.Int {
+		return 0
+	}
+
+	code, ok := constant.Int64Val(value)
+	if !ok || code < 100 || code > 599 {
+		return 0
+	}
+
+	return int(code)
+}
+
+func (analyzer *handlerAnalyzer) isNamed(expr ast.Expr, pkgPath, name string) bool {
+	return isNamedType(analyzer.info.TypeOf(expr), pkgPath, name)
+}
+
+// isContextMethod reports whether a method is one of the gin or echo context, also when it is promoted from an
+// embedded context.
+func (analyzer *handlerAnalyzer) isContextMethod(selector *ast.SelectorExpr) bool {
+	method, ok := analyzer.info.Uses[selector.Sel].(*types.Func)
+	if !ok || method.Pkg() == nil {
+		return false
+	}
+
+	recv := method.Type().(*types.Signature).Recv()
+	name, ok := contextTypes[method.Pkg().Path()]
+
+	return ok && recv != nil && isNamedType(recv.Type(), method.Pkg().Path(), name)
+}
+
+func isNamedType(typ types.Type, pkgPath, name string) bool {
+	if pointer, ok := typ.(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+
+	named, ok := typ.(*types.Named)
+
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// inferredTypeOf returns a Go type as a type of the annotations: a type parsed by swag, a slice or a map of them,
+// or a primitive type. It returns nil for the other types.
+func (parser *Parser) inferredTypeOf(typ types.Type) *inferredType {
+	switch typ := typ.(type) {
+	case *types.Pointer:
+		return parser.inferredTypeOf(typ.Elem())
+	case *types.Named:
+		if typ.TypeArgs().Len() > 0 || typ.Obj().Pkg() == nil {
+			return nil
+		}
+
+		if pkg, ok := parser.packages.packages[typ.Obj().Pkg().Path()]; ok {
+			if typeDef, ok := pkg.TypeDefinitions[typ.Obj().Name()]; ok {
+				return &inferredType{name: typ.Obj().Name(), file: typeDef.File}
+			}
+		}
+
+		// like gin.H
+		if _, ok := typ.Underlying().(*types.Map); ok {
+			return parser.inferredTypeOf(typ.Underlying())
+		}
+	case *types.Basic:
+		name := types.Default(typ).(*types.Basic).Name()
+		if IsGolangPrimitiveType(name) {
+			return &inferredType{name: name}
+		}
+	case *types.Slice:
+		if elem := parser.inferredTypeOf(typ.Elem()); elem != nil {
+			return &inferredType{name: "[]" + elem.name, file: elem.file}
+		}
+	case *types.Array:
+		if elem := parser.inferredTypeOf(typ.Elem()); elem != nil {
+			return &inferredType{name: "[]" + elem.name, file: elem.file}
+		}
+	case *types.Map:
+		if key, ok := typ.Key().Underlying().(*types.Basic); !ok || key.Info()&types.IsString == 0 {
+			return nil
+		}
+
+		if _, ok := typ.Elem().Underlying().(*types.Interface); ok {
+			return &inferredType{name: "map[string]interface{}"}
+		}
+
+		if elem := parser.inferredTypeOf(typ.Elem()); elem != nil {
+			return &inferredType{name: "map[string]" + elem.name, file: elem.file}
+		}
+	}
+
+	return nil
+}
+
+// mergeInferredSchemas adds the inferred request body and responses which the annotations of an operation don't
+// declare.
+func (parser *Parser) mergeInferredSchemas(operation *Operation, handler *inferredHandler, fileInfo *AstFileInfo) {
+	if handler == nil || len(operation.RouterProperties) == 0 {
+		return
+	}
+
+	if handler.body != nil && hasRequestBody(operation) {
+		schema, err := operation.parseObjectSchema(handler.body.name, inferredFile(handler.body, fileInfo))
+		if err != nil {
+			parser.debug.Printf("warning: cannot infer the request body of %s: %s", formatRoutes(operation.RouterProperties), err)
+		} else {
+			param := createParameter("body", "request body", "body", OBJECT, "", "", true, nil, "")
+			param.Schema = schema
+			operation.Operation.Parameters = append(operation.Operation.Parameters, param)
+		}
+	}
+
+	for _, code := range handler.codes {
+		if operation.Responses != nil {
+			if _, ok := operation.Responses.StatusCodeResponses[code]; ok {
+				continue
+			}
+		}
+
+		response := spec.NewResponse().WithDescription(http.StatusText(code))
+
+		if inferred := handler.responses[code]; inferred != nil {
+			schema, err := operation.parseObjectSchema(inferred.name, inferredFile(inferred, fileInfo))
+			if err != nil {
+				parser.debug.Printf("warning: cannot infer the response %d of %s: %s", code, formatRoutes(operation.RouterProperties), err)
+
+				continue
+			}
+
+			response.WithSchema(schema)
+		}
+
+		operation.AddResponse(code, response)
+	}
+}
+
+// hasRequestBody reports whether an operation has a method with a body and no body or form parameter yet.
+func hasRequestBody(operation *Operation) bool {
+	for _, param := range operation.Parameters {
+		if param.In == "body" || param.In == "formData" {
+			return false
+		}
+	}
+
+	for _, route := range operation.RouterProperties {
+		if route.HTTPMethod == http.MethodPost || route.HTTPMethod == http.MethodPut || route.HTTPMethod == http.MethodPatch {
+			return true
+		}
+	}
+
+	return false
+}
+
+func inferredFile(inferred *inferredType, fileInfo *AstFileInfo) *ast.File {
+	if inferred.file != nil {
+		return inferred.file
+	}
+
+	return fileInfo.File
+}
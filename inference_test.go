@@ -0,0 +1,132 @@
+package swag
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_InferSchemas(t *testing.T) {
+	t.Parallel()
+
+	p := New(SetInferSchemas(true))
+	require.NoError(t, p.ParseAPI("testdata/infer_schemas", mainAPIFile, defaultParseDepth))
+
+	createUser := p.swagger.Paths.Paths["/users"].Post
+	require.Len(t, createUser.Parameters, 1)
+	assert.Equal(t, "body", createUser.Parameters[0].In)
+	assert.Equal(t, "#/definitions/api.CreateUserRequest", createUser.Parameters[0].Schema.Ref.String())
+	assert.Equal(t, "#/definitions/api.UserDetail", createUser.Responses.StatusCodeResponses[201].Schema.Ref.String())
+	assert.Equal(t, "#/definitions/api.ErrorResponse", createUser.Responses.StatusCodeResponses[400].Schema.Ref.String())
+
+	listUsers := p.swagger.Paths.Paths["/users"].Get
+	assert.Empty(t, listUsers.Parameters)
+	assert.Equal(t, spec.StringOrArray{ARRAY}, listUsers.Responses.StatusCodeResponses[200].Schema.Type)
+	assert.Equal(t, "#/definitions/api.User", listUsers.Responses.StatusCodeResponses[200].Schema.Items.Schema.Ref.String())
+
+	deleteUser := p.swagger.Paths.Paths["/users/{id}"].Delete
+	assert.Len(t, deleteUser.Parameters, 1)
+	assert.Equal(t, "No Content", deleteUser.Responses.StatusCodeResponses[204].Description)
+	assert.Nil(t, deleteUser.Responses.StatusCodeResponses[204].Schema)
+	assert.Equal(t, spec.StringOrArray{OBJECT}, deleteUser.Responses.StatusCodeResponses[404].Schema.Type)
+
+	// web.Context has methods named as the ones of gin, which aren't gin's
+	getUser := p.swagger.Paths.Paths["/users/{id}"].Get
+	assert.Len(t, getUser.Parameters, 1)
+	assert.Empty(t, getUser.Responses.StatusCodeResponses)
+
+	postStats := p.swagger.Paths.Paths["/stats"].Post
+	require.Len(t, postStats.Parameters, 1)
+	assert.Equal(t, "stats", postStats.Parameters[0].Name)
+	assert.Equal(t, "#/definitions/api.ErrorResponse", postStats.Responses.StatusCodeResponses[400].Schema.Ref.String())
+	assert.Equal(t, "Accepted", postStats.Responses.StatusCodeResponses[202].Description)
+	assert.Equal(t, "#/definitions/api.Stats",
+		postStats.Responses.StatusCodeResponses[202].Schema.AdditionalProperties.Schema.Ref.String())
+}
+
+func TestParser_InferSchemasSearchDirs(t *testing.T) {
+	t.Parallel()
+
+	p := New(SetInferSchemas(true))
+	require.NoError(t, p.ParseAPIMultiSearchDir([]string{"testdata/infer_schemas", "testdata/infer_schemas_orders"}, mainAPIFile, defaultParseDepth))
+
+	assert.Equal(t, "#/definitions/api.CreateUserRequest", p.swagger.Paths.Paths["/users"].Post.Parameters[0].Schema.Ref.String())
+	assert.Equal(t, "#/definitions/orders.Order", p.swagger.Paths.Paths["/orders"].Get.Responses.StatusCodeResponses[200].Schema.Ref.String())
+}
+
+func TestParser_InferSchemasDisabled(t *testing.T) {
+	t.Parallel()
+
+	p := New()
+	require.NoError(t, p.ParseAPI("testdata/infer_schemas", mainAPIFile, defaultParseDepth))
+
+	createUser := p.swagger.Paths.Paths["/users"].Post
+	assert.Empty(t, createUser.Parameters)
+	assert.Len(t, createUser.Responses.StatusCodeResponses, 1)
+	assert.Empty(t, p.swagger.Paths.Paths["/users"].Get.Responses.StatusCodeResponses)
+}
+
+func TestReceiverTypeName(t *testing.T) {
+	t.Parallel()
+
+	file, err := goparser.ParseFile(token.NewFileSet(), "", `package api
+
+func Handler() {}
+
+func (h *UserHandler) Handler() {}
+
+func (h Handlers[T]) Handler() {}
+
+func (h *Pair[K, V]) Handler() {}
+`, 0)
+	require.NoError(t, err)
+
+	var names []string
+	for _, decl := range file.Decls {
+		names = append(names, receiverTypeName(decl.(*ast.FuncDecl)))
+	}
+
+	assert.Equal(t, []string{"", "UserHandler", "Handlers", "Pair"}, names)
+}
+
+func TestParser_InferredTypeOf(t *testing.T) {
+	t.Parallel()
+
+	p := New()
+
+	tests := []struct {
+		name     string
+		typ      types.Type
+		expected string
+	}{
+		{name: "untyped constant", typ: types.Typ[types.UntypedInt], expected: "int"},
+		{name: "pointer", typ: types.NewPointer(types.Typ[types.String]), expected: "string"},
+		{name: "slice", typ: types.NewSlice(types.Typ[types.Bool]), expected: "[]bool"},
+		{name: "map of interfaces", typ: types.NewMap(types.Typ[types.String], types.NewInterfaceType(nil, nil)), expected: "map[string]interface{}"},
+		{name: "map of slices", typ: types.NewMap(types.Typ[types.String], types.NewSlice(types.Typ[types.Int])), expected: "map[string][]int"},
+		{name: "map with int keys", typ: types.NewMap(types.Typ[types.Int], types.Typ[types.String])},
+		{name: "channel", typ: types.NewChan(types.SendRecv, types.Typ[types.Int])},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			inferred := p.inferredTypeOf(test.typ)
+			if test.expected == "" {
+				assert.Nil(t, inferred)
+				return
+			}
+
+			require.NotNil(t, inferred)
+			assert.Equal(t, test.expected, inferred.name)
+		})
+	}
+}
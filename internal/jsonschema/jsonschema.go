@@ -0,0 +1,91 @@
+// Package jsonschema translates the schemas of the Swagger 2.0 document into the JSON Schema dialects of the AsyncAPI
+// and OpenAPI 3.1 documents.
+package jsonschema
+
+// Convert translates a decoded Swagger 2.0 schema in place into a JSON Schema and walks into its
+// sub-schemas, for the AsyncAPI schemas (draft 7) as well as the OpenAPI 3.1 schemas (draft 2020-12). References are
+// converted by ref, exclusive bounds hold the bound itself and nullable schemas allow null. convert, when set,
+// applies the translations specific to a document to every schema. Keywords holding arbitrary values (enum,
+// example, default...) are never walked, so their content is kept as is.
+func Convert(schema map[string]interface{}, ref func(string) string, convert func(map[string]interface{})) {
+	if schemaRef, ok := schema["$ref"].(string); ok {
+		schema["$ref"] = ref(schemaRef)
+	}
+
+	// Swagger 2.0 exclusive bounds are flags on maximum/minimum, JSON Schema holds the bound itself.
+	convertExclusiveBound(schema, "exclusiveMaximum", "maximum")
+	convertExclusiveBound(schema, "exclusiveMinimum", "minimum")
+
+	if convert != nil {
+		convert(schema)
+	}
+
+	if nullable, ok := schema["nullable"].(bool); ok {
+		delete(schema, "nullable")
+
+		if nullable {
+			convertNullable(schema)
+		}
+	}
+
+	for _, keyword := range []string{"properties", "patternProperties", "definitions"} {
+		if namedSchemas, ok := schema[keyword].(map[string]interface{}); ok {
+			for _, namedSchema := range namedSchemas {
+				convertJSONSubSchema(namedSchema, ref, convert)
+			}
+		}
+	}
+
+	for _, keyword := range []string{"items", "additionalItems", "additionalProperties", "not", "allOf", "anyOf", "oneOf"} {
+		convertJSONSubSchema(schema[keyword], ref, convert)
+	}
+}
+
+// convertJSONSubSchema converts a sub-schema, which may be a schema, a list of schemas or a boolean.
+func convertJSONSubSchema(value interface{}, ref func(string) string, convert func(map[string]interface{})) {
+	switch subSchema := value.(type) {
+	case map[string]interface{}:
+		Convert(subSchema, ref, convert)
+	case []interface{}:
+		for _, item := range subSchema {
+			convertJSONSubSchema(item, ref, convert)
+		}
+	}
+}
+
+func convertExclusiveBound(schema map[string]interface{}, exclusiveKeyword, boundKeyword string) {
+	exclusive, ok := schema[exclusiveKeyword].(bool)
+	if !ok {
+		return
+	}
+
+	delete(schema, exclusiveKeyword)
+
+	if bound, ok := schema[boundKeyword]; ok && exclusive {
+		schema[exclusiveKeyword] = bound
+		delete(schema, boundKeyword)
+	}
+}
+
+// convertNullable allows null values, with a type list for typed schemas and a oneOf for references.
+func convertNullable(schema map[string]interface{}) {
+	switch schemaType := schema["type"].(type) {
+	case string:
+		schema["type"] = []interface{}{schemaType, "null"}
+
+		if enum, ok := schema["enum"].([]interface{}); ok {
+			schema["enum"] = append(enum, nil)
+		}
+	case nil:
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return
+		}
+
+		delete(schema, "$ref")
+		schema["oneOf"] = []interface{}{
+			map[string]interface{}{"$ref": ref},
+			map[string]interface{}{"type": "null"},
+		}
+	}
+}
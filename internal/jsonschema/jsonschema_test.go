@@ -0,0 +1,47 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"count": {"type": "integer", "maximum": 10, "exclusiveMaximum": true, "minimum": 0, "exclusiveMinimum": false},
+			"kind": {"type": "string", "enum": ["a", "b"], "nullable": true},
+			"owner": {"$ref": "#/definitions/model.User", "nullable": true},
+			"tags": {"type": "array", "items": {"$ref": "#/definitions/model.Tag"}, "xml": {"wrapped": true}}
+		},
+		"allOf": [{"$ref": "#/definitions/model.Base"}],
+		"example": {"$ref": "#/definitions/model.Kept"}
+	}`), &schema))
+
+	Convert(schema, func(ref string) string {
+		return strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+	}, func(schema map[string]interface{}) {
+		delete(schema, "xml")
+	})
+
+	out, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"count": {"type": "integer", "exclusiveMaximum": 10, "minimum": 0},
+			"kind": {"type": ["string", "null"], "enum": ["a", "b", null]},
+			"owner": {"oneOf": [{"$ref": "#/components/schemas/model.User"}, {"type": "null"}]},
+			"tags": {"type": "array", "items": {"$ref": "#/components/schemas/model.Tag"}}
+		},
+		"allOf": [{"$ref": "#/components/schemas/model.Base"}],
+		"example": {"$ref": "#/definitions/model.Kept"}
+	}`, string(out))
+}
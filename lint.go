@@ -0,0 +1,333 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// Rule IDs of the problems found in the annotations, see reportRule.
+const (
+	// RuleInvalidAnnotation is reported for an annotation which can't be parsed, the rest of its block is skipped.
+	RuleInvalidAnnotation = "invalid-annotation"
+
+	// RuleUnknownAttribute is reported for an attribute which isn't supported in an operation or AsyncAPI block.
+	RuleUnknownAttribute = "unknown-attribute"
+
+	// RulePathParamNotInRoute is reported for a path parameter which isn't a placeholder of the routes.
+	RulePathParamNotInRoute = "path-param-not-in-route"
+
+	// RulePathPlaceholderWithoutParam is reported for a placeholder of a route without path parameter.
+	RulePathPlaceholderWithoutParam = "path-placeholder-without-param"
+
+	// RuleDuplicateRoute is reported for a route which is already declared by another operation.
+	RuleDuplicateRoute = "duplicate-route"
+
+	// RuleRouteMismatch is reported for a @Router which doesn't match the routes registered with the handler, when
+	// inferring routes.
+	RuleRouteMismatch = "route-mismatch"
+
+	// RuleUndeclaredChannelServer is reported for an AsyncAPI channel using a server which isn't declared.
+	RuleUndeclaredChannelServer = "undeclared-channel-server"
+
+	// RuleDuplicateOperationID is reported for an operation ID which is already used by another operation.
+	RuleDuplicateOperationID = "duplicate-operation-id"
+
+	// RuleMissingResponse is reported for an operation without any response.
+	RuleMissingResponse = "missing-response"
+)
+
+// Severities of the diagnostics.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// LintRule describes a rule checked in lint mode.
+type LintRule struct {
+	ID          string
+	Severity    string
+	Description string
+}
+
+// LintRules lists the rules checked in lint mode.
+var LintRules = []LintRule{
+	{ID: RuleInvalidAnnotation, Severity: SeverityError, Description: "The annotation can't be parsed."},
+	{ID: RuleUnknownAttribute, Severity: SeverityWarning, Description: "The attribute isn't supported and is ignored."},
+	{ID: RulePathParamNotInRoute, Severity: SeverityError, Description: "The path parameter isn't a placeholder of the @Router path."},
+	{ID: RulePathPlaceholderWithoutParam, Severity: SeverityError, Description: "The @Router path has a placeholder without path parameter."},
+	{ID: RuleDuplicateRoute, Severity: SeverityError, Description: "The route is already declared by another operation."},
+	{ID: RuleRouteMismatch, Severity: SeverityError, Description: "The @Router doesn't match the routes registered with the handler."},
+	{ID: RuleUndeclaredChannelServer, Severity: SeverityError, Description: "The channel uses a server which isn't declared with @server."},
+	{ID: RuleDuplicateOperationID, Severity: SeverityError, Description: "The operation ID is already used by another operation."},
+	{ID: RuleMissingResponse, Severity: SeverityWarning, Description: "The operation doesn't declare any response."},
+}
+
+// Diagnostic is a problem found in the annotations in lint mode.
+type Diagnostic struct {
+	Rule     string
+	Severity string
+	Position token.Position
+	Message  string
+}
+
+// String returns the diagnostic as file:line:column: message.
+func (diagnostic Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", diagnostic.Position, diagnostic.Severity, diagnostic.Message, diagnostic.Rule)
+}
+
+// channelServers are the servers of a parsed AsyncAPI channel, checked once every server is known.
+type channelServers struct {
+	name     string
+	servers  []string
+	position token.Position
+}
+
+// fatalRules fail the generation even without strict mode, since the document would be invalid.
+var fatalRules = map[string]bool{
+	RuleInvalidAnnotation:    true,
+	RuleDuplicateOperationID: true,
+}
+
+// SetLint sets whether the parser collects diagnostics about the annotations, see Diagnostics, instead of failing
+// on the first invalid annotation.
+func SetLint(lint bool) func(*Parser) {
+	return func(p *Parser) {
+		p.lint = lint
+	}
+}
+
+// Diagnostics returns the diagnostics found in lint mode, sorted by position.
+func (parser *Parser) Diagnostics() []Diagnostic {
+	sort.SliceStable(parser.diagnostics, func(i, j int) bool {
+		left, right := parser.diagnostics[i].Position, parser.diagnostics[j].Position
+		if left.Filename != right.Filename {
+			return left.Filename < right.Filename
+		}
+
+		if left.Line != right.Line {
+			return left.Line < right.Line
+		}
+
+		return left.Column < right.Column
+	})
+
+	return parser.diagnostics
+}
+
+func (parser *Parser) addDiagnostic(rule string, position token.Position, format string, args ...interface{}) {
+	parser.diagnostics = append(parser.diagnostics, Diagnostic{
+		Rule:     rule,
+		Severity: ruleSeverity(rule),
+		Position: position,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func ruleSeverity(rule string) string {
+	for _, lintRule := range LintRules {
+		if lintRule.ID == rule {
+			return lintRule.Severity
+		}
+	}
+
+	return SeverityError
+}
+
+// reportRule reports a problem found by a rule, the same way for every rule: as a diagnostic in lint mode, as an
+// error for the fatal rules and, in strict mode, for the rules of error severity, and as a warning otherwise.
+func (parser *Parser) reportRule(rule string, position token.Position, format string, args ...interface{}) error {
+	if parser.lint {
+		parser.addDiagnostic(rule, position, format, args...)
+
+		return nil
+	}
+
+	err := &ParseError{Pos: position, Err: fmt.Errorf(format, args...)}
+
+	if fatalRules[rule] || parser.Strict && ruleSeverity(rule) == SeverityError {
+		return parser.reportError(err)
+	}
+
+	parser.debug.Printf("warning: %s\n", err)
+
+	return nil
+}
+
+// commentPosition returns the position of the attribute of a comment, after the comment marker.
+func commentPosition(fileInfo *AstFileInfo, comment *ast.Comment) token.Position {
+	if fileInfo.FileSet == nil {
+		return token.Position{Filename: fileInfo.Path}
+	}
+
+	position := fileInfo.FileSet.Position(comment.Pos())
+
+	offset := len(comment.Text) - len(strings.TrimLeft(strings.TrimLeft(comment.Text, "/"), " \t"))
+	position.Offset += offset
+	position.Column += offset
+
+	return position
+}
+
+// commentAttribute returns the attribute of a comment, lower cased, and the rest of the comment. The attribute
+// is empty when the comment doesn't start with one.
+func commentAttribute(comment string) (string, string) {
+	fields := FieldsByAnySpace(strings.TrimSpace(strings.TrimLeft(comment, "/")), 2)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "@") {
+		return "", ""
+	}
+
+	var lineRemainder string
+	if len(fields) > 1 {
+		lineRemainder = fields[1]
+	}
+
+	return strings.ToLower(fields[0]), lineRemainder
+}
+
+func isOperationAttribute(attribute string) bool {
+	switch attribute {
+	case stateAttr, descriptionAttr, descriptionMarkdownAttr, summaryAttr, idAttr, tagsAttr, acceptAttr, produceAttr,
+		paramAttr, successAttr, failureAttr, responseAttr, headerAttr, routerAttr, deprecatedRouterAttr, securityAttr,
+		deprecatedAttr, xCodeSamplesAttr:
+		return true
+	}
+
+	return strings.HasPrefix(attribute, "@x-")
+}
+
+func isAsyncAPIAttribute(attribute string) bool {
+	_, exists := AttributeHandler[Attribute(attribute)]
+
+	return exists || strings.HasPrefix(attribute, bindingAttrPrefix)
+}
+
+// attributePositions locates the attributes of an operation block, to report the problems found in the operation.
+// The fields are exported to be stored in the parse cache.
+type attributePositions struct {
+	// Block is the position of the first attribute of the block
+	Block   token.Position            `json:"block"`
+	Routers []token.Position          `json:"routers,omitempty"`
+	Params  map[string]token.Position `json:"params,omitempty"`
+	ID      token.Position            `json:"id"`
+	// Unknown holds the positions of the attributes which aren't operation attributes, by attribute
+	Unknown []attributePosition `json:"unknown,omitempty"`
+}
+
+type attributePosition struct {
+	Attribute string         `json:"attribute"`
+	Position  token.Position `json:"position"`
+}
+
+// record records the position of the attribute of a comment.
+func (positions *attributePositions) record(comment string, position token.Position) {
+	attribute, lineRemainder := commentAttribute(comment)
+	if attribute == "" {
+		return
+	}
+
+	if positions.Block.Filename == "" {
+		positions.Block = position
+	}
+
+	switch {
+	case attribute == paramAttr:
+		if fields := strings.Fields(lineRemainder); len(fields) > 0 {
+			if positions.Params == nil {
+				positions.Params = make(map[string]token.Position)
+			}
+
+			positions.Params[fields[0]] = position
+		}
+	case attribute == idAttr:
+		positions.ID = position
+	case attribute == routerAttr || attribute == deprecatedRouterAttr:
+		positions.Routers = append(positions.Routers, position)
+	case !isOperationAttribute(attribute):
+		positions.Unknown = append(positions.Unknown, attributePosition{Attribute: attribute, Position: position})
+	}
+}
+
+// router returns the position of the @Router declaring the i-th route of the operation, every @Router comment
+// declares one route, or the one of the block for the routes which are registered without @Router.
+func (positions *attributePositions) router(i int) token.Position {
+	if i < len(positions.Routers) {
+		return positions.Routers[i]
+	}
+
+	return positions.Block
+}
+
+// param returns the position of the @Param declaring a parameter, or the one of the first @Router.
+func (positions *attributePositions) param(name string) token.Position {
+	if position, ok := positions.Params[name]; ok {
+		return position
+	}
+
+	return positions.router(0)
+}
+
+// operationID returns the position of the @ID of the operation, or the one of the first @Router.
+func (positions *attributePositions) operationID() token.Position {
+	if positions.ID.Filename != "" {
+		return positions.ID
+	}
+
+	return positions.router(0)
+}
+
+// checkOperationAttributes reports the attributes of an operation block which aren't supported. Blocks without
+// @Router aren't operations and aren't checked.
+func (parser *Parser) checkOperationAttributes(operation *Operation) error {
+	if len(operation.RouterProperties) == 0 {
+		return nil
+	}
+
+	for _, unknown := range operation.positions.Unknown {
+		if err := parser.reportRule(RuleUnknownAttribute, unknown.Position, "unknown attribute '%s'", unknown.Attribute); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordChannelServers records the servers of the channels of a parsed AsyncAPI block, which are checked by
+// checkChannelServers once every block is parsed.
+func (parser *Parser) recordChannelServers(asyncScope *AsyncScope) {
+	names := make([]string, 0, len(asyncScope.channels))
+	for name := range asyncScope.channels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		parser.channelServers = append(parser.channelServers, channelServers{
+			name:     name,
+			servers:  asyncScope.channels[name].Servers,
+			position: asyncScope.channelPositions[name],
+		})
+	}
+}
+
+// checkChannelServers checks that the servers used by the channels are declared.
+func (parser *Parser) checkChannelServers() error {
+	for _, channel := range parser.channelServers {
+		for _, server := range channel.servers {
+			if _, ok := parser.asyncAPI.Servers[server]; ok {
+				continue
+			}
+
+			err := parser.reportRule(RuleUndeclaredChannelServer, channel.position,
+				"channel '%s' uses the undeclared server '%s'", channel.name, server)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package swag
+
+import (
+	"errors"
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_Lint(t *testing.T) {
+	p := New(SetLint(true))
+	require.NoError(t, p.ParseAPI("testdata/lint", mainAPIFile, defaultParseDepth))
+
+	type expectedDiagnostic struct {
+		rule     string
+		severity string
+		line     int
+		column   int
+		message  string
+	}
+
+	var diagnostics []expectedDiagnostic
+	for _, diagnostic := range p.Diagnostics() {
+		assert.Equal(t, "testdata/lint/api/api.go", diagnostic.Position.Filename)
+		diagnostics = append(diagnostics, expectedDiagnostic{
+			rule:     diagnostic.Rule,
+			severity: diagnostic.Severity,
+			line:     diagnostic.Position.Line,
+			column:   diagnostic.Position.Column,
+			message:  diagnostic.Message,
+		})
+	}
+
+	assert.Equal(t, []expectedDiagnostic{
+		{RulePathParamNotInRoute, SeverityError, 16, 4, "path parameter 'petId' isn't a placeholder of GET /pets/{id}"},
+		{RuleDuplicateOperationID, SeverityError, 24, 4, "operation ID 'getPet' is already used at testdata/lint/api/api.go:14:4"},
+		{RuleUnknownAttribute, SeverityWarning, 26, 4, "unknown attribute '@summry'"},
+		{RuleMissingResponse, SeverityWarning, 27, 4, "operation doesn't declare any response"},
+		{RuleUndeclaredChannelServer, SeverityError, 34, 4, "channel 'audits' uses the undeclared server 'auditBroker'"},
+		{RuleInvalidAnnotation, SeverityError, 45, 4, "missing required param comment parameters \"id path\""},
+		{RuleUnknownAttribute, SeverityWarning, 53, 4, "unknown attribute '@message.tittle'"},
+		{RulePathPlaceholderWithoutParam, SeverityError, 60, 4, "placeholder 'petId' of route GET /pets/{petId}/photos has no path parameter"},
+		{RulePathPlaceholderWithoutParam, SeverityError, 61, 4, "placeholder 'petID' of route GET /pets/{petID} has no path parameter"},
+		{RuleDuplicateRoute, SeverityError, 61, 4, "route GET /pets/{petID} is already declared as GET /pets/{id} at testdata/lint/api/api.go:18:4"},
+	}, diagnostics)
+}
+
+func TestParser_LintStrict(t *testing.T) {
+	lint := New(SetLint(true))
+	require.NoError(t, lint.ParseAPI("testdata/lint", mainAPIFile, defaultParseDepth))
+
+	var expected []string
+	for _, diagnostic := range lint.Diagnostics() {
+		if diagnostic.Severity == SeverityError {
+			expected = append(expected, diagnostic.Position.String()+": "+diagnostic.Message)
+		}
+	}
+
+	err := New(SetStrict(true), SetCollectErrors(true)).ParseAPI("testdata/lint", mainAPIFile, defaultParseDepth)
+
+	var parseErrs ParseErrors
+	require.True(t, errors.As(err, &parseErrs))
+
+	var actual []string
+	for _, parseErr := range parseErrs {
+		actual = append(actual, parseErr.Error())
+	}
+
+	// strict mode fails on the rules of error severity found in lint mode
+	assert.ElementsMatch(t, expected, actual)
+}
+
+func TestParser_LintDisabled(t *testing.T) {
+	p := New()
+	assert.Error(t, p.ParseAPI("testdata/lint", mainAPIFile, defaultParseDepth))
+	assert.Empty(t, p.Diagnostics())
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	diagnostic := Diagnostic{
+		Rule:     RuleMissingResponse,
+		Severity: SeverityWarning,
+		Position: token.Position{Filename: "api.go", Line: 12, Column: 4},
+		Message:  "operation doesn't declare any response",
+	}
+
+	assert.Equal(t, "api.go:12:4: warning: operation doesn't declare any response (missing-response)", diagnostic.String())
+}
+
+func TestCommentPosition(t *testing.T) {
+	fileSet := token.NewFileSet()
+	file := fileSet.AddFile("api.go", -1, 100)
+	file.SetLines([]int{0, 20})
+
+	fileInfo := &AstFileInfo{FileSet: fileSet, Path: "api.go"}
+
+	assert.Equal(t, token.Position{Filename: "api.go", Offset: 25, Line: 2, Column: 6},
+		commentPosition(fileInfo, &ast.Comment{Slash: file.Pos(20), Text: "//   @Success 200"}))
+	assert.Equal(t, token.Position{Filename: "api.go"},
+		commentPosition(&AstFileInfo{Path: "api.go"}, &ast.Comment{Text: "// @Success 200"}))
+}
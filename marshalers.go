@@ -0,0 +1,117 @@
+package swag
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// marshalerMethods are the methods of encoding.TextMarshaler and json.Marshaler, a type implementing one of them is
+// documented as a string instead of its fields.
+var marshalerMethods = map[string]struct{}{
+	"MarshalText": {},
+	"MarshalJSON": {},
+}
+
+// formatAttr sets the format of the string documenting a marshaler type, like `// @format date`.
+const formatAttr = "@format"
+
+// marshaler is a type implementing encoding.TextMarshaler or json.Marshaler.
+type marshaler struct {
+	// format of the string, from the @format attribute of the type
+	format string
+
+	// file declaring the marshaling method
+	file *ast.File
+}
+
+// collectMarshalers marks the types whose value or pointer method set implements encoding.TextMarshaler or
+// json.Marshaler. The enum types keep their values.
+func (pkgDefs *PackagesDefinitions) collectMarshalers(parsedSchemas map[*TypeSpecDef]*Schema) {
+	sortedFiles := make([]*AstFileInfo, 0, len(pkgDefs.files))
+	for _, info := range pkgDefs.files {
+		sortedFiles = append(sortedFiles, info)
+	}
+	sort.Slice(sortedFiles, func(i, j int) bool {
+		return sortedFiles[i].Path < sortedFiles[j].Path
+	})
+
+	for _, info := range sortedFiles {
+		pkg, ok := pkgDefs.packages[info.PackagePath]
+		if !ok {
+			continue
+		}
+		for _, decl := range info.File.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || !isMarshalerMethod(funcDecl) {
+				continue
+			}
+			typeDef, ok := pkg.TypeDefinitions[receiverTypeName(funcDecl)]
+			if !ok || typeDef.marshaler != nil || len(typeDef.Enums) > 0 {
+				continue
+			}
+
+			// delete it from parsed schemas, and will parse it again
+			delete(parsedSchemas, typeDef)
+			typeDef.marshaler = &marshaler{format: typeFormat(typeDef), file: info.File}
+		}
+	}
+}
+
+// isMarshalerMethod reports whether a method is `MarshalText() ([]byte, error)` or `MarshalJSON() ([]byte, error)`.
+func isMarshalerMethod(funcDecl *ast.FuncDecl) bool {
+	if _, ok := marshalerMethods[funcDecl.Name.Name]; !ok || funcDecl.Recv == nil {
+		return false
+	}
+
+	if funcDecl.Type.Params != nil && len(funcDecl.Type.Params.List) > 0 {
+		return false
+	}
+
+	var results []ast.Expr
+
+	if funcDecl.Type.Results != nil {
+		for _, field := range funcDecl.Type.Results.List {
+			results = append(results, field.Type)
+			for i := 1; i < len(field.Names); i++ {
+				results = append(results, field.Type)
+			}
+		}
+	}
+
+	if len(results) != 2 {
+		return false
+	}
+
+	bytes, ok := results[0].(*ast.ArrayType)
+	if !ok || bytes.Len != nil {
+		return false
+	}
+
+	elt, ok := bytes.Elt.(*ast.Ident)
+	if !ok || elt.Name != "byte" {
+		return false
+	}
+
+	errType, ok := results[1].(*ast.Ident)
+
+	return ok && errType.Name == ERROR
+}
+
+// typeFormat returns the value of the @format attribute of a type.
+func typeFormat(typeDef *TypeSpecDef) string {
+	for _, commentGroup := range typeSpecDocs(typeDef) {
+		if commentGroup == nil {
+			continue
+		}
+
+		for _, comment := range commentGroup.List {
+			fields := FieldsByAnySpace(strings.TrimSpace(strings.TrimLeft(comment.Text, "/")), 2)
+			if len(fields) == 2 && strings.ToLower(fields[0]) == formatAttr {
+				return strings.TrimSpace(fields[1])
+			}
+		}
+	}
+
+	return ""
+}
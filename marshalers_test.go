@@ -0,0 +1,98 @@
+package swag
+
+import (
+	"encoding/json"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarshalers(t *testing.T) {
+	searchDir := "testdata/marshalers"
+	expected, err := os.ReadFile(filepath.Join(searchDir, "expected.json"))
+	require.NoError(t, err)
+
+	p := New()
+	p.RequiredByDefault = true
+	require.NoError(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+
+	b, err := json.MarshalIndent(p.swagger, "", "    ")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(b))
+}
+
+func TestIsMarshalerMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		want   bool
+	}{
+		{"text marshaler", `func (d Date) MarshalText() ([]byte, error) { return nil, nil }`, true},
+		{"json marshaler", `func (d *Date) MarshalJSON() ([]byte, error) { return nil, nil }`, true},
+		{"named results", `func (d Date) MarshalText() (text []byte, err error) { return }`, true},
+		{"function", `func MarshalText() ([]byte, error) { return nil, nil }`, false},
+		{"other method", `func (d Date) MarshalYAML() ([]byte, error) { return nil, nil }`, false},
+		{"parameters", `func (d Date) MarshalText(indent bool) ([]byte, error) { return nil, nil }`, false},
+		{"no error", `func (d Date) MarshalText() []byte { return nil }`, false},
+		{"string", `func (d Date) MarshalText() (string, error) { return "", nil }`, false},
+		{"array", `func (d Date) MarshalText() ([8]byte, error) { return [8]byte{}, nil }`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := goparser.ParseFile(token.NewFileSet(), "", "package types\n"+tt.method, 0)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, isMarshalerMethod(file.Decls[0].(*ast.FuncDecl)))
+		})
+	}
+}
+
+func TestParseMarshalers_Enum(t *testing.T) {
+	src := `
+package api
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+)
+
+func (l Level) MarshalText() ([]byte, error) {
+	return nil, nil
+}
+
+// @format duration
+type Timeout int64
+
+func (t *Timeout) MarshalJSON() ([]byte, error) {
+	return nil, nil
+}
+`
+	p := New()
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+	parsedSchemas, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+	p.parsedSchemas = parsedSchemas
+
+	level := p.packages.FindTypeSpec("api.Level", nil)
+	require.NotNil(t, level)
+	assert.Nil(t, level.marshaler)
+
+	timeout := p.packages.FindTypeSpec("api.Timeout", nil)
+	require.NotNil(t, timeout)
+	require.NotNil(t, timeout.marshaler)
+
+	schema, err := p.ParseDefinition(timeout, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{STRING}, []string(schema.Type))
+	assert.Equal(t, "duration", schema.Format)
+}
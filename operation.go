@@ -32,6 +32,9 @@ type Operation struct {
 	spec.Operation
 	RouterProperties []RouteProperties
 	State            string
+
+	// positions locates the attributes parsed by parseCommentAt
+	positions attributePositions
 }
 
 var mimeTypeAliases = map[string]string{
@@ -165,6 +168,18 @@ func (operation *Operation) ParseComment(comment string, astFile *ast.File) erro
 	return nil
 }
 
+// parseCommentAt parses a comment like ParseComment, the position of its attribute is recorded to report the
+// problems found in the operation.
+func (operation *Operation) parseCommentAt(comment string, astFile *ast.File, position token.Position) error {
+	if err := operation.ParseComment(comment, astFile); err != nil {
+		return err
+	}
+
+	operation.positions.record(comment, position)
+
+	return nil
+}
+
 // ParseCodeSample parse code sample.
 func (operation *Operation) ParseCodeSample(attribute, _, lineRemainder string) error {
 	if lineRemainder == "file" {
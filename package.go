@@ -180,7 +180,8 @@ func (pkg *PackageDefinitions) evaluateConstValue(file *ast.File, iota int, expr
 			if typeDef == nil {
 				return nil, nil
 			}
-			return arg, typeDef.TypeSpec.Type
+			value, _ := pkg.evaluateConstValue(file, iota, arg, globalEvaluator, recursiveStack)
+			return value, valueExpr.Fun
 		}
 	}
 	return nil, nil
@@ -34,13 +34,22 @@ func NewPackagesDefinitions() *PackagesDefinitions {
 
 // ParseFile parse a source file.
 func (pkgDefs *PackagesDefinitions) ParseFile(packageDir, path string, src interface{}, flag ParseFlag) error {
+	fileSet, astFile, err := parseGoFile(path, src)
+	if err != nil {
+		return err
+	}
+	return pkgDefs.collectAstFile(fileSet, packageDir, path, astFile, flag)
+}
+
+// parseGoFile parses a source file with its own FileSet, so that files can be parsed concurrently.
+func parseGoFile(path string, src interface{}) (*token.FileSet, *ast.File, error) {
 	// positions are relative to FileSet
 	fileSet := token.NewFileSet()
 	astFile, err := goparser.ParseFile(fileSet, path, src, goparser.ParseComments)
 	if err != nil {
-		return fmt.Errorf("failed to parse file %s, error:%+v", path, err)
+		return nil, nil, fmt.Errorf("failed to parse file %s, error:%+v", path, err)
 	}
-	return pkgDefs.collectAstFile(fileSet, packageDir, path, astFile, flag)
+	return fileSet, astFile, nil
 }
 
 // collectAstFile collect ast.file.
@@ -117,13 +126,28 @@ func (pkgDefs *PackagesDefinitions) RangeFiles(handle func(info *AstFileInfo) er
 // @Return parsed definitions.
 func (pkgDefs *PackagesDefinitions) ParseTypes() (map[*TypeSpecDef]*Schema, error) {
 	parsedSchemas := make(map[*TypeSpecDef]*Schema)
-	for astFile, info := range pkgDefs.files {
-		pkgDefs.parseTypesFromFile(astFile, info.PackagePath, parsedSchemas)
-		pkgDefs.parseFunctionScopedTypesFromFile(astFile, info.PackagePath, parsedSchemas)
+
+	// in the order of the paths, so that the first of the types declared twice in a package is always the same
+	sortedFiles := make([]*AstFileInfo, 0, len(pkgDefs.files))
+	for _, info := range pkgDefs.files {
+		sortedFiles = append(sortedFiles, info)
+	}
+
+	sort.Slice(sortedFiles, func(i, j int) bool {
+		return sortedFiles[i].Path < sortedFiles[j].Path
+	})
+
+	for _, info := range sortedFiles {
+		pkgDefs.parseTypesFromFile(info.File, info.PackagePath, parsedSchemas)
+		pkgDefs.parseFunctionScopedTypesFromFile(info.File, info.PackagePath, parsedSchemas)
 	}
 	pkgDefs.removeAllNotUniqueTypes()
 	pkgDefs.evaluateAllConstVariables()
 	pkgDefs.collectConstEnums(parsedSchemas)
+	pkgDefs.collectMarshalers(parsedSchemas)
+	if err := pkgDefs.collectPolymorphisms(); err != nil {
+		return nil, err
+	}
 	return parsedSchemas, nil
 }
 
@@ -367,49 +391,99 @@ func (pkgDefs *PackagesDefinitions) EvaluateConstValueByName(file *ast.File, pkg
 	return nil, nil
 }
 
+// collectConstEnums collects the typed consts as the enum values of their type, the consts of the package of the
+// type first, then the consts of the other packages in the order of their paths.
 func (pkgDefs *PackagesDefinitions) collectConstEnums(parsedSchemas map[*TypeSpecDef]*Schema) {
-	for _, pkg := range pkgDefs.packages {
-		for _, constVar := range pkg.OrderedConst {
-			if constVar.Type == nil {
-				continue
-			}
-			ident, ok := constVar.Type.(*ast.Ident)
-			if !ok || IsGolangPrimitiveType(ident.Name) {
-				continue
-			}
-			typeDef, ok := pkg.TypeDefinitions[ident.Name]
-			if !ok {
-				continue
-			}
+	pkgPaths := make([]string, 0, len(pkgDefs.packages))
+	for pkgPath := range pkgDefs.packages {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+
+	for _, ownPackage := range []bool{true, false} {
+		for _, pkgPath := range pkgPaths {
+			pkg := pkgDefs.packages[pkgPath]
+			for _, constVar := range pkg.OrderedConst {
+				if constVar.Type == nil {
+					continue
+				}
+				typeDef := pkgDefs.findConstTypeDef(pkg, constVar)
+				if typeDef == nil || (typeDef.PkgPath == pkg.Path) != ownPackage {
+					continue
+				}
 
-			// delete it from parsed schemas, and will parse it again
-			if _, ok = parsedSchemas[typeDef]; ok {
-				delete(parsedSchemas, typeDef)
-			}
+				// delete it from parsed schemas, and will parse it again
+				if _, ok := parsedSchemas[typeDef]; ok {
+					delete(parsedSchemas, typeDef)
+				}
 
-			if typeDef.Enums == nil {
-				typeDef.Enums = make([]EnumValue, 0)
-			}
+				if typeDef.Enums == nil {
+					typeDef.Enums = make([]EnumValue, 0)
+				}
 
-			name := constVar.Name.Name
-			if _, ok = constVar.Value.(ast.Expr); ok {
-				continue
-			}
+				name := constVar.Name.Name
+				if name == "_" {
+					// a blank const only skips an iota value
+					continue
+				}
 
-			enumValue := EnumValue{
-				key:   name,
-				Value: constVar.Value,
+				value := constVar.Value
+				if _, ok := value.(ast.Expr); ok {
+					value, _ = pkgDefs.EvaluateConstValue(pkg, constVar, nil)
+				}
+				if _, ok := value.(ast.Expr); ok || value == nil {
+					continue
+				}
+
+				typeDef.Enums = append(typeDef.Enums, EnumValue{
+					key:     name,
+					Value:   value,
+					Comment: constComment(constVar),
+					file:    constVar.File,
+				})
 			}
-			if constVar.Comment != nil && len(constVar.Comment.List) > 0 {
-				enumValue.Comment = constVar.Comment.List[0].Text
-				enumValue.Comment = strings.TrimPrefix(enumValue.Comment, "//")
-				enumValue.Comment = strings.TrimPrefix(enumValue.Comment, "/*")
-				enumValue.Comment = strings.TrimSuffix(enumValue.Comment, "*/")
-				enumValue.Comment = strings.TrimSpace(enumValue.Comment)
+		}
+	}
+
+	pkgDefs.collectMethodEnums(parsedSchemas)
+}
+
+// findConstTypeDef returns the definition of the type of a const, declared in the package of the const or in an
+// imported package, nil if it's a primitive type or an unknown type.
+func (pkgDefs *PackagesDefinitions) findConstTypeDef(pkg *PackageDefinitions, constVar *ConstVariable) *TypeSpecDef {
+	switch constType := constVar.Type.(type) {
+	case *ast.Ident:
+		if IsGolangPrimitiveType(constType.Name) {
+			return nil
+		}
+		return pkg.TypeDefinitions[constType.Name]
+	case *ast.SelectorExpr:
+		pkgIdent, ok := constType.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		pkgPaths, _ := pkgDefs.findPackagePathFromImports(pkgIdent.Name, constVar.File)
+		for _, pkgPath := range pkgPaths {
+			if typePkg, ok := pkgDefs.packages[pkgPath]; ok {
+				if typeDef, ok := typePkg.TypeDefinitions[constType.Sel.Name]; ok {
+					return typeDef
+				}
 			}
-			typeDef.Enums = append(typeDef.Enums, enumValue)
 		}
 	}
+	return nil
+}
+
+// constComment returns the trailing comment of a const.
+func constComment(constVar *ConstVariable) string {
+	if constVar.Comment == nil || len(constVar.Comment.List) == 0 {
+		return ""
+	}
+	comment := constVar.Comment.List[0].Text
+	comment = strings.TrimPrefix(comment, "//")
+	comment = strings.TrimPrefix(comment, "/*")
+	comment = strings.TrimSuffix(comment, "*/")
+	return strings.TrimSpace(comment)
 }
 
 func (pkgDefs *PackagesDefinitions) removeAllNotUniqueTypes() {
@@ -15,9 +15,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/KyleBanks/depth"
 	"github.com/go-openapi/spec"
@@ -70,6 +73,24 @@ const (
 	scopeAttrPrefix         = "@scope."
 	stateAttr               = "@state"
 	asyncAPIAttr            = "@asyncapi"
+
+	asyncSecAttrPrefix            = "@asyncapi.securityschemes."
+	asyncSecUserPasswordAttr      = "@asyncapi.securityschemes.userpassword"
+	asyncSecAPIKeyAttr            = "@asyncapi.securityschemes.apikey"
+	asyncSecX509Attr              = "@asyncapi.securityschemes.x509"
+	asyncSecSymmetricAttr         = "@asyncapi.securityschemes.symmetricencryption"
+	asyncSecAsymmetricAttr        = "@asyncapi.securityschemes.asymmetricencryption"
+	asyncSecHTTPAttr              = "@asyncapi.securityschemes.http"
+	asyncSecHTTPAPIKeyAttr        = "@asyncapi.securityschemes.httpapikey"
+	asyncSecImplicitAttr          = "@asyncapi.securityschemes.oauth2.implicit"
+	asyncSecPasswordAttr          = "@asyncapi.securityschemes.oauth2.password"
+	asyncSecClientCredentialsAttr = "@asyncapi.securityschemes.oauth2.clientcredentials"
+	asyncSecAuthorizationCodeAttr = "@asyncapi.securityschemes.oauth2.authorizationcode"
+	asyncSecOpenIDConnectAttr     = "@asyncapi.securityschemes.openidconnect"
+	asyncSecPlainAttr             = "@asyncapi.securityschemes.plain"
+	asyncSecScramSha256Attr       = "@asyncapi.securityschemes.scramsha256"
+	asyncSecScramSha512Attr       = "@asyncapi.securityschemes.scramsha512"
+	asyncSecGSSAPIAttr            = "@asyncapi.securityschemes.gssapi"
 )
 
 // ParseFlag determine what to parse
@@ -118,6 +139,9 @@ type Parser struct {
 	// asyncAPI represents the root document object for AsyncAPI specification
 	asyncAPI *asyncSpec.AsyncAPI
 
+	// asyncOperations store AsyncAPI operations by operation ID together with their action and channel
+	asyncOperations map[string]*OperationWithChannel
+
 	// packages store entities of APIs, definitions, file, package path etc.  and their relations
 	packages *PackagesDefinitions
 
@@ -187,6 +211,67 @@ type Parser struct {
 
 	// ParseFuncBody whether swag should parse api info inside of funcs
 	ParseFuncBody bool
+
+	// lint whether the parser collects diagnostics instead of failing on the first invalid annotation
+	lint bool
+
+	// diagnostics found in lint mode
+	diagnostics []Diagnostic
+
+	// operationIDs holds the position of the first use of every operation ID
+	operationIDs map[string]token.Position
+
+	// channelServers holds the servers of the parsed AsyncAPI channels, checked once every server is known
+	channelServers []channelServers
+
+	// collectErrors whether the parser keeps going after an invalid annotation
+	collectErrors bool
+
+	// parseErrors collected when the parser keeps going after an invalid annotation
+	parseErrors []error
+
+	// routes holds every declared route by method and path without placeholder names
+	routes map[string]declaredRoute
+
+	// inferRoutes whether the routes registered with routers are bound to the handlers without @Router
+	inferRoutes bool
+
+	// registeredRoutes holds the routes registered with routers by handler, when inferring routes
+	registeredRoutes map[routeHandler][]RouteProperties
+
+	// methodNames counts the methods of the parsed packages by name, when inferring routes
+	methodNames map[string]int
+
+	// inferSchemas whether the request body and the responses are inferred from the code of the handlers
+	inferSchemas bool
+
+	// inferredSchemas holds the request body and the responses inferred by handler, when inferring schemas
+	inferredSchemas map[handlerKey]*inferredHandler
+
+	// cacheDir is the directory of the parse cache, disabled when empty
+	cacheDir string
+
+	// cache stores the operations parsed from the files, when the parse cache is enabled
+	cache *parseCache
+
+	// cacheRecorder records the operations of the file being parsed and the files declaring the types they use
+	cacheRecorder *parseCacheRecorder
+
+	// typeDependencies holds the files declaring the types used by the definitions being parsed, and their names
+	typeDependencies []*cacheDependencies
+
+	// definitionDependencies holds the files declaring the types used by the parsed definitions, and their names
+	definitionDependencies map[*TypeSpecDef]*cacheDependencies
+
+	// typesParsed whether the type definitions of the files are parsed
+	typesParsed bool
+
+	// parseWorkers is the number of workers parsing the Go source files and collecting their operations, the number
+	// of CPUs when it isn't positive
+	parseWorkers int
+
+	// operationBlocks holds the operations collected by collectOperations, by file
+	operationBlocks map[*AstFileInfo][]*operationBlock
 }
 
 // FieldParserFactory create FieldParser.
@@ -248,9 +333,18 @@ func New(options ...func(*Parser)) *Parser {
 			Channels: make(map[string]asyncSpec.ChannelItem),
 			Servers:  make(map[string]asyncSpec.ServersAdditionalProperties),
 			Components: &asyncSpec.Components{
-				Schemas: make(map[string]map[string]interface{}),
+				Schemas:  make(map[string]map[string]interface{}),
+				Messages: make(map[string]asyncSpec.Message),
 			},
 		},
+		asyncOperations:  make(map[string]*OperationWithChannel),
+		operationIDs:     make(map[string]token.Position),
+		routes:           make(map[string]declaredRoute),
+		registeredRoutes: make(map[routeHandler][]RouteProperties),
+		methodNames:      make(map[string]int),
+		inferredSchemas:  make(map[handlerKey]*inferredHandler),
+
+		definitionDependencies: make(map[*TypeSpecDef]*cacheDependencies),
 	}
 
 	for _, option := range options {
@@ -313,6 +407,15 @@ func SetPackagePrefix(packagePrefix string) func(*Parser) {
 	}
 }
 
+// SetParseWorkers sets the number of workers parsing the Go source files and collecting their operations
+// concurrently, the number of CPUs when it isn't positive. The attributes which resolve the types, and thus the
+// schemas, are still parsed in the order of the files, so that the output doesn't depend on the number of workers.
+func SetParseWorkers(parseWorkers int) func(*Parser) {
+	return func(p *Parser) {
+		p.parseWorkers = parseWorkers
+	}
+}
+
 // SetTags sets the tags to be included
 func SetTags(include string) func(*Parser) {
 	return func(p *Parser) {
@@ -461,17 +564,71 @@ func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile st
 		return err
 	}
 
-	parser.parsedSchemas, err = parser.packages.ParseTypes()
+	// with the cache, the types are only parsed when the operations of a file aren't cached
+	parser.typesParsed = false
+	parser.cache = nil
+
+	if parser.cacheDir != "" && !parser.lint && !parser.inferRoutes && !parser.inferSchemas {
+		parser.cache = newParseCache(parser.cacheDir, parser.cacheFingerprint(), parser.packages)
+	} else {
+		if parser.cacheDir != "" {
+			parser.debug.Printf("warning: the parse cache is disabled when linting and when inferring routes or schemas")
+		}
+
+		if err = parser.parseTypes(); err != nil {
+			return err
+		}
+	}
+
+	if parser.inferRoutes {
+		err = parser.packages.RangeFiles(parser.collectRegisteredRoutes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if parser.inferSchemas {
+		err = parser.inferHandlerSchemas(searchDirs)
+		if err != nil {
+			return err
+		}
+	}
+
+	parser.collectOperations()
+
+	err = parser.packages.RangeFiles(parser.ParseRouterAPIInfo)
 	if err != nil {
 		return err
 	}
 
-	err = parser.packages.RangeFiles(parser.ParseRouterAPIInfo)
+	parser.operationBlocks = nil
+
+	if err = parser.checkChannelServers(); err != nil {
+		return err
+	}
+
+	if parser.lint {
+		return nil
+	}
+
+	return parser.collectedErrors()
+}
+
+// parseTypes parses the type definitions of the files, once.
+func (parser *Parser) parseTypes() error {
+	if parser.typesParsed {
+		return nil
+	}
+
+	parsedSchemas, err := parser.packages.ParseTypes()
 	if err != nil {
 		return err
 	}
 
-	return parser.checkOperationIDUniqueness()
+	parser.parsedSchemas = parsedSchemas
+	parser.typesParsed = true
+
+	return nil
 }
 
 func getPkgName(searchDir string) (string, error) {
@@ -632,6 +789,22 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 
 			parser.swagger.SecurityDefinitions[value] = scheme
 
+		case asyncSecUserPasswordAttr, asyncSecAPIKeyAttr, asyncSecX509Attr, asyncSecSymmetricAttr, asyncSecAsymmetricAttr,
+			asyncSecHTTPAttr, asyncSecHTTPAPIKeyAttr, asyncSecImplicitAttr, asyncSecPasswordAttr, asyncSecClientCredentialsAttr,
+			asyncSecAuthorizationCodeAttr, asyncSecOpenIDConnectAttr, asyncSecPlainAttr, asyncSecScramSha256Attr,
+			asyncSecScramSha512Attr, asyncSecGSSAPIAttr:
+			scheme, err := parseAsyncAPISecAttributes(attribute, comments, &line)
+			if err != nil {
+				return err
+			}
+
+			if parser.asyncAPI.Components.SecuritySchemes == nil {
+				parser.asyncAPI.Components.SecuritySchemes = &asyncSpec.ComponentsSecuritySchemes{}
+			}
+
+			parser.asyncAPI.Components.SecuritySchemes.WithMapOfComponentsSecuritySchemesWDValuesItem(value,
+				asyncSpec.ComponentsSecuritySchemesWD{SecurityScheme: scheme})
+
 		case securityAttr:
 			parser.swagger.Security = append(parser.swagger.Security, parseSecurity(value))
 
@@ -811,7 +984,7 @@ loopline:
 		}
 
 		// next securityDefinitions
-		if strings.Index(securityAttr, "@securitydefinitions.") == 0 {
+		if strings.Index(securityAttr, "@securitydefinitions.") == 0 || strings.HasPrefix(securityAttr, asyncSecAttrPrefix) {
 			// Go back to the previous line and break
 			*index--
 
@@ -851,6 +1024,195 @@ loopline:
 	return scheme, nil
 }
 
+func parseAsyncAPISecAttributes(context string, lines []string, index *int) (*asyncSpec.SecurityScheme, error) {
+	const (
+		in               = "@in"
+		name             = "@name"
+		descriptionAttr  = "@description"
+		scheme           = "@scheme"
+		bearerFormat     = "@bearerformat"
+		openIDConnectURL = "@openidconnecturl"
+		tokenURL         = "@tokenurl"
+		authorizationURL = "@authorizationurl"
+		refreshURL       = "@refreshurl"
+	)
+
+	var search, optional []string
+
+	attribute := strings.ToLower(FieldsByAnySpace(lines[*index], 2)[0])
+	switch attribute {
+	case asyncSecAPIKeyAttr:
+		search = []string{in}
+	case asyncSecHTTPAttr:
+		search, optional = []string{scheme}, []string{bearerFormat}
+	case asyncSecHTTPAPIKeyAttr:
+		search = []string{in, name}
+	case asyncSecOpenIDConnectAttr:
+		search = []string{openIDConnectURL}
+	case asyncSecImplicitAttr:
+		search, optional = []string{authorizationURL}, []string{refreshURL}
+	case asyncSecPasswordAttr, asyncSecClientCredentialsAttr:
+		search, optional = []string{tokenURL}, []string{refreshURL}
+	case asyncSecAuthorizationCodeAttr:
+		search, optional = []string{tokenURL, authorizationURL}, []string{refreshURL}
+	}
+
+	// For the first line we get the attributes in the context parameter, so we skip to the next one
+	*index++
+
+	attrMap, scopes := make(map[string]string), make(map[string]string)
+	extensions, description := make(map[string]interface{}), ""
+
+loopline:
+	for ; *index < len(lines); *index++ {
+		v := strings.TrimSpace(lines[*index])
+		if len(v) == 0 {
+			continue
+		}
+
+		fields := FieldsByAnySpace(v, 2)
+		securityAttr := strings.ToLower(fields[0])
+		var value string
+		if len(fields) > 1 {
+			value = fields[1]
+		}
+
+		for _, findterm := range append(search, optional...) {
+			if securityAttr == findterm {
+				attrMap[securityAttr] = value
+				continue loopline
+			}
+		}
+
+		if isExists, err := isExistsScope(securityAttr); err != nil {
+			return nil, err
+		} else if isExists {
+			scopes[securityAttr[len(scopeAttrPrefix):]] = value
+			continue
+		}
+
+		if strings.HasPrefix(securityAttr, "@x-") {
+			// Add the custom attribute without the @
+			extensions[securityAttr[1:]] = value
+			continue
+		}
+
+		// Not mandatory field
+		if securityAttr == descriptionAttr {
+			if description != "" {
+				description += "\n"
+			}
+			description += value
+		}
+
+		// next securityDefinitions
+		if strings.Index(securityAttr, "@securitydefinitions.") == 0 || strings.HasPrefix(securityAttr, asyncSecAttrPrefix) {
+			// Go back to the previous line and break
+			*index--
+
+			break
+		}
+	}
+
+	for _, findterm := range search {
+		if _, ok := attrMap[findterm]; !ok {
+			return nil, fmt.Errorf("%s is %v required", context, search)
+		}
+	}
+
+	if len(extensions) == 0 {
+		extensions = nil
+	}
+
+	securityScheme := &asyncSpec.SecurityScheme{}
+
+	oauth2Flow := &asyncSpec.Oauth2Flow{
+		AuthorizationURL: attrMap[authorizationURL],
+		TokenURL:         attrMap[tokenURL],
+		RefreshURL:       attrMap[refreshURL],
+		Scopes:           scopes,
+	}
+
+	switch attribute {
+	case asyncSecUserPasswordAttr:
+		securityScheme.WithUserPassword(asyncSpec.UserPassword{Description: description, MapOfAnything: extensions})
+	case asyncSecAPIKeyAttr:
+		keyIn := asyncSpec.APIKeyIn(attrMap[in])
+		if keyIn != asyncSpec.APIKeyInUser && keyIn != asyncSpec.APIKeyInPassword {
+			return nil, fmt.Errorf("%s @in must be one of [user password], got %s", context, attrMap[in])
+		}
+
+		securityScheme.WithAPIKey(asyncSpec.APIKey{In: keyIn, Description: description, MapOfAnything: extensions})
+	case asyncSecX509Attr:
+		securityScheme.WithX509(asyncSpec.X509{Description: description, MapOfAnything: extensions})
+	case asyncSecSymmetricAttr:
+		securityScheme.WithSymmetricEncryption(asyncSpec.SymmetricEncryption{Description: description, MapOfAnything: extensions})
+	case asyncSecAsymmetricAttr:
+		securityScheme.WithAsymmetricEncryption(asyncSpec.AsymmetricEncryption{Description: description, MapOfAnything: extensions})
+	case asyncSecHTTPAttr:
+		if strings.ToLower(attrMap[scheme]) == "bearer" {
+			securityScheme.HTTPSecuritySchemeEns().WithBearerHTTPSecurityScheme(asyncSpec.BearerHTTPSecurityScheme{
+				BearerFormat: attrMap[bearerFormat], Description: description, MapOfAnything: extensions,
+			})
+			break
+		}
+
+		securityScheme.HTTPSecuritySchemeEns().WithNonBearerHTTPSecurityScheme(asyncSpec.NonBearerHTTPSecurityScheme{
+			Scheme: attrMap[scheme], Description: description, MapOfAnything: extensions,
+		})
+	case asyncSecHTTPAPIKeyAttr:
+		keyIn := asyncSpec.APIKeyHTTPSecuritySchemeIn(attrMap[in])
+		switch keyIn {
+		case asyncSpec.APIKeyHTTPSecuritySchemeInHeader, asyncSpec.APIKeyHTTPSecuritySchemeInQuery, asyncSpec.APIKeyHTTPSecuritySchemeInCookie:
+		default:
+			return nil, fmt.Errorf("%s @in must be one of [header query cookie], got %s", context, attrMap[in])
+		}
+
+		securityScheme.HTTPSecuritySchemeEns().WithAPIKeyHTTPSecurityScheme(asyncSpec.APIKeyHTTPSecurityScheme{
+			Name: attrMap[name], In: keyIn, Description: description, MapOfAnything: extensions,
+		})
+	case asyncSecImplicitAttr:
+		securityScheme.WithOauth2Flows(asyncSpec.Oauth2Flows{
+			Description: description, Flows: asyncSpec.Oauth2FlowsFlows{Implicit: oauth2Flow}, MapOfAnything: extensions,
+		})
+	case asyncSecPasswordAttr:
+		securityScheme.WithOauth2Flows(asyncSpec.Oauth2Flows{
+			Description: description, Flows: asyncSpec.Oauth2FlowsFlows{Password: oauth2Flow}, MapOfAnything: extensions,
+		})
+	case asyncSecClientCredentialsAttr:
+		securityScheme.WithOauth2Flows(asyncSpec.Oauth2Flows{
+			Description: description, Flows: asyncSpec.Oauth2FlowsFlows{ClientCredentials: oauth2Flow}, MapOfAnything: extensions,
+		})
+	case asyncSecAuthorizationCodeAttr:
+		securityScheme.WithOauth2Flows(asyncSpec.Oauth2Flows{
+			Description: description, Flows: asyncSpec.Oauth2FlowsFlows{AuthorizationCode: oauth2Flow}, MapOfAnything: extensions,
+		})
+	case asyncSecOpenIDConnectAttr:
+		securityScheme.WithOpenIDConnect(asyncSpec.OpenIDConnect{
+			URL: attrMap[openIDConnectURL], Description: description, MapOfAnything: extensions,
+		})
+	case asyncSecPlainAttr:
+		securityScheme.SaslSecuritySchemeEns().WithSaslPlainSecurityScheme(asyncSpec.SaslPlainSecurityScheme{
+			Description: description, MapOfAnything: extensions,
+		})
+	case asyncSecScramSha256Attr, asyncSecScramSha512Attr:
+		scramType := asyncSpec.SaslScramSecuritySchemeTypeScramSha256
+		if attribute == asyncSecScramSha512Attr {
+			scramType = asyncSpec.SaslScramSecuritySchemeTypeScramSha512
+		}
+
+		securityScheme.SaslSecuritySchemeEns().WithSaslScramSecurityScheme(asyncSpec.SaslScramSecurityScheme{
+			Type: scramType, Description: description, MapOfAnything: extensions,
+		})
+	case asyncSecGSSAPIAttr:
+		securityScheme.SaslSecuritySchemeEns().WithSaslGssapiSecurityScheme(asyncSpec.SaslGssapiSecurityScheme{
+			Description: description, MapOfAnything: extensions,
+		})
+	}
+
+	return securityScheme, nil
+}
+
 func parseSecurity(commentLine string) map[string][]string {
 	securityMap := make(map[string][]string)
 
@@ -1092,42 +1454,171 @@ func (parser *Parser) ParseRouterAPIInfo(fileInfo *AstFileInfo) error {
 		return nil
 	}
 
+	if parser.cache != nil {
+		return parser.parseRouterAPIInfoWithCache(fileInfo)
+	}
+
+	return parser.parseRouterAPIInfo(fileInfo)
+}
+
+func (parser *Parser) parseRouterAPIInfo(fileInfo *AstFileInfo) error {
+	blocks, ok := parser.operationBlocks[fileInfo]
+	if !ok {
+		blocks = parser.collectOperationBlocks(fileInfo)
+	}
+
+	delete(parser.operationBlocks, fileInfo)
+
+	for _, block := range blocks {
+		if block.operation == nil {
+			if err := parser.handleAsyncAPIComments(block.funcName, block.comments, fileInfo); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var handler *inferredHandler
+		if block.decl != nil {
+			handler = parser.inferHandler(fileInfo, block.decl)
+		}
+
+		if err := parser.handleOpenAPIComments(block, fileInfo, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// operationBlock is a comment block of a file matching the tags and the extension, whose OpenAPI attributes which
+// don't resolve a type are parsed by collectOperationBlocks.
+type operationBlock struct {
+	funcName *string
+	// decl is the declaration documented by the block, nil with ParseFuncBody
+	decl     ast.Decl
+	comments []*ast.Comment
+
+	// operation holds the parsed attributes, nil for an AsyncAPI block
+	operation *Operation
+
+	// deferred holds the indexes of the comments with the attributes which may resolve a type, they're parsed in the
+	// order of the files by handleOpenAPIComments
+	deferred []int
+
+	// end is the index of the comment which changed the state of the operation, or failed with err, the parsing
+	// stopped after it
+	end int
+	err error
+}
+
+// collectOperations parses the attributes which don't resolve a type of the operations of every file, concurrently
+// with the parse workers, so that only the attributes which resolve the types, and thus the schemas, are parsed in
+// the order of the files.
+func (parser *Parser) collectOperations() {
+	var files []*AstFileInfo
+
+	_ = parser.packages.RangeFiles(func(fileInfo *AstFileInfo) error {
+		if (fileInfo.ParseFlag & ParseOperations) != ParseNone {
+			files = append(files, fileInfo)
+		}
+
+		return nil
+	})
+
+	blocks := make([][]*operationBlock, len(files))
+
+	parser.runWorkers(len(files), func(index int) {
+		blocks[index] = parser.collectOperationBlocks(files[index])
+	})
+
+	parser.operationBlocks = make(map[*AstFileInfo][]*operationBlock, len(files))
+	for index, fileInfo := range files {
+		parser.operationBlocks[fileInfo] = blocks[index]
+	}
+}
+
+// collectOperationBlocks returns the comment blocks of the operations of a file. It only reads the parser, so that
+// the files are collected concurrently.
+func (parser *Parser) collectOperationBlocks(fileInfo *AstFileInfo) []*operationBlock {
+	var blocks []*operationBlock
+
+	addBlock := func(funcName *string, decl ast.Decl, comments []*ast.Comment) {
+		if !parser.matchTags(comments) || !matchExtension(parser.parseExtension, comments) {
+			return
+		}
+
+		block := &operationBlock{funcName: funcName, decl: decl, comments: comments}
+		if !isAsyncAPIComment(comments) {
+			parser.parseOperationBlock(block, fileInfo)
+		}
+
+		blocks = append(blocks, block)
+	}
+
 	// parse File.Comments instead of File.Decls.Doc if ParseFuncBody flag set to "true"
 	if parser.ParseFuncBody {
 		for _, astComments := range fileInfo.File.Comments {
 			if astComments.List != nil {
-				if err := parser.parseFunctionInfoComment(nil, astComments.List, fileInfo); err != nil {
-					return err
-				}
+				addBlock(nil, nil, astComments.List)
 			}
 		}
 
-		return nil
+		return blocks
 	}
 
 	for _, decl := range fileInfo.File.Decls {
 		funcDoc, ok := getFuncDoc(decl)
 		if ok && funcDoc != nil && funcDoc.List != nil {
-			funcName := getFuncName(decl)
-			if err := parser.parseFunctionInfoComment(funcName, funcDoc.List, fileInfo); err != nil {
-				return err
-			}
+			addBlock(getFuncName(decl), decl, funcDoc.List)
 		}
 	}
 
-	return nil
+	return blocks
 }
 
-func (parser *Parser) parseFunctionInfoComment(funcName *string, comments []*ast.Comment, fileInfo *AstFileInfo) error {
-	if !parser.matchTags(comments) || !matchExtension(parser.parseExtension, comments) {
-		return nil
+// parseOperationBlock parses the attributes of an OpenAPI block which don't resolve a type, the other ones are
+// deferred.
+func (parser *Parser) parseOperationBlock(block *operationBlock, fileInfo *AstFileInfo) {
+	// for per 'function' comment, create a new 'Operation' object
+	block.operation = NewOperation(parser, SetCodeExampleFilesDirectory(parser.codeExampleFilesDir))
+	block.end = len(block.comments)
+
+	for index, comment := range block.comments {
+		position := commentPosition(fileInfo, comment)
+
+		if resolvesTypes(comment.Text) {
+			block.deferred = append(block.deferred, index)
+			block.operation.positions.record(comment.Text, position)
+
+			continue
+		}
+
+		// the rest of the block is skipped
+		if err := block.operation.parseCommentAt(comment.Text, fileInfo.File, position); err != nil {
+			block.end, block.err = index, err
+
+			return
+		}
+
+		// Early exit if the operation state changes and is no longer the host state.
+		if block.operation.State != "" && block.operation.State != parser.HostState {
+			block.end = index
+
+			return
+		}
 	}
+}
 
-	if isAsyncAPIComment(comments) {
-		return parser.handleAsyncAPIComments(funcName, comments, fileInfo)
+// resolvesTypes reports whether the attribute of a comment may resolve a type, or depends on one which does, like
+// @Header on the responses.
+func resolvesTypes(comment string) bool {
+	switch attribute, _ := commentAttribute(comment); attribute {
+	case paramAttr, successAttr, failureAttr, responseAttr, headerAttr:
+		return true
 	}
 
-	return parser.handleOpenAPIComments(comments, fileInfo)
+	return false
 }
 
 // Determines if the comments represent an AsyncAPI block.
@@ -1142,31 +1633,81 @@ func isAsyncAPIComment(comments []*ast.Comment) bool {
 
 // Handles AsyncAPI comments by creating a new scope and processing it.
 func (parser *Parser) handleAsyncAPIComments(funcName *string, comments []*ast.Comment, fileInfo *AstFileInfo) error {
+	if parser.cacheRecorder != nil {
+		parser.cacheRecorder.uncacheable = true
+	}
+
 	asyncAPIScope := NewAsyncScope(parser)
 
 	for _, comment := range comments[1:] {
-		if err := asyncAPIScope.ParseAsyncAPIComment(funcName, comment.Text, fileInfo.File); err != nil {
-			return fmt.Errorf("ParseAsyncAPIComment error in file %s for comment: '%s': %+v", fileInfo.Path, comment.Text, err)
+		if attribute, _ := commentAttribute(comment.Text); attribute != "" && !isAsyncAPIAttribute(attribute) {
+			if err := parser.reportRule(RuleUnknownAttribute, commentPosition(fileInfo, comment), "unknown attribute '%s'", attribute); err != nil {
+				return err
+			}
+
+			continue
 		}
+
+		position := commentPosition(fileInfo, comment)
+
+		// the rest of the block is skipped
+		if err := asyncAPIScope.parseAsyncAPICommentAt(funcName, comment.Text, fileInfo.File, position); err != nil {
+			return parser.reportRule(RuleInvalidAnnotation, position, "%s", err)
+		}
+	}
+
+	if err := asyncAPIScope.applyMessageAttributes(); err != nil {
+		position, err := errorPosition(err, commentPosition(fileInfo, comments[0]))
+
+		return parser.reportRule(RuleInvalidAnnotation, position, "%s", err)
+	}
+
+	parser.recordChannelServers(asyncAPIScope)
+
+	if err := processAsyncAPIScope(parser, asyncAPIScope); err != nil {
+		position, err := errorPosition(err, commentPosition(fileInfo, comments[0]))
+
+		return parser.reportRule(RuleDuplicateOperationID, position, "%s", err)
 	}
 
-	return processAsyncAPIScope(parser, asyncAPIScope)
+	return nil
 }
 
-// Handles OpenAPI comments by creating an operation and processing it.
-func (parser *Parser) handleOpenAPIComments(comments []*ast.Comment, fileInfo *AstFileInfo) error {
-	// for per 'function' comment, create a new 'Operation' object
-	httpOperation := NewOperation(parser, SetCodeExampleFilesDirectory(parser.codeExampleFilesDir))
+// Handles OpenAPI comments by parsing the deferred attributes of the operation and processing it.
+func (parser *Parser) handleOpenAPIComments(block *operationBlock, fileInfo *AstFileInfo, handler *inferredHandler) error {
+	httpOperation := block.operation
+
+	// the deferred attributes precede the end of the block, so the first invalid attribute is reported
+	for _, index := range block.deferred {
+		comment := block.comments[index]
 
-	for _, comment := range comments {
 		if err := httpOperation.ParseComment(comment.Text, fileInfo.File); err != nil {
-			return fmt.Errorf("ParseComment error in file %s for comment: '%s': %+v", fileInfo.Path, comment.Text, err)
+			return parser.reportRule(RuleInvalidAnnotation, commentPosition(fileInfo, comment), "%s", err)
 		}
+	}
 
-		// Early exit if the operation state changes and is no longer the host state.
-		if httpOperation.State != "" && httpOperation.State != parser.HostState {
-			return nil
+	if block.err != nil {
+		return parser.reportRule(RuleInvalidAnnotation, commentPosition(fileInfo, block.comments[block.end]), "%s", block.err)
+	}
+
+	if httpOperation.State != "" && httpOperation.State != parser.HostState {
+		return nil
+	}
+
+	if handler != nil {
+		if err := parser.bindRegisteredRoutes(httpOperation, handler.routes); err != nil {
+			return err
 		}
+
+		parser.mergeInferredSchemas(httpOperation, handler, fileInfo)
+	}
+
+	if err := parser.checkOperationAttributes(httpOperation); err != nil {
+		return err
+	}
+
+	if parser.cacheRecorder != nil {
+		parser.recordOperation(httpOperation, block.comments)
 	}
 
 	return processRouterOperation(parser, httpOperation)
@@ -1174,12 +1715,42 @@ func (parser *Parser) handleOpenAPIComments(comments []*ast.Comment, fileInfo *A
 
 // Processes the AsyncAPI scope and updates the parser's AsyncAPI configuration.
 func processAsyncAPIScope(parser *Parser, asyncAPIScope *AsyncScope) error {
+	if err := checkAsyncOperationIDUniqueness(parser, asyncAPIScope); err != nil {
+		return err
+	}
+
 	addAsyncAPIServers(parser, asyncAPIScope)
 	addAsyncAPIChannels(parser, asyncAPIScope)
+	addAsyncAPIMessages(parser, asyncAPIScope)
 	addAsyncAPIOperations(parser, asyncAPIScope)
 	return nil
 }
 
+// Checks that the operations of the AsyncAPI scope don't reuse the ID of an operation of another scope.
+func checkAsyncOperationIDUniqueness(parser *Parser, asyncAPIScope *AsyncScope) error {
+	operationIDs := make([]string, 0, len(asyncAPIScope.operations))
+	for operationID := range asyncAPIScope.operations {
+		operationIDs = append(operationIDs, operationID)
+	}
+
+	sort.Strings(operationIDs)
+
+	for _, operationID := range operationIDs {
+		previous, ok := parser.asyncOperations[operationID]
+		if !ok {
+			continue
+		}
+
+		current := asyncAPIScope.operations[operationID]
+
+		return &ParseError{Pos: current.position, Err: fmt.Errorf(
+			"duplicated AsyncAPI operation id '%s' found in '%s %s', previously declared in: '%s %s'",
+			operationID, current.action, current.channel, previous.action, previous.channel)}
+	}
+
+	return nil
+}
+
 // Adds servers from the AsyncAPI scope to the parser's AsyncAPI configuration.
 func addAsyncAPIServers(parser *Parser, asyncAPIScope *AsyncScope) {
 	for serverName, server := range asyncAPIScope.servers {
@@ -1199,20 +1770,39 @@ func addAsyncAPIChannels(parser *Parser, asyncAPIScope *AsyncScope) {
 	}
 }
 
+// Adds messages from the AsyncAPI scope to the components of the parser's AsyncAPI configuration.
+func addAsyncAPIMessages(parser *Parser, asyncAPIScope *AsyncScope) {
+	for messageID, entity := range asyncAPIScope.messages {
+		parser.asyncAPI.Components.Messages[messageID] = asyncSpec.Message{
+			OneOf1: &asyncSpec.MessageOneOf1{MessageEntity: entity},
+		}
+	}
+}
+
 // Adds operations from the AsyncAPI scope to the corresponding channels in the parser's AsyncAPI configuration.
 func addAsyncAPIOperations(parser *Parser, asyncAPIScope *AsyncScope) {
-	for _, operation := range asyncAPIScope.operations {
+	operationIDs := make([]string, 0, len(asyncAPIScope.operations))
+	for operationID := range asyncAPIScope.operations {
+		operationIDs = append(operationIDs, operationID)
+	}
+
+	sort.Strings(operationIDs)
+
+	for _, operationID := range operationIDs {
+		operation := asyncAPIScope.operations[operationID]
 		channel := parser.asyncAPI.Channels[operation.channel]
 
+		// Operations with the same action on a channel are merged, so every message type of the channel is kept.
 		if operation.action == Receive {
-			channel.Publish = &operation.Operation
+			channel.Publish = mergeOperations(channel.Publish, &operation.Operation)
 		}
 
 		if operation.action == Send {
-			channel.Subscribe = &operation.Operation
+			channel.Subscribe = mergeOperations(channel.Subscribe, &operation.Operation)
 		}
 
 		parser.asyncAPI.Channels[operation.channel] = channel
+		parser.asyncOperations[operation.ID] = operation
 	}
 }
 
@@ -1238,12 +1828,32 @@ func refRouteMethodOp(item *spec.PathItem, method string) (op **spec.Operation)
 }
 
 func processRouterOperation(parser *Parser, operation *Operation) error {
-	for _, routeProperties := range operation.RouterProperties {
+	if err := checkPathParams(parser, operation); err != nil {
+		return err
+	}
+
+	if err := checkResponses(parser, operation); err != nil {
+		return err
+	}
+
+	if err := checkOperationID(parser, operation); err != nil {
+		return err
+	}
+
+	for i, routeProperties := range operation.RouterProperties {
 		var (
 			pathItem spec.PathItem
 			ok       bool
 		)
 
+		if err := checkRoutePlaceholders(parser, operation, routeProperties, operation.positions.router(i)); err != nil {
+			return err
+		}
+
+		if err := checkDuplicateRoute(parser, routeProperties, operation.positions.router(i)); err != nil {
+			return err
+		}
+
 		pathItem, ok = parser.swagger.Paths.Paths[routeProperties.Path]
 		if !ok {
 			pathItem = spec.PathItem{}
@@ -1251,21 +1861,11 @@ func processRouterOperation(parser *Parser, operation *Operation) error {
 
 		op := refRouteMethodOp(&pathItem, routeProperties.HTTPMethod)
 
-		// check if we already have an operation for this path and method
-		if *op != nil {
-			err := fmt.Errorf("route %s %s is declared multiple times", routeProperties.HTTPMethod, routeProperties.Path)
-			if parser.Strict {
-				return err
-			}
-
-			parser.debug.Printf("warning: %s\n", err)
-		}
-
 		if len(operation.RouterProperties) > 1 {
 			newOp := *operation
 			var validParams []spec.Parameter
 			for _, param := range newOp.Operation.OperationProps.Parameters {
-				if param.In == "path" && !strings.Contains(routeProperties.Path, param.Name) {
+				if param.In == "path" && !routeHasParam(routeProperties.Path, param.Name) {
 					// This path param is not actually contained in the path, skip adding it to the final params
 					continue
 				}
@@ -1287,6 +1887,170 @@ func processRouterOperation(parser *Parser, operation *Operation) error {
 	return nil
 }
 
+// declaredRoute is a route declared by an operation.
+type declaredRoute struct {
+	path     string
+	position token.Position
+}
+
+// checkDuplicateRoute checks that a route, or the same route with other placeholder names, isn't declared by another
+// operation.
+func checkDuplicateRoute(parser *Parser, routeProperties RouteProperties, position token.Position) error {
+	previous, ok := parser.routes[routeKey(routeProperties)]
+	if !ok {
+		parser.routes[routeKey(routeProperties)] = declaredRoute{path: routeProperties.Path, position: position}
+
+		return nil
+	}
+
+	if previous.path == routeProperties.Path {
+		return parser.reportRule(RuleDuplicateRoute, position, "route %s %s is already declared at %s",
+			routeProperties.HTTPMethod, routeProperties.Path, previous.position)
+	}
+
+	return parser.reportRule(RuleDuplicateRoute, position, "route %s %s is already declared as %s %s at %s",
+		routeProperties.HTTPMethod, routeProperties.Path, routeProperties.HTTPMethod, previous.path, previous.position)
+}
+
+// checkPathParams checks that every path parameter of an operation is a placeholder of one of its routes.
+func checkPathParams(parser *Parser, operation *Operation) error {
+	var routes []string
+	for _, routeProperties := range operation.RouterProperties {
+		routes = append(routes, routeProperties.HTTPMethod+" "+routeProperties.Path)
+	}
+
+	for _, param := range operation.Parameters {
+		if param.In != "path" || len(routes) == 0 {
+			continue
+		}
+
+		found := false
+
+		for _, routeProperties := range operation.RouterProperties {
+			if routeHasParam(routeProperties.Path, param.Name) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			err := parser.reportRule(RulePathParamNotInRoute, operation.positions.param(param.Name),
+				"path parameter '%s' isn't a placeholder of %s", param.Name, strings.Join(routes, ", "))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkRoutePlaceholders checks that every placeholder of a route has a path parameter.
+func checkRoutePlaceholders(parser *Parser, operation *Operation, routeProperties RouteProperties, position token.Position) error {
+	for _, placeholder := range routePlaceholders(routeProperties.Path) {
+		if !hasPathParam(operation, placeholder) {
+			err := parser.reportRule(RulePathPlaceholderWithoutParam, position,
+				"placeholder '%s' of route %s %s has no path parameter", placeholder, routeProperties.HTTPMethod, routeProperties.Path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkResponses checks that an operation declares a response.
+func checkResponses(parser *Parser, operation *Operation) error {
+	if len(operation.RouterProperties) == 0 {
+		return nil
+	}
+
+	if operation.Responses != nil && (operation.Responses.Default != nil || len(operation.Responses.StatusCodeResponses) > 0) {
+		return nil
+	}
+
+	return parser.reportRule(RuleMissingResponse, operation.positions.router(0), "operation doesn't declare any response")
+}
+
+// checkOperationID checks that the ID of an operation isn't used by another operation.
+func checkOperationID(parser *Parser, operation *Operation) error {
+	if operation.ID == "" || len(operation.RouterProperties) == 0 {
+		return nil
+	}
+
+	previous, ok := parser.operationIDs[operation.ID]
+	if !ok {
+		parser.operationIDs[operation.ID] = operation.positions.operationID()
+
+		return nil
+	}
+
+	return parser.reportRule(RuleDuplicateOperationID, operation.positions.operationID(),
+		"operation ID '%s' is already used at %s", operation.ID, previous)
+}
+
+// hasPathParam reports whether the operation has a path parameter of the name.
+func hasPathParam(operation *Operation, name string) bool {
+	for _, param := range operation.Parameters {
+		if param.In == "path" && param.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// routePlaceholderPattern matches a placeholder of a route path, as {name} or {name:pattern}.
+var routePlaceholderPattern = regexp.MustCompile(`\{([^{}:]+)(?::[^{}]*)?\}`)
+
+// routePlaceholders returns the names of the placeholders of a route path, as {name} or :name.
+func routePlaceholders(path string) []string {
+	var placeholders []string
+
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") && len(segment) > 1 {
+			placeholders = append(placeholders, segment[1:])
+
+			continue
+		}
+
+		for _, matches := range routePlaceholderPattern.FindAllStringSubmatch(segment, -1) {
+			placeholders = append(placeholders, matches[1])
+		}
+	}
+
+	return placeholders
+}
+
+// routeHasParam reports whether a route path has a placeholder for the parameter.
+func routeHasParam(path, name string) bool {
+	for _, placeholder := range routePlaceholders(path) {
+		if placeholder == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// routeKey returns the method and the path of a route without the names of its placeholders, so that routes which
+// only differ by them are the same.
+func routeKey(routeProperties RouteProperties) string {
+	var segments []string
+
+	for _, segment := range strings.Split(routeProperties.Path, "/") {
+		if strings.HasPrefix(segment, ":") && len(segment) > 1 {
+			segment = "{}"
+		}
+
+		segments = append(segments, routePlaceholderPattern.ReplaceAllString(segment, "{}"))
+	}
+
+	return routeProperties.HTTPMethod + " " + strings.Join(segments, "/")
+}
+
 func convertFromSpecificToPrimitive(typeName string) (string, error) {
 	name := typeName
 	if strings.ContainsRune(name, '.') {
@@ -1346,8 +2110,18 @@ func (parser *Parser) getTypeSchema(typeName string, file *ast.File, ref bool, f
 		typeSpecDef = parser.packages.findTypeSpec(override[0:separator], override[separator+1:])
 	}
 
+	return parser.getTypeSpecSchema(typeSpecDef, typeName, ref, forAsyncAPI)
+}
+
+// getTypeSpecSchema returns the schema of a type definition, a reference to its definition if it's complex and ref
+// is set.
+func (parser *Parser) getTypeSpecSchema(typeSpecDef *TypeSpecDef, typeName string, ref bool, forAsyncAPI bool) (*spec.Schema, error) {
+	parser.recordTypeDependency(typeSpecDef)
+
 	schema, ok := parser.parsedSchemas[typeSpecDef]
-	if !ok {
+	if ok {
+		parser.markSchemaUsage(typeSpecDef, schema, forAsyncAPI)
+	} else {
 		var err error
 
 		schema, err = parser.ParseDefinition(typeSpecDef, forAsyncAPI)
@@ -1388,6 +2162,17 @@ func (parser *Parser) getRefTypeSchema(typeSpecDef *TypeSpecDef, schema *Schema)
 	return refSchema
 }
 
+// markSchemaUsage marks a parsed schema as used by the OpenAPI or by the AsyncAPI spec.
+func (parser *Parser) markSchemaUsage(typeSpecDef *TypeSpecDef, schema *Schema, forAsyncAPI bool) {
+	if forAsyncAPI {
+		schema.UsedForAsyncAPI = true
+	} else {
+		schema.UsedForOpenAPI = true
+	}
+
+	parser.markPolymorphismUsage(typeSpecDef, forAsyncAPI)
+}
+
 func (parser *Parser) isInStructStack(typeSpecDef *TypeSpecDef) bool {
 	for _, specDef := range parser.structStack {
 		if typeSpecDef == specDef {
@@ -1406,11 +2191,7 @@ func (parser *Parser) ParseDefinition(typeSpecDef *TypeSpecDef, forAsyncAPI bool
 	schema, found := parser.parsedSchemas[typeSpecDef]
 	if found {
 		parser.debug.Printf("Skipping '%s', already parsed.", typeName)
-		if forAsyncAPI {
-			schema.UsedForAsyncAPI = true
-		} else {
-			schema.UsedForOpenAPI = true
-		}
+		parser.markSchemaUsage(typeSpecDef, schema, forAsyncAPI)
 		return schema, nil
 	}
 
@@ -1427,9 +2208,31 @@ func (parser *Parser) ParseDefinition(typeSpecDef *TypeSpecDef, forAsyncAPI bool
 
 	parser.structStack = append(parser.structStack, typeSpecDef)
 
+	parser.beginDefinitionDependencies()
+	defer parser.endDefinitionDependencies(typeSpecDef)
+
 	parser.debug.Printf("Generating %s", typeName)
 
-	definition, err := parser.parseTypeExpr(typeSpecDef.File, typeSpecDef.TypeSpec.Type, false, forAsyncAPI)
+	var (
+		definition *spec.Schema
+		err        error
+	)
+
+	switch {
+	case typeSpecDef.polymorphism != nil:
+		definition, err = parser.parsePolymorphism(typeSpecDef.polymorphism, forAsyncAPI)
+	case typeSpecDef.marshaler != nil:
+		// marshaled as text, whatever the type is
+		definition = PrimitiveSchema(STRING)
+		definition.Format = typeSpecDef.marshaler.format
+	default:
+		definition, err = parser.parseTypeExpr(typeSpecDef.File, typeSpecDef.TypeSpec.Type, false, forAsyncAPI)
+	}
+
+	if err == nil && typeSpecDef.implementation != nil {
+		definition, err = parser.parseImplementation(typeSpecDef.implementation, definition, forAsyncAPI)
+	}
+
 	if err != nil {
 		parser.debug.Printf("Error parsing type definition '%s': %s", typeName, err)
 		return nil, err
@@ -1446,18 +2249,25 @@ func (parser *Parser) ParseDefinition(typeSpecDef *TypeSpecDef, forAsyncAPI bool
 		var varnames []string
 		var enumComments = make(map[string]string)
 		var enumDescriptions = make([]string, 0, len(typeSpecDef.Enums))
+		namedValues := true
 		for _, value := range typeSpecDef.Enums {
 			definition.Enum = append(definition.Enum, value.Value)
 			varnames = append(varnames, value.key)
 			if len(value.Comment) > 0 {
-				enumComments[value.key] = value.Comment
+				if value.key != "" {
+					enumComments[value.key] = value.Comment
+				}
 				enumDescriptions = append(enumDescriptions, value.Comment)
 			}
+			namedValues = namedValues && value.key != ""
 		}
 		if definition.Extensions == nil {
 			definition.Extensions = make(spec.Extensions)
 		}
-		definition.Extensions[enumVarNamesExtension] = varnames
+		// the literals returned by an EnumValues method may have no name
+		if namedValues {
+			definition.Extensions[enumVarNamesExtension] = varnames
+		}
 		if len(enumComments) > 0 {
 			definition.Extensions[enumCommentsExtension] = enumComments
 			definition.Extensions[enumDescriptionsExtension] = enumDescriptions
@@ -1927,7 +2737,10 @@ func (parser *Parser) getAllGoFileInfo(packageDir, searchDir string) error {
 	if parser.skipPackageByPrefix(packageDir) {
 		return nil // ignored by user-defined package path prefixes
 	}
-	return filepath.Walk(searchDir, func(path string, f os.FileInfo, _ error) error {
+
+	var files []goFile
+
+	err := filepath.Walk(searchDir, func(path string, f os.FileInfo, _ error) error {
 		err := parser.Skip(path, f)
 		if err != nil {
 			return err
@@ -1942,95 +2755,164 @@ func (parser *Parser) getAllGoFileInfo(packageDir, searchDir string) error {
 			return err
 		}
 
-		return parser.parseFile(filepath.ToSlash(filepath.Dir(filepath.Clean(filepath.Join(packageDir, relPath)))), path, nil, ParseAll)
+		files = append(files, goFile{
+			packageDir: filepath.ToSlash(filepath.Dir(filepath.Clean(filepath.Join(packageDir, relPath)))),
+			path:       path,
+		})
+
+		return nil
 	})
+
+	// the files found before the error are parsed, as when they were parsed one by one
+	if parseErr := parser.parseFiles(files, ParseAll); parseErr != nil {
+		return parseErr
+	}
+
+	return err
 }
 
 func (parser *Parser) getAllGoFileInfoFromDeps(pkg *depth.Pkg, parseFlag ParseFlag) error {
+	files, err := parser.collectDepsFiles(pkg, nil)
+
+	if parseErr := parser.parseFiles(files, parseFlag); parseErr != nil {
+		return parseErr
+	}
+
+	return err
+}
+
+// collectDepsFiles appends the files of a dependency and of its own dependencies.
+func (parser *Parser) collectDepsFiles(pkg *depth.Pkg, files []goFile) ([]goFile, error) {
 	ignoreInternal := pkg.Internal && !parser.ParseInternal
 	if ignoreInternal || !pkg.Resolved { // ignored internal and not resolved dependencies
-		return nil
+		return files, nil
 	}
 
 	if pkg.Raw != nil && parser.skipPackageByPrefix(pkg.Raw.ImportPath) {
-		return nil // ignored by user-defined package path prefixes
+		return files, nil // ignored by user-defined package path prefixes
 	}
 
 	// Skip cgo
 	if pkg.Raw == nil && pkg.Name == "C" {
-		return nil
+		return files, nil
 	}
 
 	srcDir := pkg.Raw.Dir
 
-	files, err := os.ReadDir(srcDir) // only parsing files in the dir(don't contain sub dir files)
+	entries, err := os.ReadDir(srcDir) // only parsing files in the dir(don't contain sub dir files)
 	if err != nil {
-		return err
+		return files, err
 	}
 
-	for _, f := range files {
+	for _, f := range entries {
 		if f.IsDir() {
 			continue
 		}
 
-		path := filepath.Join(srcDir, f.Name())
-		if err := parser.parseFile(pkg.Name, path, nil, parseFlag); err != nil {
-			return err
-		}
+		files = append(files, goFile{packageDir: pkg.Name, path: filepath.Join(srcDir, f.Name())})
 	}
 
 	for i := 0; i < len(pkg.Deps); i++ {
-		if err := parser.getAllGoFileInfoFromDeps(&pkg.Deps[i], parseFlag); err != nil {
-			return err
+		if files, err = parser.collectDepsFiles(&pkg.Deps[i], files); err != nil {
+			return files, err
 		}
 	}
 
-	return nil
+	return files, nil
 }
 
 func (parser *Parser) parseFile(packageDir, path string, src interface{}, flag ParseFlag) error {
-	if strings.HasSuffix(strings.ToLower(path), "_test.go") || filepath.Ext(path) != ".go" {
+	if !isParsedGoFile(path) {
 		return nil
 	}
 
 	return parser.packages.ParseFile(packageDir, path, src, flag)
 }
 
-func (parser *Parser) checkOperationIDUniqueness() error {
-	// operationsIds contains all operationId annotations to check it's unique
-	operationsIds := make(map[string]string)
+func isParsedGoFile(path string) bool {
+	return !strings.HasSuffix(strings.ToLower(path), "_test.go") && filepath.Ext(path) == ".go"
+}
+
+// goFile is a Go source file of a package.
+type goFile struct {
+	packageDir string
+	path       string
+}
 
-	for path, item := range parser.swagger.Paths.Paths {
-		var method, id string
+// parseFiles parses Go source files with a bounded pool of workers, then collects them in order so that the
+// result doesn't depend on the number of workers. It returns the error of the first file which can't be parsed.
+func (parser *Parser) parseFiles(files []goFile, flag ParseFlag) error {
+	type parsedFile struct {
+		fileSet *token.FileSet
+		astFile *ast.File
+		err     error
+	}
 
-		for method = range allMethod {
-			op := refRouteMethodOp(&item, method)
-			if *op != nil {
-				id = (**op).ID
+	var sourceFiles []goFile
 
-				break
-			}
+	for _, file := range files {
+		if isParsedGoFile(file.path) {
+			sourceFiles = append(sourceFiles, file)
 		}
+	}
 
-		if id == "" {
-			continue
-		}
+	parsedFiles := make([]parsedFile, len(sourceFiles))
 
-		current := fmt.Sprintf("%s %s", method, path)
+	parser.runWorkers(len(sourceFiles), func(index int) {
+		parsed := &parsedFiles[index]
+		parsed.fileSet, parsed.astFile, parsed.err = parseGoFile(sourceFiles[index].path, nil)
+	})
 
-		previous, ok := operationsIds[id]
-		if ok {
-			return fmt.Errorf(
-				"duplicated @id annotation '%s' found in '%s', previously declared in: '%s'",
-				id, current, previous)
+	for index, file := range sourceFiles {
+		parsed := parsedFiles[index]
+		if parsed.err != nil {
+			return parsed.err
 		}
 
-		operationsIds[id] = current
+		err := parser.packages.collectAstFile(parsed.fileSet, file.packageDir, file.path, parsed.astFile, flag)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// runWorkers runs a job for the indexes from 0 to count with the bounded pool of parse workers, and waits for them.
+func (parser *Parser) runWorkers(count int, job func(index int)) {
+	workers := parser.parseWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > count {
+		workers = count
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range indexes {
+				job(index)
+			}
+		}()
+	}
+
+	for index := 0; index < count; index++ {
+		indexes <- index
+	}
+
+	close(indexes)
+	wg.Wait()
+}
+
 // Skip returns filepath.SkipDir error if match vendor and hidden folder.
 func (parser *Parser) Skip(path string, f os.FileInfo) error {
 	return walkWith(parser.excludes, parser.ParseVendor)(path, f)
@@ -2065,6 +2947,11 @@ func (parser *Parser) GetAsyncAPI() *asyncSpec.AsyncAPI {
 	return parser.asyncAPI
 }
 
+// GetAsyncAPIOperations returns the AsyncAPI operations keyed by operation ID.
+func (parser *Parser) GetAsyncAPIOperations() map[string]*OperationWithChannel {
+	return parser.asyncOperations
+}
+
 func (parser *Parser) GetParsedSchemas() map[*TypeSpecDef]*Schema {
 	return parser.parsedSchemas
 }
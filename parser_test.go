@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -784,6 +785,73 @@ func TestParser_ParseGeneralAPISecurity(t *testing.T) {
 	})
 }
 
+func TestParser_ParseGeneralAPIAsyncAPISecurity(t *testing.T) {
+	t.Run("SecuritySchemes", func(t *testing.T) {
+		t.Parallel()
+
+		parser := New()
+		err := parseGeneralAPIInfo(parser, []string{
+			"@asyncapi.securitySchemes.scramSha512 scram",
+			"@description SASL/SCRAM credentials",
+			"@asyncapi.securitySchemes.plain plain",
+			"@asyncapi.securitySchemes.userPassword userPassword",
+			"@asyncapi.securitySchemes.apiKey apiKey",
+			"@in user",
+			"@asyncapi.securitySchemes.x509 certs",
+			"@x-rotation 30d",
+			"@asyncapi.securitySchemes.http bearer",
+			"@scheme bearer",
+			"@bearerFormat JWT",
+			"@asyncapi.securitySchemes.httpApiKey httpApiKey",
+			"@in header",
+			"@name X-API-KEY",
+			"@asyncapi.securitySchemes.oauth2.clientCredentials oauth",
+			"@tokenUrl https://example.com/oauth/token",
+			"@scope.events:read Read events",
+			"@asyncapi.securitySchemes.openIdConnect oidc",
+			"@openIdConnectUrl https://example.com/.well-known/openid-configuration",
+			"@securitydefinitions.basic BasicAuth",
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, parser.GetSwagger().SecurityDefinitions, "BasicAuth")
+
+		b, _ := json.Marshal(parser.GetAsyncAPI().Components.SecuritySchemes)
+		expected := `{
+			"scram": {"type": "scramSha512", "description": "SASL/SCRAM credentials"},
+			"plain": {"type": "plain"},
+			"userPassword": {"type": "userPassword"},
+			"apiKey": {"type": "apiKey", "in": "user"},
+			"certs": {"type": "X509", "x-rotation": "30d"},
+			"bearer": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			"httpApiKey": {"type": "httpApiKey", "in": "header", "name": "X-API-KEY"},
+			"oauth": {"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": "https://example.com/oauth/token", "scopes": {"events:read": "Read events"}}}},
+			"oidc": {"type": "openIdConnect", "openIdConnectUrl": "https://example.com/.well-known/openid-configuration"}
+		}`
+		assert.JSONEq(t, expected, string(b))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		parser := New()
+		assert.EqualError(t, parseGeneralAPIInfo(parser, []string{
+			"@asyncapi.securitySchemes.apiKey apiKey"}), "@asyncapi.securitySchemes.apiKey is [@in] required")
+
+		assert.EqualError(t, parseGeneralAPIInfo(parser, []string{
+			"@asyncapi.securitySchemes.apiKey apiKey",
+			"@in header"}), "@asyncapi.securitySchemes.apiKey @in must be one of [user password], got header")
+
+		assert.EqualError(t, parseGeneralAPIInfo(parser, []string{
+			"@asyncapi.securitySchemes.httpApiKey httpApiKey",
+			"@in user",
+			"@name X-API-KEY"}), "@asyncapi.securitySchemes.httpApiKey @in must be one of [header query cookie], got user")
+
+		assert.Error(t, parseGeneralAPIInfo(parser, []string{
+			"@asyncapi.securitySchemes.oauth2.authorizationCode oauth",
+			"@tokenUrl https://example.com/oauth/token"}))
+	})
+}
+
 func TestParser_RefWithOtherPropertiesIsWrappedInAllOf(t *testing.T) {
 	t.Run("Readonly", func(t *testing.T) {
 		src := `
@@ -873,6 +941,124 @@ func Fun()  {
 	})
 }
 
+// parseOutput returns the specs generated from a directory, or the error.
+func parseOutput(t *testing.T, searchDir string, options ...func(*Parser)) string {
+	t.Helper()
+
+	p := New(options...)
+	if err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth); err != nil {
+		return err.Error()
+	}
+
+	swagger, err := json.MarshalIndent(p.swagger, "", "    ")
+	assert.NoError(t, err)
+
+	asyncAPI, err := json.MarshalIndent(p.asyncAPI, "", "    ")
+	assert.NoError(t, err)
+
+	return string(swagger) + "\n" + string(asyncAPI)
+}
+
+func TestParser_ParseWorkers(t *testing.T) {
+	entries, err := os.ReadDir("testdata")
+	assert.NoError(t, err)
+
+	for _, entry := range entries {
+		searchDir := filepath.Join("testdata", entry.Name())
+		if _, err := os.Stat(filepath.Join(searchDir, mainAPIFile)); err != nil {
+			continue
+		}
+
+		t.Run(entry.Name(), func(t *testing.T) {
+			sequential := parseOutput(t, searchDir, SetParseWorkers(1))
+
+			for i := 0; i < 3; i++ {
+				assert.Equal(t, sequential, parseOutput(t, searchDir, SetParseWorkers(8)))
+			}
+		})
+	}
+}
+
+func TestParser_CollectOperationsDeferredAttributes(t *testing.T) {
+	t.Parallel()
+
+	parse := func(src string) error {
+		p := New()
+		_ = p.packages.ParseFile("api", "api/api.go", src, ParseAll)
+
+		_, err := p.packages.ParseTypes()
+		assert.NoError(t, err)
+
+		p.collectOperations()
+
+		return p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	}
+
+	// the invalid @Success is parsed after the @Router, but reported first
+	err := parse(`package api
+
+// @Success 200 {object} Missing
+// @Router /users [get
+func GetUsers() {}
+`)
+
+	var parseError *ParseError
+	if assert.ErrorAs(t, err, &parseError) {
+		assert.Equal(t, 3, parseError.Pos.Line)
+	}
+
+	// the parsing stops at @State
+	err = parse(`package api
+
+// @State beta
+// @Success 200 {object} Missing
+// @Router /users [get]
+func GetUsers() {}
+`)
+	assert.NoError(t, err)
+
+	err = parse(`package api
+
+// @Success 200 {object} Missing
+// @State beta
+// @Router /users [get]
+func GetUsers() {}
+`)
+	if assert.ErrorAs(t, err, &parseError) {
+		assert.Equal(t, 3, parseError.Pos.Line)
+	}
+}
+
+// BenchmarkParser_ParseWorkers measures the parsing of the Go source files of the testdata corpus and the collection
+// of their operations by the number of workers.
+func BenchmarkParser_ParseWorkers(b *testing.B) {
+	var files []goFile
+
+	err := filepath.Walk("testdata", func(path string, f os.FileInfo, err error) error {
+		if err == nil && !f.IsDir() && isParsedGoFile(path) {
+			files = append(files, goFile{packageDir: filepath.ToSlash(filepath.Dir(path)), path: path})
+		}
+
+		return err
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(strconv.Itoa(workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p := New(SetParseWorkers(workers))
+				if err := p.parseFiles(files, ParseAll); err != nil {
+					b.Fatal(err)
+				}
+
+				p.collectOperations()
+			}
+		})
+	}
+}
+
 func TestGetAllGoFileInfo(t *testing.T) {
 	t.Parallel()
 
@@ -3047,7 +3233,7 @@ func FunctionTwo(w http.ResponseWriter, r *http.Request) {
 	assert.NoError(t, err)
 
 	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
-	assert.EqualError(t, err, "route GET /api/endpoint is declared multiple times")
+	assert.EqualError(t, err, "api/api.go:13:4: route GET /api/endpoint is already declared at api/api.go:8:4")
 
 	p = New()
 	err = p.packages.ParseFile("api", "api/api.go", src, ParseAll)
@@ -3057,6 +3243,186 @@ func FunctionTwo(w http.ResponseWriter, r *http.Request) {
 	assert.NoError(t, err)
 }
 
+func TestParser_ParseAsyncAPIDuplicatedOperationID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		comments    string
+		expectedErr string
+	}{
+		{
+			name: "distinct operation ids",
+			comments: `// @asyncapi
+// @operation send orders Order
+func OnOrderCreated() {
+}
+
+// @asyncapi
+// @operation receive orders Order`,
+		},
+		{
+			name: "same function name in another block",
+			comments: `// @asyncapi
+// @operation send orders Order
+func OnOrder() {
+}
+
+// @asyncapi
+// @operation OnOrder receive audits Order`,
+			expectedErr: "api/api.go:14:4: duplicated AsyncAPI operation id 'OnOrder' found in 'receive audits', previously declared in: 'send orders'",
+		},
+		{
+			name: "same operation id in one block",
+			comments: `// @asyncapi
+// @operation send orders Order
+// @operation receive audits Order`,
+			expectedErr: "api/api.go:10:4: duplicated AsyncAPI operation id 'OnOrder' found in 'receive audits', previously declared in: 'send orders'",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src := `
+package api
+
+type Order struct {
+	ID string
+}
+
+` + tt.comments + `
+func OnOrder() {
+}
+`
+			p := New()
+			assert.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+			_, err := p.packages.ParseTypes()
+			assert.NoError(t, err)
+
+			err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				assert.Len(t, p.asyncOperations, 2)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestParser_ParseRouterApiPathParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		comments    string
+		expectedErr string
+	}{
+		{
+			name: "matching placeholders",
+			comments: `// @Param userID path int true "User ID"
+// @Param photoID path int true "Photo ID"
+// @Router /users/{userID}/photos/{photoID} [get]`,
+		},
+		{
+			name: "placeholders in one of the routes",
+			comments: `// @Param userID path int true "User ID"
+// @Router /users/{userID}/photos [get]
+// @Router /photos [get]`,
+		},
+		{
+			name: "gin and regexp placeholders",
+			comments: `// @Param userID path int true "User ID"
+// @Param photoID path int true "Photo ID"
+// @Router /users/:userID/photos/{photoID:.+} [get]`,
+		},
+		{
+			name: "path param not in route",
+			comments: `// @Param userID path int true "User ID"
+// @Param user_id path int true "User ID"
+// @Router /users/{userID} [get]`,
+			expectedErr: "api/api.go:9:4: path parameter 'user_id' isn't a placeholder of GET /users/{userID}",
+		},
+		{
+			name: "placeholder without path param",
+			comments: `// @Param userID path int true "User ID"
+// @Router /users/{userID}/photos/{photoID} [get]`,
+			expectedErr: "api/api.go:9:4: placeholder 'photoID' of route GET /users/{userID}/photos/{photoID} has no path parameter",
+		},
+		{
+			name: "same route with other placeholder names",
+			comments: `// @Param id path int true "User ID"
+// @Router /users/{id} [get]
+func GetUserByID(w http.ResponseWriter, r *http.Request) {
+}
+
+// @Param userID path int true "User ID"
+// @Router /users/{userID} [get]`,
+			expectedErr: "api/api.go:14:4: route GET /users/{userID} is already declared as GET /users/{id} at api/api.go:9:4",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			src := `
+package api
+
+import (
+	"net/http"
+)
+
+` + tt.comments + `
+func GetUser(w http.ResponseWriter, r *http.Request) {
+}
+`
+			p := New(SetStrict(true))
+			assert.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+			err := p.packages.RangeFiles(p.ParseRouterAPIInfo)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+
+			p = New()
+			assert.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+			assert.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+		})
+	}
+}
+
+func TestRoutePlaceholders(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"id"}, routePlaceholders("/pets/{id}"))
+	assert.Equal(t, []string{"id", "photo"}, routePlaceholders("/pets/:id/photos/{photo:.+}"))
+	assert.Equal(t, []string{"name", "ext"}, routePlaceholders("/files/{name}.{ext}"))
+	assert.Empty(t, routePlaceholders("/pets"))
+
+	assert.True(t, routeHasParam("/pets/{id}", "id"))
+	assert.True(t, routeHasParam("/pets/:id/photos", "id"))
+	assert.False(t, routeHasParam("/pets/{petId}", "id"))
+	assert.False(t, routeHasParam("/pets/:idx", "id"))
+}
+
+func TestRouteKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "GET /users/{}/photos/{}", routeKey(RouteProperties{HTTPMethod: "GET", Path: "/users/{id}/photos/:photo"}))
+	assert.Equal(t, routeKey(RouteProperties{HTTPMethod: "GET", Path: "/users/{id}"}),
+		routeKey(RouteProperties{HTTPMethod: "GET", Path: "/users/{userID}"}))
+	assert.NotEqual(t, routeKey(RouteProperties{HTTPMethod: "GET", Path: "/users/{id}"}),
+		routeKey(RouteProperties{HTTPMethod: "PUT", Path: "/users/{id}"}))
+}
+
 func TestApiParseTag(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,254 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	discriminatorAttr  = "@discriminator"
+	implementationAttr = "@implementation"
+)
+
+// discriminatorValueExtension holds the discriminator value of an implementation, Swagger 2.0 uses the definition
+// name otherwise.
+const discriminatorValueExtension = "x-discriminator-value"
+
+// polymorphism is an interface type annotated with @discriminator, like
+//
+//	// @discriminator kind
+//	// @implementation CardPayment card
+//	// @implementation bank.Transfer bank
+//	type Payment interface{}
+//
+// Its definition holds the discriminator property, the definitions of its implementations extend it with allOf.
+type polymorphism struct {
+	// propertyName is the property holding the discriminator value
+	propertyName string
+
+	implementations []*implementation
+}
+
+// implementation is a struct type listed by an @implementation attribute of an interface type.
+type implementation struct {
+	base        *TypeSpecDef
+	typeSpecDef *TypeSpecDef
+
+	// value of the discriminator property, the name of the type by default
+	value string
+}
+
+// collectPolymorphisms collects the interface types annotated with @discriminator and their implementations.
+func (pkgDefs *PackagesDefinitions) collectPolymorphisms() error {
+	pkgPaths := make([]string, 0, len(pkgDefs.packages))
+	for pkgPath := range pkgDefs.packages {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+
+	for _, pkgPath := range pkgPaths {
+		pkg := pkgDefs.packages[pkgPath]
+
+		names := make([]string, 0, len(pkg.TypeDefinitions))
+		for name := range pkg.TypeDefinitions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := pkgDefs.collectPolymorphism(pkg.TypeDefinitions[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectPolymorphism reads the @discriminator and @implementation attributes of a type.
+func (pkgDefs *PackagesDefinitions) collectPolymorphism(typeDef *TypeSpecDef) error {
+	var (
+		poly            polymorphism
+		implementations [][]string
+	)
+
+	for _, commentGroup := range typeSpecDocs(typeDef) {
+		if commentGroup == nil {
+			continue
+		}
+
+		for _, comment := range commentGroup.List {
+			fields := FieldsByAnySpace(strings.TrimSpace(strings.TrimLeft(comment.Text, "/")), 3)
+			if len(fields) == 0 {
+				continue
+			}
+
+			switch strings.ToLower(fields[0]) {
+			case discriminatorAttr:
+				if len(fields) != 2 {
+					return fmt.Errorf("%s: %s must be followed by the name of the discriminator property", typeDef.TypeName(), discriminatorAttr)
+				}
+				poly.propertyName = fields[1]
+			case implementationAttr:
+				if len(fields) < 2 {
+					return fmt.Errorf("%s: %s must be followed by the name of a type", typeDef.TypeName(), implementationAttr)
+				}
+				implementations = append(implementations, fields[1:])
+			}
+		}
+	}
+
+	if poly.propertyName == "" && len(implementations) == 0 {
+		return nil
+	}
+
+	switch {
+	case poly.propertyName == "":
+		return fmt.Errorf("%s: %s requires a %s attribute", typeDef.TypeName(), implementationAttr, discriminatorAttr)
+	case len(implementations) == 0:
+		return fmt.Errorf("%s: %s requires at least one %s attribute", typeDef.TypeName(), discriminatorAttr, implementationAttr)
+	}
+
+	if _, ok := typeDef.TypeSpec.Type.(*ast.InterfaceType); !ok {
+		return fmt.Errorf("%s: %s only applies to an interface type", typeDef.TypeName(), discriminatorAttr)
+	}
+
+	values := make(map[string]string, len(implementations))
+
+	for _, fields := range implementations {
+		implDef := pkgDefs.FindTypeSpec(fields[0], typeDef.File)
+		if implDef == nil {
+			return fmt.Errorf("%s: %s %s: cannot find type definition", typeDef.TypeName(), implementationAttr, fields[0])
+		}
+
+		if _, ok := implDef.TypeSpec.Type.(*ast.StructType); !ok {
+			return fmt.Errorf("%s: %s %s: must be a struct type", typeDef.TypeName(), implementationAttr, fields[0])
+		}
+
+		if implDef.marshaler != nil {
+			return fmt.Errorf("%s: %s %s: must not be marshaled as a string", typeDef.TypeName(), implementationAttr, fields[0])
+		}
+
+		if implDef.implementation != nil {
+			return fmt.Errorf("%s: %s %s: already an implementation of %s", typeDef.TypeName(), implementationAttr, fields[0], implDef.implementation.base.TypeName())
+		}
+
+		value := implDef.Name()
+		if len(fields) > 1 {
+			value = fields[1]
+		}
+
+		if other, ok := values[value]; ok {
+			return fmt.Errorf("%s: %s %s: discriminator value %q already used by %s", typeDef.TypeName(), implementationAttr, fields[0], value, other)
+		}
+		values[value] = fields[0]
+
+		implDef.implementation = &implementation{
+			base:        typeDef,
+			typeSpecDef: implDef,
+			value:       value,
+		}
+		poly.implementations = append(poly.implementations, implDef.implementation)
+	}
+
+	typeDef.polymorphism = &poly
+
+	return nil
+}
+
+// typeSpecDocs returns the doc comments of a type, the one of its declaration unless it's grouped with others.
+func typeSpecDocs(typeDef *TypeSpecDef) []*ast.CommentGroup {
+	docs := []*ast.CommentGroup{typeDef.TypeSpec.Doc}
+
+	if typeDef.File == nil {
+		return docs
+	}
+
+	for _, decl := range typeDef.File.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Lparen.IsValid() {
+			continue
+		}
+
+		for _, astSpec := range genDecl.Specs {
+			if astSpec == typeDef.TypeSpec {
+				return append(docs, genDecl.Doc)
+			}
+		}
+	}
+
+	return docs
+}
+
+// parsePolymorphism returns the definition of a polymorphic interface type: an object holding the discriminator
+// property. The definitions of the implementations are added as well, since nothing references them.
+func (parser *Parser) parsePolymorphism(poly *polymorphism, forAsyncAPI bool) (*spec.Schema, error) {
+	values := make([]interface{}, 0, len(poly.implementations))
+
+	for _, impl := range poly.implementations {
+		_, err := parser.getTypeSpecSchema(impl.typeSpecDef, impl.typeSpecDef.TypeName(), true, forAsyncAPI)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, impl.value)
+	}
+
+	property := PrimitiveSchema(STRING)
+	property.Enum = values
+
+	schema := PrimitiveSchema(OBJECT)
+	schema.Properties = map[string]spec.Schema{poly.propertyName: *property}
+	schema.Required = []string{poly.propertyName}
+	schema.Discriminator = poly.propertyName
+
+	return schema, nil
+}
+
+// parseImplementation returns the definition of an implementation of a polymorphic interface type, extending the
+// definition of the interface with allOf.
+func (parser *Parser) parseImplementation(impl *implementation, definition *spec.Schema, forAsyncAPI bool) (*spec.Schema, error) {
+	baseSchema, err := parser.getTypeSpecSchema(impl.base, impl.base.TypeName(), true, forAsyncAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := PrimitiveSchema(OBJECT)
+	schema.AllOf = []spec.Schema{*baseSchema, *definition}
+	schema.AddExtension(discriminatorValueExtension, impl.value)
+
+	return schema, nil
+}
+
+// markPolymorphismUsage marks the definitions of the implementations of a polymorphic interface type, or the
+// definition of the interface of an implementation, as used by the same spec as the type.
+func (parser *Parser) markPolymorphismUsage(typeSpecDef *TypeSpecDef, forAsyncAPI bool) {
+	var related []*TypeSpecDef
+
+	if typeSpecDef.polymorphism != nil {
+		for _, impl := range typeSpecDef.polymorphism.implementations {
+			related = append(related, impl.typeSpecDef)
+		}
+	}
+
+	if typeSpecDef.implementation != nil {
+		related = append(related, typeSpecDef.implementation.base)
+	}
+
+	for _, relatedDef := range related {
+		schema, ok := parser.parsedSchemas[relatedDef]
+		if !ok {
+			continue
+		}
+
+		if forAsyncAPI {
+			schema.UsedForAsyncAPI = true
+		} else {
+			schema.UsedForOpenAPI = true
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package swag
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolymorphism(t *testing.T) {
+	searchDir := "testdata/polymorphism"
+	expected, err := os.ReadFile(filepath.Join(searchDir, "expected.json"))
+	require.NoError(t, err)
+
+	p := New()
+	require.NoError(t, p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth))
+
+	b, err := json.MarshalIndent(p.swagger, "", "    ")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(b))
+}
+
+func TestParsePolymorphism_ImplementationFirst(t *testing.T) {
+	src := `
+package api
+
+// @discriminator type
+// @implementation Cat
+// @implementation Dog dog
+type Pet interface{}
+
+type Cat struct {
+	Lives int
+}
+
+type Dog struct {
+	Friend Pet
+}
+
+// @Success 200 {object} Dog
+// @Router /dog [get]
+func GetDog() {
+}
+`
+	p := New()
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	expected := `{
+   "api.Cat": {
+      "type": "object",
+      "allOf": [
+         {
+            "$ref": "#/definitions/api.Pet"
+         },
+         {
+            "type": "object",
+            "properties": {
+               "lives": {
+                  "type": "integer"
+               }
+            }
+         }
+      ],
+      "x-discriminator-value": "Cat"
+   },
+   "api.Dog": {
+      "type": "object",
+      "allOf": [
+         {
+            "$ref": "#/definitions/api.Pet"
+         },
+         {
+            "type": "object",
+            "properties": {
+               "friend": {
+                  "$ref": "#/definitions/api.Pet"
+               }
+            }
+         }
+      ],
+      "x-discriminator-value": "dog"
+   },
+   "api.Pet": {
+      "type": "object",
+      "required": [
+         "type"
+      ],
+      "properties": {
+         "type": {
+            "type": "string",
+            "enum": [
+               "Cat",
+               "dog"
+            ]
+         }
+      },
+      "discriminator": "type"
+   }
+}`
+	out, err := json.MarshalIndent(p.swagger.Definitions, "", "   ")
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+}
+
+func TestCollectPolymorphisms(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "grouped declaration",
+			src: `
+// @discriminator kind
+type (
+	// @discriminator type
+	// @implementation Cat
+	Pet interface{}
+
+	Cat struct{}
+)`,
+		},
+		{
+			name: "implementation without discriminator",
+			src: `
+// @implementation Cat
+type Pet interface{}
+
+type Cat struct{}`,
+			err: "api.Pet: @implementation requires a @discriminator attribute",
+		},
+		{
+			name: "discriminator without implementation",
+			src: `
+// @discriminator type
+type Pet interface{}`,
+			err: "api.Pet: @discriminator requires at least one @implementation attribute",
+		},
+		{
+			name: "not an interface",
+			src: `
+// @discriminator type
+// @implementation Cat
+type Pet struct{}
+
+type Cat struct{}`,
+			err: "api.Pet: @discriminator only applies to an interface type",
+		},
+		{
+			name: "unknown type",
+			src: `
+// @discriminator type
+// @implementation Cat
+type Pet interface{}`,
+			err: "api.Pet: @implementation Cat: cannot find type definition",
+		},
+		{
+			name: "not a struct",
+			src: `
+// @discriminator type
+// @implementation Cat
+type Pet interface{}
+
+type Cat string`,
+			err: "api.Pet: @implementation Cat: must be a struct type",
+		},
+		{
+			name: "duplicated value",
+			src: `
+// @discriminator type
+// @implementation Cat pet
+// @implementation Dog pet
+type Pet interface{}
+
+type Cat struct{}
+
+type Dog struct{}`,
+			err: `api.Pet: @implementation Dog: discriminator value "pet" already used by Cat`,
+		},
+		{
+			name: "several interfaces",
+			src: `
+// @discriminator type
+// @implementation Cat
+type Animal interface{}
+
+// @discriminator type
+// @implementation Cat
+type Pet interface{}
+
+type Cat struct{}`,
+			err: "api.Pet: @implementation Cat: already an implementation of api.Animal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			require.NoError(t, p.packages.ParseFile("api", "api/api.go", "package api\n"+tt.src, ParseAll))
+
+			_, err := p.packages.ParseTypes()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
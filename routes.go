@@ -0,0 +1,673 @@
+package swag
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// routeHandler identifies the handler function of a registered route, or the method of a receiver type.
+type routeHandler struct {
+	// pkgPath is the package of a function or of the receiver type of a method, empty for a method whose
+	// receiver isn't resolved
+	pkgPath  string
+	receiver string
+	name     string
+}
+
+// SetInferRoutes sets whether the routes registered with gin, echo, chi or net/http routers are bound to the
+// handlers whose comments don't have @Router.
+func SetInferRoutes(inferRoutes bool) func(*Parser) {
+	return func(p *Parser) {
+		p.inferRoutes = inferRoutes
+	}
+}
+
+// collectRegisteredRoutes collects the routes registered in a file, by handler.
+func (parser *Parser) collectRegisteredRoutes(fileInfo *AstFileInfo) error {
+	if (fileInfo.ParseFlag & ParseOperations) == ParseNone {
+		return nil
+	}
+
+	collector := &routeCollector{
+		parser:         parser,
+		fileInfo:       fileInfo,
+		prefixes:       make(map[interface{}]string),
+		unknownRouters: make(map[interface{}]bool),
+	}
+
+	ast.Inspect(fileInfo.File, collector.visit)
+
+	for _, decl := range fileInfo.File.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv != nil {
+			parser.methodNames[funcDecl.Name.Name]++
+		}
+	}
+
+	return nil
+}
+
+// routeCollector collects the route registrations of a file and follows the prefixes of the router groups, as
+// gin and echo Group or chi Route, assigned to a variable or passed to a function literal. The prefix of a router
+// received from elsewhere, like a parameter of a named function or a struct field, is unknown.
+type routeCollector struct {
+	parser   *Parser
+	fileInfo *AstFileInfo
+	prefixes map[interface{}]string
+	// unknownRouters are the groups of the routers whose prefix is unknown
+	unknownRouters map[interface{}]bool
+}
+
+func (collector *routeCollector) visit(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.AssignStmt:
+		for i, value := range node.Rhs {
+			if prefix, known, ok := collector.groupPrefix(value); ok && i < len(node.Lhs) {
+				if ident, ok := node.Lhs[i].(*ast.Ident); ok {
+					collector.setPrefix(ident, prefix, known)
+				}
+			}
+		}
+	case *ast.ValueSpec:
+		for i, value := range node.Values {
+			if prefix, known, ok := collector.groupPrefix(value); ok && i < len(node.Names) {
+				collector.setPrefix(node.Names[i], prefix, known)
+			}
+		}
+	case *ast.CallExpr:
+		collector.collectCall(node)
+	}
+
+	return true
+}
+
+func (collector *routeCollector) collectCall(call *ast.CallExpr) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return
+	}
+
+	name := selector.Sel.Name
+	prefix, known := collector.prefix(selector.X)
+
+	switch {
+	case name == "Route" && len(call.Args) == 2:
+		// chi: r.Route("/users", func(r chi.Router) {...})
+		if path, ok := stringLiteral(call.Args[0]); ok {
+			collector.setFuncLitPrefix(call.Args[1], joinRoutePath(prefix, path), known)
+		}
+	case name == "Group" && len(call.Args) == 1:
+		// chi: r.Group(func(r chi.Router) {...})
+		collector.setFuncLitPrefix(call.Args[0], prefix, known)
+	case routerMethod(name) != "" && len(call.Args) >= 2:
+		// gin and echo: r.GET("/users/:id", handler), chi: r.Get("/users/{id}", handler)
+		if path, ok := stringLiteral(call.Args[0]); ok && isRouterPath(path) {
+			collector.register(call, routerMethod(name), joinRoutePath(prefix, path), known, call.Args[1:])
+		}
+	case (name == "Handle" || name == "Method" || name == "MethodFunc" || name == "Add") && len(call.Args) >= 3:
+		// gin: r.Handle("GET", "/users/:id", handler), chi: r.Method("GET", "/users/{id}", handler),
+		// echo: e.Add("GET", "/users/:id", handler)
+		method, ok := stringLiteral(call.Args[0])
+		if _, valid := allMethod[strings.ToUpper(method)]; !ok || !valid {
+			return
+		}
+
+		if path, ok := stringLiteral(call.Args[1]); ok && isRouterPath(path) {
+			collector.register(call, strings.ToUpper(method), joinRoutePath(prefix, path), known, call.Args[2:])
+		}
+	case (name == "Handle" || name == "HandleFunc") && len(call.Args) == 2:
+		// net/http: http.HandleFunc("GET /users/{id}", handler)
+		pattern, ok := stringLiteral(call.Args[0])
+		if !ok {
+			return
+		}
+
+		if method, path := splitServeMuxPattern(pattern); method != "" {
+			collector.register(call, method, joinRoutePath(prefix, path), known, call.Args[1:])
+		}
+	}
+}
+
+// register binds a route to the handlers which may be the arguments of its registration, handlers without
+// annotations, like middlewares, are never bound. A route registered on a router whose prefix is unknown is bound
+// without the prefix, with a warning.
+func (collector *routeCollector) register(call *ast.CallExpr, method, path string, known bool, args []ast.Expr) {
+	route := RouteProperties{HTTPMethod: method, Path: openAPIRoutePath(path)}
+
+	if !known {
+		collector.parser.debug.Printf("warning: %s: the prefix of the router of %s %s is unknown, the route may miss the prefix of its group\n",
+			collector.position(call), route.HTTPMethod, route.Path)
+	}
+
+	for _, arg := range args {
+		for _, handler := range collector.handlers(arg) {
+			if !containsRoute(collector.parser.registeredRoutes[handler], route) {
+				collector.parser.registeredRoutes[handler] = append(collector.parser.registeredRoutes[handler], route)
+			}
+		}
+	}
+}
+
+// handlers returns the functions an argument of a registration may refer to: a function, a method value, or the
+// function wrapped by a conversion like http.HandlerFunc(handler) or returned by a factory like handler().
+func (collector *routeCollector) handlers(expr ast.Expr) []routeHandler {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return []routeHandler{{pkgPath: collector.fileInfo.PackagePath, name: expr.Name}}
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok && pkg.Obj == nil {
+			if pkgPath := collector.parser.importPath(pkg.Name, collector.fileInfo.File); pkgPath != "" {
+				return []routeHandler{{pkgPath: pkgPath, name: expr.Sel.Name}}
+			}
+		}
+
+		pkgPath, receiver := collector.parser.receiverType(expr.X, collector.fileInfo.File, collector.fileInfo.PackagePath)
+		if receiver == "" {
+			collector.parser.debug.Printf("warning: cannot resolve the receiver of the handler %s", expr.Sel.Name)
+
+			return []routeHandler{{name: expr.Sel.Name}}
+		}
+
+		return []routeHandler{{pkgPath: pkgPath, receiver: receiver, name: expr.Sel.Name}}
+	case *ast.ParenExpr:
+		return collector.handlers(expr.X)
+	case *ast.CallExpr:
+		handlers := collector.handlers(expr.Fun)
+		if len(expr.Args) == 1 {
+			handlers = append(handlers, collector.handlers(expr.Args[0])...)
+		}
+
+		return handlers
+	}
+
+	return nil
+}
+
+// receiverType returns the package and the name of the type of the receiver of a method value, like h in h.Get,
+// from the declaration of a variable, a parameter or a struct field, empty when it isn't resolved.
+func (parser *Parser) receiverType(expr ast.Expr, file *ast.File, pkgPath string) (string, string) {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return parser.receiverType(expr.X, file, pkgPath)
+	case *ast.Ident:
+		if expr.Obj == nil {
+			// a variable declared in another file of the package
+			return parser.packageVarType(pkgPath, expr.Name)
+		}
+
+		switch decl := expr.Obj.Decl.(type) {
+		case *ast.ValueSpec:
+			return parser.valueSpecType(decl, expr.Name, file, pkgPath)
+		case *ast.AssignStmt:
+			if len(decl.Lhs) != len(decl.Rhs) {
+				return "", ""
+			}
+
+			for i, lhs := range decl.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == expr.Name {
+					return parser.valueType(decl.Rhs[i], file, pkgPath)
+				}
+			}
+		case *ast.Field:
+			return parser.typeName(decl.Type, file, pkgPath)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok && pkg.Obj == nil {
+			if importPath := parser.importPath(pkg.Name, file); importPath != "" {
+				return parser.packageVarType(importPath, expr.Sel.Name)
+			}
+		}
+
+		structPkgPath, structName := parser.receiverType(expr.X, file, pkgPath)
+
+		return parser.fieldType(structPkgPath, structName, expr.Sel.Name)
+	}
+
+	return "", ""
+}
+
+// valueType returns the package and the name of the type of a value: a composite literal or its address, new(T),
+// the result of a function or another variable.
+func (parser *Parser) valueType(expr ast.Expr, file *ast.File, pkgPath string) (string, string) {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return parser.valueType(expr.X, file, pkgPath)
+	case *ast.UnaryExpr:
+		if expr.Op == token.AND {
+			return parser.valueType(expr.X, file, pkgPath)
+		}
+	case *ast.CompositeLit:
+		return parser.typeName(expr.Type, file, pkgPath)
+	case *ast.CallExpr:
+		if ident, ok := expr.Fun.(*ast.Ident); ok && ident.Name == "new" && ident.Obj == nil && len(expr.Args) == 1 {
+			return parser.typeName(expr.Args[0], file, pkgPath)
+		}
+
+		return parser.resultType(expr.Fun, file, pkgPath)
+	default:
+		return parser.receiverType(expr, file, pkgPath)
+	}
+
+	return "", ""
+}
+
+// resultType returns the package and the name of the type of the first result of a function, like a constructor.
+func (parser *Parser) resultType(fun ast.Expr, file *ast.File, pkgPath string) (string, string) {
+	name := ""
+
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		pkg, ok := fun.X.(*ast.Ident)
+		if !ok || pkg.Obj != nil {
+			return "", ""
+		}
+
+		name, pkgPath = fun.Sel.Name, parser.importPath(pkg.Name, file)
+	}
+
+	pkg := parser.packages.packages[pkgPath]
+	if name == "" || pkg == nil {
+		return "", ""
+	}
+
+	for _, pkgFile := range pkg.Files {
+		for _, decl := range pkgFile.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil || funcDecl.Name.Name != name {
+				continue
+			}
+
+			if funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) == 0 {
+				return "", ""
+			}
+
+			return parser.typeName(funcDecl.Type.Results.List[0].Type, pkgFile, pkgPath)
+		}
+	}
+
+	return "", ""
+}
+
+// packageVarType returns the package and the name of the type of a package variable.
+func (parser *Parser) packageVarType(pkgPath, name string) (string, string) {
+	pkg := parser.packages.packages[pkgPath]
+	if pkg == nil {
+		return "", ""
+	}
+
+	for _, pkgFile := range pkg.Files {
+		for _, decl := range pkgFile.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				if pkgPath, typeName := parser.valueSpecType(spec.(*ast.ValueSpec), name, pkgFile, pkgPath); typeName != "" {
+					return pkgPath, typeName
+				}
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// valueSpecType returns the package and the name of the type of a variable of a declaration.
+func (parser *Parser) valueSpecType(spec *ast.ValueSpec, name string, file *ast.File, pkgPath string) (string, string) {
+	for i, ident := range spec.Names {
+		if ident.Name != name {
+			continue
+		}
+
+		if spec.Type != nil {
+			return parser.typeName(spec.Type, file, pkgPath)
+		}
+
+		if len(spec.Values) == len(spec.Names) {
+			return parser.valueType(spec.Values[i], file, pkgPath)
+		}
+	}
+
+	return "", ""
+}
+
+// fieldType returns the package and the name of the type of a field of a struct type.
+func (parser *Parser) fieldType(pkgPath, structName, name string) (string, string) {
+	typeSpec := parser.packages.findTypeSpec(pkgPath, structName)
+	if typeSpec == nil {
+		return "", ""
+	}
+
+	structType, ok := typeSpec.TypeSpec.Type.(*ast.StructType)
+	if !ok {
+		return "", ""
+	}
+
+	for _, field := range structType.Fields.List {
+		for _, ident := range field.Names {
+			if ident.Name == name {
+				return parser.typeName(field.Type, typeSpec.File, pkgPath)
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// typeName returns the package and the name of a named type, like T, *T or pkg.T.
+func (parser *Parser) typeName(expr ast.Expr, file *ast.File, pkgPath string) (string, string) {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return parser.typeName(expr.X, file, pkgPath)
+	case *ast.StarExpr:
+		return parser.typeName(expr.X, file, pkgPath)
+	case *ast.IndexExpr:
+		return parser.typeName(expr.X, file, pkgPath)
+	case *ast.IndexListExpr:
+		return parser.typeName(expr.X, file, pkgPath)
+	case *ast.Ident:
+		return pkgPath, expr.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok {
+			if importPath := parser.importPath(pkg.Name, file); importPath != "" {
+				return importPath, expr.Sel.Name
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// importPath returns the path of a package imported by a file.
+func (parser *Parser) importPath(name string, file *ast.File) string {
+	pkgPaths, externalPkgPaths := parser.packages.findPackagePathFromImports(name, file)
+	if pkgPaths = append(pkgPaths, externalPkgPaths...); len(pkgPaths) > 0 {
+		return pkgPaths[0]
+	}
+
+	return ""
+}
+
+// groupPrefix returns the prefix of a gin or echo router group, as r.Group("/users"), and whether the prefix of
+// its router is known.
+func (collector *routeCollector) groupPrefix(expr ast.Expr) (string, bool, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false, false
+	}
+
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Group" {
+		return "", false, false
+	}
+
+	path, ok := stringLiteral(call.Args[0])
+	if !ok {
+		return "", false, false
+	}
+
+	prefix, known := collector.prefix(selector.X)
+
+	return joinRoutePath(prefix, path), known, true
+}
+
+// prefix returns the prefix of a router, empty when it isn't a group, and whether it is known. The prefix of a
+// router declared in the file is known, the one of a parameter, of a struct field or of a package variable declared
+// in another file isn't.
+func (collector *routeCollector) prefix(expr ast.Expr) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if prefix, ok := collector.prefixes[identKey(expr)]; ok {
+			return prefix, true
+		}
+
+		if collector.unknownRouters[identKey(expr)] {
+			return "", false
+		}
+
+		if expr.Obj == nil {
+			// a package, like http in http.HandleFunc, or a package variable declared in another file
+			return "", collector.parser.importPath(expr.Name, collector.fileInfo.File) != ""
+		}
+
+		_, isField := expr.Obj.Decl.(*ast.Field)
+
+		return "", !isField
+	case *ast.ParenExpr:
+		return collector.prefix(expr.X)
+	case *ast.CallExpr:
+		prefix, known, _ := collector.groupPrefix(expr)
+
+		return prefix, known
+	}
+
+	return "", false
+}
+
+func (collector *routeCollector) setPrefix(ident *ast.Ident, prefix string, known bool) {
+	if known {
+		collector.prefixes[identKey(ident)] = prefix
+	} else {
+		collector.unknownRouters[identKey(ident)] = true
+	}
+}
+
+func (collector *routeCollector) setFuncLitPrefix(expr ast.Expr, prefix string, known bool) {
+	funcLit, ok := expr.(*ast.FuncLit)
+	if !ok || len(funcLit.Type.Params.List) == 0 || len(funcLit.Type.Params.List[0].Names) == 0 {
+		return
+	}
+
+	collector.setPrefix(funcLit.Type.Params.List[0].Names[0], prefix, known)
+}
+
+// position returns the position of a node of the file.
+func (collector *routeCollector) position(node ast.Node) token.Position {
+	if collector.fileInfo.FileSet == nil {
+		return token.Position{Filename: collector.fileInfo.Path}
+	}
+
+	return collector.fileInfo.FileSet.Position(node.Pos())
+}
+
+// identKey returns the object of an identifier, resolved in its file, or its name.
+func identKey(ident *ast.Ident) interface{} {
+	if ident.Obj != nil {
+		return ident.Obj
+	}
+
+	return ident.Name
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+
+	value, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return "", false
+	}
+
+	return value, true
+}
+
+// isRouterPath reports whether a string is a path relative to a router, empty for the root of a group.
+func isRouterPath(path string) bool {
+	return path == "" || strings.HasPrefix(path, "/")
+}
+
+// routerMethod returns the HTTP method of a router method named like GET (gin, echo) or Get (chi).
+func routerMethod(name string) string {
+	method := strings.ToUpper(name)
+	if _, ok := allMethod[method]; !ok {
+		return ""
+	}
+
+	if name != method && name != method[:1]+strings.ToLower(method[1:]) {
+		return ""
+	}
+
+	return method
+}
+
+// splitServeMuxPattern returns the method and the path of a net/http pattern like "GET example.com/users/{id}",
+// the method is empty when the pattern matches every method.
+func splitServeMuxPattern(pattern string) (string, string) {
+	fields := strings.Fields(pattern)
+	if len(fields) != 2 {
+		return "", ""
+	}
+
+	if _, ok := allMethod[fields[0]]; !ok {
+		return "", ""
+	}
+
+	path := fields[1]
+	if index := strings.Index(path, "/"); index > 0 {
+		// host
+		path = path[index:]
+	}
+
+	return fields[0], path
+}
+
+func joinRoutePath(prefix, path string) string {
+	joined := "/" + prefix + "/" + path
+	for strings.Contains(joined, "//") {
+		joined = strings.ReplaceAll(joined, "//", "/")
+	}
+
+	if len(joined) > 1 && !strings.HasSuffix(path, "/") {
+		joined = strings.TrimSuffix(joined, "/")
+	}
+
+	return joined
+}
+
+// routerPathParamPattern matches the path parameters of a router: :name and *name (gin, echo), {name:pattern}
+// (chi) and {name...} (net/http).
+var routerPathParamPattern = regexp.MustCompile(`/[:*]([^/]+)|\{([^{}:.]+)(?::[^{}]*|\.\.\.)?\}`)
+
+// openAPIRoutePath returns a router path with {name} path parameters, as in @Router.
+func openAPIRoutePath(path string) string {
+	path = strings.ReplaceAll(path, "{$}", "")
+
+	return routerPathParamPattern.ReplaceAllStringFunc(path, func(param string) string {
+		matches := routerPathParamPattern.FindStringSubmatch(param)
+		if matches[1] != "" {
+			return "/{" + matches[1] + "}"
+		}
+
+		return "{" + matches[2] + "}"
+	})
+}
+
+func containsRoute(routes []RouteProperties, route RouteProperties) bool {
+	for _, existing := range routes {
+		if existing.HTTPMethod == route.HTTPMethod && existing.Path == route.Path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// registeredRoutesOf returns the routes registered with the function of a declaration, relative to @BasePath.
+func (parser *Parser) registeredRoutesOf(fileInfo *AstFileInfo, decl ast.Decl) []RouteProperties {
+	if !parser.inferRoutes {
+		return nil
+	}
+
+	var handler routeHandler
+
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		handler = routeHandler{pkgPath: fileInfo.PackagePath, receiver: receiverTypeName(decl), name: decl.Name.Name}
+	case *ast.GenDecl:
+		valueSpec, ok := decl.Specs[0].(*ast.ValueSpec)
+		if !ok || len(valueSpec.Names) == 0 {
+			return nil
+		}
+
+		handler = routeHandler{pkgPath: fileInfo.PackagePath, name: valueSpec.Names[0].Name}
+	}
+
+	basePath := strings.TrimSuffix(parser.swagger.BasePath, "/")
+
+	registered := parser.registeredRoutes[handler]
+	if handler.receiver != "" && parser.methodNames[handler.name] == 1 {
+		// a method value whose receiver isn't resolved is bound only when no other method has its name
+		registered = append(registered[:len(registered):len(registered)], parser.registeredRoutes[routeHandler{name: handler.name}]...)
+	}
+
+	var routes []RouteProperties
+
+	for _, route := range registered {
+		if basePath != "" && (route.Path == basePath || strings.HasPrefix(route.Path, basePath+"/")) {
+			route.Path = strings.TrimPrefix(route.Path, basePath)
+			if route.Path == "" {
+				route.Path = "/"
+			}
+		}
+
+		if !containsRoute(routes, route) {
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
+}
+
+// bindRegisteredRoutes sets the registered routes of an operation without @Router, or checks that they match its
+// @Router comments.
+func (parser *Parser) bindRegisteredRoutes(operation *Operation, routes []RouteProperties) error {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	if len(operation.positions.Routers) == 0 {
+		// a documented function only, not any function registered as handler
+		if operation.positions.Block.Filename != "" {
+			operation.RouterProperties = routes
+		}
+
+		return nil
+	}
+
+	if sameRoutes(operation.RouterProperties, routes) {
+		return nil
+	}
+
+	return parser.reportRule(RuleRouteMismatch, operation.positions.router(0),
+		"@Router %s doesn't match the registered routes %s", formatRoutes(operation.RouterProperties), formatRoutes(routes))
+}
+
+func sameRoutes(routes, others []RouteProperties) bool {
+	if len(routes) != len(others) {
+		return false
+	}
+
+	for _, route := range routes {
+		if !containsRoute(others, route) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func formatRoutes(routes []RouteProperties) string {
+	formatted := make([]string, 0, len(routes))
+	for _, route := range routes {
+		formatted = append(formatted, route.HTTPMethod+" "+route.Path)
+	}
+
+	return strings.Join(formatted, ", ")
+}
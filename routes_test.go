@@ -0,0 +1,162 @@
+package swag
+
+import (
+	"bytes"
+	"log"
+	"sort"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pathMethods(paths *spec.Paths) map[string][]string {
+	methods := make(map[string][]string)
+
+	for path, item := range paths.Paths {
+		for method, operation := range map[string]*spec.Operation{
+			"GET":    item.Get,
+			"PUT":    item.Put,
+			"POST":   item.Post,
+			"DELETE": item.Delete,
+		} {
+			if operation != nil {
+				methods[path] = append(methods[path], method)
+			}
+		}
+
+		sort.Strings(methods[path])
+	}
+
+	return methods
+}
+
+func TestParser_InferRoutes(t *testing.T) {
+	t.Parallel()
+
+	p := New(SetInferRoutes(true))
+	require.NoError(t, p.ParseAPI("testdata/infer_routes", mainAPIFile, defaultParseDepth))
+
+	assert.Equal(t, map[string][]string{
+		"/users":            {"POST"},
+		"/users/{id}":       {"GET"},
+		"/pets/{petID}":     {"DELETE", "GET", "PUT"},
+		"/files/{path}":     {"GET"},
+		"/orders/{orderID}": {"GET"},
+	}, pathMethods(p.swagger.Paths))
+
+	assert.Equal(t, "Get a pet", p.swagger.Paths.Paths["/pets/{petID}"].Get.Summary)
+	assert.Equal(t, "petID", p.swagger.Paths.Paths["/pets/{petID}"].Put.Parameters[0].Name)
+}
+
+func TestParser_InferRoutesUnknownPrefix(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	p := New(SetInferRoutes(true), SetDebugger(log.New(&output, "", 0)))
+	require.NoError(t, p.ParseAPI("testdata/infer_routes", mainAPIFile, defaultParseDepth))
+
+	assert.Contains(t, output.String(), "warning: testdata/infer_routes/main.go:39:2: the prefix of the router of GET /health is unknown")
+	assert.NotContains(t, output.String(), "GET /users")
+}
+
+func TestParser_InferRoutesDisabled(t *testing.T) {
+	t.Parallel()
+
+	p := New()
+	require.NoError(t, p.ParseAPI("testdata/infer_routes", mainAPIFile, defaultParseDepth))
+
+	assert.Equal(t, map[string][]string{
+		"/pets/{petID}":     {"DELETE"},
+		"/orders/{orderID}": {"GET"},
+	}, pathMethods(p.swagger.Paths))
+}
+
+func TestParser_InferRoutesReceivers(t *testing.T) {
+	t.Parallel()
+
+	p := New(SetInferRoutes(true), SetStrict(true))
+	require.NoError(t, p.ParseAPI("testdata/infer_routes_receivers", mainAPIFile, defaultParseDepth))
+
+	assert.Equal(t, map[string][]string{
+		"/users/{id}":  {"GET"},
+		"/orders/{id}": {"GET"},
+		"/items/{id}":  {"GET"},
+	}, pathMethods(p.swagger.Paths))
+
+	assert.Equal(t, "Get a user", p.swagger.Paths.Paths["/users/{id}"].Get.Summary)
+	assert.Equal(t, "Get an order", p.swagger.Paths.Paths["/orders/{id}"].Get.Summary)
+	assert.Equal(t, "Get an item", p.swagger.Paths.Paths["/items/{id}"].Get.Summary)
+}
+
+func TestParser_InferRoutesMismatch(t *testing.T) {
+	t.Parallel()
+
+	err := New(SetInferRoutes(true), SetStrict(true)).ParseAPI("testdata/infer_routes", mainAPIFile, defaultParseDepth)
+	assert.EqualError(t, err, "testdata/infer_routes/api/api.go:67:4: @Router GET /orders/{orderID} doesn't match the registered routes GET /orders/{id}")
+
+	p := New(SetInferRoutes(true), SetLint(true))
+	require.NoError(t, p.ParseAPI("testdata/infer_routes", mainAPIFile, defaultParseDepth))
+
+	diagnostics := p.Diagnostics()
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, RuleRouteMismatch, diagnostics[0].Rule)
+	assert.Equal(t, 67, diagnostics[0].Position.Line)
+}
+
+func TestOpenAPIRoutePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/users/:id", expected: "/users/{id}"},
+		{path: "/users/:id/photos/:photoID", expected: "/users/{id}/photos/{photoID}"},
+		{path: "/static/*filepath", expected: "/static/{filepath}"},
+		{path: "/users/{id:[0-9]+}", expected: "/users/{id}"},
+		{path: "/files/{path...}", expected: "/files/{path}"},
+		{path: "/users/{$}", expected: "/users/"},
+		{path: "/users/{id}", expected: "/users/{id}"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, openAPIRoutePath(tt.path), tt.path)
+	}
+}
+
+func TestJoinRoutePath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/", joinRoutePath("", ""))
+	assert.Equal(t, "/api/users", joinRoutePath("/api", "/users"))
+	assert.Equal(t, "/api/users", joinRoutePath("/api/", "/users"))
+	assert.Equal(t, "/api", joinRoutePath("/api", ""))
+	assert.Equal(t, "/api/", joinRoutePath("/api", "/"))
+}
+
+func TestSplitServeMuxPattern(t *testing.T) {
+	t.Parallel()
+
+	method, path := splitServeMuxPattern("GET /users/{id}")
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/users/{id}", path)
+
+	method, path = splitServeMuxPattern("POST example.com/users")
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, "/users", path)
+
+	method, _ = splitServeMuxPattern("/users")
+	assert.Empty(t, method)
+}
+
+func TestRouterMethod(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "GET", routerMethod("GET"))
+	assert.Equal(t, "DELETE", routerMethod("Delete"))
+	assert.Empty(t, routerMethod("delete"))
+	assert.Empty(t, routerMethod("Group"))
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yalochat/swag/testdata/enums_packages/flags"
+	"github.com/yalochat/swag/testdata/enums_packages/level"
+	"github.com/yalochat/swag/testdata/enums_packages/status"
+)
+
+type Account struct {
+	Status      status.Status    `json:"status"`
+	Permissions flags.Permission `json:"permissions"`
+	Quota       flags.Size       `json:"quota"`
+	LogLevel    level.Level      `json:"logLevel"`
+	Priority    level.Priority   `json:"priority"`
+}
+
+// GetAccount returns an account.
+//
+//	@Summary	Get an account
+//	@Success	200	{object}	api.Account
+//	@Router		/accounts [get]
+func GetAccount(w http.ResponseWriter, r *http.Request) {}
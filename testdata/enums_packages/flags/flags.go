@@ -0,0 +1,20 @@
+package flags
+
+// Permission is a set of bit flags.
+type Permission uint8
+
+const (
+	_       Permission = 1 << iota
+	Read               // Read the files
+	Write              // Write the files
+	Execute            // Execute the files
+)
+
+// Size is a size in bytes.
+type Size int64
+
+const (
+	KB Size = 1 << (10 * (iota + 1))
+	MB
+	GB
+)
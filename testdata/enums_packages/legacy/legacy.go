@@ -0,0 +1,9 @@
+package legacy
+
+import "github.com/yalochat/swag/testdata/enums_packages/status"
+
+const (
+	// Archived is declared in another package than its type.
+	Archived status.Status = "archived" // Archived account
+	Deleted                = status.Status("deleted")
+)
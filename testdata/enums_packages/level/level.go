@@ -0,0 +1,25 @@
+package level
+
+// Level of the logs.
+type Level string
+
+const (
+	Debug Level = "debug" // Verbose logs
+	Info  Level = "info"
+	Warn  Level = "warn" // Warnings
+	// Trace isn't one of the EnumValues
+	Trace Level = "trace"
+)
+
+// EnumValues returns the values of the levels.
+func (Level) EnumValues() []Level {
+	return []Level{Debug, Info, Warn, "error"}
+}
+
+// Priority of the jobs.
+type Priority int
+
+// EnumValues returns the values of the priorities.
+func (p Priority) EnumValues() []Priority {
+	return []Priority{1, 2, 3}
+}
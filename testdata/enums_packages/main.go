@@ -0,0 +1,14 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/yalochat/swag/testdata/enums_packages/api"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @description Enums declared across packages, with shifted iota and EnumValues methods.
+func main() {
+	http.HandleFunc("/accounts", api.GetAccount)
+}
@@ -0,0 +1,9 @@
+package status
+
+// Status of an account.
+type Status string
+
+const (
+	Active   Status = "active"   // Active account
+	Inactive Status = "inactive" // Inactive account
+)
@@ -0,0 +1,4 @@
+package status
+
+// Suspended is declared in another file of the package.
+const Suspended Status = "suspended" // Suspended account
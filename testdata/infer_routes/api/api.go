@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Pet struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetUser
+// @Summary Get a user
+// @Param id path int true "User ID"
+// @Success 200 {object} User
+func GetUser(w http.ResponseWriter, r *http.Request) {
+}
+
+// CreateUser
+// @Summary Create a user
+// @Param user body User true "User"
+// @Success 201 {object} User
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+}
+
+type PetHandler struct{}
+
+// GetPet
+// @Summary Get a pet
+// @Param petID path int true "Pet ID"
+// @Success 200 {object} Pet
+func (h *PetHandler) GetPet(w http.ResponseWriter, r *http.Request) {
+}
+
+// UpdatePet
+// @Summary Update a pet
+// @Param petID path int true "Pet ID"
+// @Param pet body Pet true "Pet"
+// @Success 200 {object} Pet
+func (h *PetHandler) UpdatePet(w http.ResponseWriter, r *http.Request) {
+}
+
+// DeletePet
+// @Summary Delete a pet
+// @Param petID path int true "Pet ID"
+// @Success 204
+// @Router /pets/{petID} [delete]
+func (h *PetHandler) DeletePet(w http.ResponseWriter, r *http.Request) {
+}
+
+// GetFile
+// @Summary Download a file
+// @Param path path string true "File path"
+// @Success 200 {file} file
+func GetFile(w http.ResponseWriter, r *http.Request) {
+}
+
+// GetOrder
+// @Summary Get an order
+// @Param orderID path int true "Order ID"
+// @Success 200
+// @Router /orders/{orderID} [get]
+func GetOrder(w http.ResponseWriter, r *http.Request) {
+}
+
+// GetVersion returns the version of the API, it isn't documented.
+func GetVersion(w http.ResponseWriter, r *http.Request) {
+}
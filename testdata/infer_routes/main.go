@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/yalochat/swag/testdata/infer_routes/api"
+	"github.com/yalochat/swag/testdata/infer_routes/router"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /api/v1
+func main() {
+	r := router.New()
+
+	v1 := r.Group("/api/v1")
+	{
+		users := v1.Group("/users")
+		users.GET("/:id", api.GetUser)
+		users.POST("", router.Logger(), api.CreateUser)
+		registerHealthRoutes(v1)
+	}
+
+	handler := &api.PetHandler{}
+	r.Route("/api/v1/pets", func(r *router.Router) {
+		r.Get("/{petID:[0-9]+}", handler.GetPet)
+		r.Method("PUT", "/{petID}", http.HandlerFunc(handler.UpdatePet))
+	})
+
+	http.HandleFunc("DELETE /api/v1/pets/{petID}", handler.DeletePet)
+	http.HandleFunc("GET /api/v1/files/{path...}", api.GetFile)
+	http.Handle("/api/v1/", r)
+
+	r.GET("/api/v1/orders/:id", api.GetOrder)
+	r.GET("/api/v1/version", api.GetVersion)
+}
+
+func registerHealthRoutes(g *router.Router) {
+	g.GET("/health", api.GetVersion)
+}
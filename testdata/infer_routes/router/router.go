@@ -0,0 +1,35 @@
+package router
+
+import (
+	"net/http"
+)
+
+type Router struct {
+	prefix string
+}
+
+func New() *Router {
+	return &Router{}
+}
+
+func Logger() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {}
+}
+
+func (router *Router) Group(prefix string, middlewares ...http.HandlerFunc) *Router {
+	return &Router{prefix: router.prefix + prefix}
+}
+
+func (router *Router) Route(prefix string, fn func(r *Router)) {
+	fn(router.Group(prefix))
+}
+
+func (router *Router) GET(path string, handlers ...http.HandlerFunc) {}
+
+func (router *Router) POST(path string, handlers ...http.HandlerFunc) {}
+
+func (router *Router) Get(path string, handler http.HandlerFunc) {}
+
+func (router *Router) Method(method, path string, handler http.Handler) {}
+
+func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
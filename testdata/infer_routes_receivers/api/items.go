@@ -0,0 +1,20 @@
+package api
+
+import (
+	"net/http"
+)
+
+type Item struct {
+	ID int `json:"id"`
+}
+
+type ItemHandler struct{}
+
+var Items = &ItemHandler{}
+
+// Get
+// @Summary Get an item
+// @Param id path int true "Item ID"
+// @Success 200 {object} Item
+func (h *ItemHandler) Get(w http.ResponseWriter, r *http.Request) {
+}
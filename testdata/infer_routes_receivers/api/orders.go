@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+)
+
+type Order struct {
+	ID int `json:"id"`
+}
+
+type OrderHandler struct{}
+
+// Get
+// @Summary Get an order
+// @Param id path int true "Order ID"
+// @Success 200 {object} Order
+func (h OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserHandler struct{}
+
+func NewUserHandler() *UserHandler {
+	return &UserHandler{}
+}
+
+// Get
+// @Summary Get a user
+// @Param id path int true "User ID"
+// @Success 200 {object} User
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+}
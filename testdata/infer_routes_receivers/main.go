@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/yalochat/swag/testdata/infer_routes_receivers/api"
+	"github.com/yalochat/swag/testdata/infer_routes_receivers/router"
+)
+
+type server struct {
+	orders api.OrderHandler
+}
+
+// @title Swagger Example API
+// @version 1.0
+func main() {
+	r := router.New()
+
+	users := api.NewUserHandler()
+	r.GET("/users/:id", users.Get)
+
+	s := &server{}
+	r.GET("/orders/:id", s.orders.Get)
+
+	r.GET("/items/:id", api.Items.Get)
+}
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"net/http"
+)
+
+type Router struct{}
+
+func New() *Router {
+	return &Router{}
+}
+
+func (router *Router) GET(path string, handlers ...http.HandlerFunc) {}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"example.com/infer_schemas/web"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateUserRequest struct {
+	Name string `json:"name"`
+}
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserDetail struct {
+	User
+	Email string `json:"email"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+type Stats struct {
+	Count int `json:"count"`
+}
+
+// CreateUser
+// @Summary Create a user
+// @Success 201 {object} UserDetail
+// @Router /users [post]
+func CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &User{Name: req.Name})
+}
+
+// ListUsers
+// @Summary List the users
+// @Router /users [get]
+func ListUsers(c *gin.Context) {
+	users := []User{}
+	c.JSON(http.StatusOK, users)
+}
+
+type UserHandler struct{}
+
+// DeleteUser
+// @Summary Delete a user
+// @Param id path int true "User ID"
+// @Router /users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// GetUser
+// @Summary Get a user
+// @Param id path int true "User ID"
+// @Router /users/{id} [get]
+func GetUser(c *web.Context) {
+	var req CreateUserRequest
+	_ = c.Bind(&req)
+	c.JSON(http.StatusOK, User{Name: req.Name})
+}
+
+// PostStats
+// @Summary Post the stats
+// @Param stats body Stats true "Stats"
+// @Router /stats [post]
+func PostStats(w http.ResponseWriter, r *http.Request) {
+	var stats Stats
+	if err := json.NewDecoder(r.Body).Decode(&stats); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]Stats{"stats": stats})
+}
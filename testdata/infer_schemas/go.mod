@@ -0,0 +1,7 @@
+module example.com/infer_schemas
+
+go 1.18
+
+require github.com/gin-gonic/gin v1.9.1
+
+replace github.com/gin-gonic/gin => ../stubs/gin
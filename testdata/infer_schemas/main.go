@@ -0,0 +1,20 @@
+package main
+
+import (
+	"net/http"
+
+	"example.com/infer_schemas/api"
+	"github.com/gin-gonic/gin"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /api/v1
+func main() {
+	r := gin.New()
+	r.POST("/users", api.CreateUser)
+	r.GET("/users", api.ListUsers)
+	r.DELETE("/users/:id", (&api.UserHandler{}).DeleteUser)
+
+	http.HandleFunc("/stats", api.PostStats)
+}
@@ -0,0 +1,12 @@
+package web
+
+// Context is the context of a request of a framework which isn't gin or echo, with methods named as theirs.
+type Context struct{}
+
+// Bind decodes the request body into obj.
+func (c *Context) Bind(obj interface{}) error {
+	return nil
+}
+
+// JSON writes obj as the JSON response body with the status code.
+func (c *Context) JSON(code int, obj interface{}) {}
@@ -0,0 +1,18 @@
+package orders
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type Order struct {
+	ID int `json:"id"`
+}
+
+// GetOrder
+// @Summary Get an order
+// @Router /orders [get]
+func GetOrder(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(Order{ID: 1})
+}
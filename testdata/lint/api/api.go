@@ -0,0 +1,63 @@
+package api
+
+type Pet struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type PetCreated struct {
+	ID int `json:"id"`
+}
+
+// GetPet
+// @Summary Get a pet
+// @ID getPet
+// @Param id path int true "Pet ID"
+// @Param petId path int true "Pet ID"
+// @Success 200 {object} Pet
+// @Router /pets/{id} [get]
+func GetPet() {
+}
+
+// UpdatePet
+// @Summary Update a pet
+// @ID getPet
+// @Param id path int true "Pet ID"
+// @Summry typo
+// @Router /pets/{id} [put]
+func UpdatePet() {
+}
+
+// @asyncapi
+// @server broker mqtt mqtt://broker.hivemq.com
+// @channel pets broker "Events of the pets"
+// @channel audits auditBroker "Audit events"
+func ConfigEventDrivenChannel() {
+}
+
+// @asyncapi
+// @operation send pets PetCreated
+func OnPetCreated() {
+}
+
+// DeletePet
+// @Summary Delete a pet
+// @Param id path
+// @Success 204
+// @Router /pets/{id} [delete]
+func DeletePet() {
+}
+
+// @asyncapi
+// @operation receive pets PetCreated
+// @message.tittle Pet created
+func OnPetAudited() {
+}
+
+// GetPetPhotos
+// @Summary Get the photos of a pet
+// @Success 200 {array} string
+// @Router /pets/{petId}/photos [get]
+// @Router /pets/{petID} [get]
+func GetPetPhotos() {
+}
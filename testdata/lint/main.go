@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/yalochat/swag/testdata/lint/api"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @BasePath /v1
+func main() {
+	api.ConfigEventDrivenChannel()
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yalochat/swag/testdata/marshalers/types"
+)
+
+// Meta holds the metadata of a resource.
+type Meta struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitzero"`
+}
+
+// Audit tells who changed a resource.
+type Audit struct {
+	UpdatedBy string `json:"updatedBy"`
+}
+
+// Order is an order of a customer.
+type Order struct {
+	Meta   Meta              `json:",inline"`
+	Audit  *Audit            `json:",inline"`
+	ID     types.ULID        `json:"id"`
+	Total  types.Money       `json:"total"`
+	Amount types.Amount      `json:"amount"`
+	Due    *types.Date       `json:"due,omitempty"`
+	Status types.Status      `json:"status"`
+	Note   string            `json:"note,omitzero"`
+	Extra  map[string]string `json:"extra,inline"`
+	// Placed is the date of the order, documented as a date-time
+	Placed types.Date `json:"placed" format:"date-time"`
+}
+
+// CreateOrder godoc
+// @Summary Create an order
+// @Param order body Order true "Order"
+// @Success 201 {object} Order
+// @Router /orders [post]
+func CreateOrder(w http.ResponseWriter, r *http.Request) {
+	// write your code
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/yalochat/swag/testdata/marshalers/api"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @description This is a sample server of orders.
+// @host localhost:4000
+// @basePath /
+func main() {
+	http.HandleFunc("/orders", api.CreateOrder)
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"fmt"
+)
+
+// Date is a day of the calendar.
+//
+// @format date
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)), nil
+}
+
+// MarshalString isn't a marshaling method.
+func (d Date) MarshalString() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
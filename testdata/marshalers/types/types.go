@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Money is an amount of a currency, marshaled like "12.50 EUR".
+type Money struct {
+	Units    int64
+	Cents    int64
+	Currency string
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d.%02d %s", m.Units, m.Cents, m.Currency))
+}
+
+// ULID is a sortable unique identifier.
+//
+// @format ulid
+type ULID [16]byte
+
+// MarshalText implements encoding.TextMarshaler.
+func (id *ULID) MarshalText() (text []byte, err error) {
+	return []byte(hex.EncodeToString(id[:])), nil
+}
+
+// Amount is an amount of cents, marshaled as a string so that it isn't rounded.
+type Amount int64
+
+// MarshalJSON implements json.Marshaler.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(a), 10))
+}
+
+// Status is the status of an order, it keeps its enum values.
+type Status int
+
+const (
+	Pending Status = iota
+	Paid
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(s))), nil
+}
@@ -0,0 +1,25 @@
+package api
+
+type Pet struct {
+	ID int `json:"id"`
+}
+
+// GetPet
+// @Summary Get a pet
+// @Param id path
+// @Success 200 {object} Pet
+// @Router /pets/{id} [get]
+func GetPet() {
+}
+
+// DeletePet
+// @Summary Delete a pet
+// @Success 204 {object} Unknown
+// @Router /pets/{id} [delete]
+func DeletePet() {
+}
+
+// @asyncapi
+// @server broker
+func ConfigEventDrivenChannel() {
+}
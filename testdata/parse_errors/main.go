@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/yalochat/swag/testdata/parse_errors/api"
+)
+
+// @title Swagger Example API
+// @version 1.0
+func main() {
+	api.ConfigEventDrivenChannel()
+}
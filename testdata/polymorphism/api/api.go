@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yalochat/swag/testdata/polymorphism/payments"
+)
+
+// PaymentEvent is published when a payment is settled.
+type PaymentEvent struct {
+	ID      string           `json:"id"`
+	Payment payments.Payment `json:"payment"`
+}
+
+// GetPayment godoc
+// @Summary Get a payment
+// @Param id path string true "Payment ID"
+// @Success 200 {object} payments.Payment
+// @Router /payments/{id} [get]
+func GetPayment(w http.ResponseWriter, r *http.Request) {
+	// write your code
+}
+
+// @asyncapi
+// @server broker mqtt mqtt://localhost:1883
+// @channel payments broker "Events of the payments"
+func ConfigEventDrivenChannel() {
+	// write your code
+}
+
+// @asyncapi
+// @operation send payments PaymentEvent
+func OnPaymentSettled() {
+	// write your code
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/yalochat/swag/testdata/polymorphism/api"
+)
+
+// @title Swagger Example API
+// @version 1.0
+// @description This is a sample server of payments.
+// @host localhost:4000
+// @basePath /
+func main() {
+	http.HandleFunc("/payments/", api.GetPayment)
+	api.ConfigEventDrivenChannel()
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,11 @@
+package bank
+
+// Transfer is paid with a bank transfer.
+type Transfer struct {
+	IBAN string `json:"iban"`
+}
+
+// PaymentKind implements payments.Payment.
+func (Transfer) PaymentKind() string {
+	return "bank"
+}
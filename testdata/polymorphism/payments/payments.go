@@ -0,0 +1,39 @@
+package payments
+
+import (
+	"github.com/yalochat/swag/testdata/polymorphism/payments/bank"
+)
+
+var _ Payment = (*bank.Transfer)(nil)
+
+// Payment is a payment of an order.
+//
+// @discriminator kind
+// @implementation CardPayment card
+// @implementation bank.Transfer bank
+// @implementation Voucher
+type Payment interface {
+	PaymentKind() string
+}
+
+// CardPayment is paid with a card.
+type CardPayment struct {
+	Last4 string `json:"last4"`
+	// Fallback is tried when the card is declined
+	Fallback Payment `json:"fallback,omitempty"`
+}
+
+// PaymentKind implements Payment.
+func (CardPayment) PaymentKind() string {
+	return "card"
+}
+
+// Voucher is paid with a voucher.
+type Voucher struct {
+	Code string `json:"code"`
+}
+
+// PaymentKind implements Payment.
+func (Voucher) PaymentKind() string {
+	return "Voucher"
+}
@@ -5,15 +5,85 @@ type MyMessage struct {
 	MessageID int
 }
 
+type OrderCreated struct {
+	Type    string `json:"type" enums:"order.created"`
+	OrderID string `json:"orderId"`
+}
+
+type OrderCancelled struct {
+	Type    string `json:"type" enums:"order.cancelled"`
+	OrderID string `json:"orderId"`
+	Reason  string `json:"reason"`
+}
+
+type OrderShipped struct {
+	Type    string `json:"type" enums:"order.shipped"`
+	OrderID string `json:"orderId"`
+}
+
+type MyHeaders struct {
+	CorrelationID string `json:"correlationId"`
+	TraceID       string `json:"traceId"`
+}
+
 // @asyncapi
 // @server myServer mqtt mqtt://broker.hivemq.com
+// @server.description Public HiveMQ broker
+// @server.protocolVersion 5
+// @server.security userPassword
 // @channel myChannel myServer "Channel to hold events"
+// @channel orders myServer "Events of the orders"
+// @channel tenants/{tenantId}/orders myServer "Events of the orders of a tenant"
+// @channel.param tenantId string "Tenant identifier"
 func ConfigEventDrivenChannel() {
 	// write your code
 }
 
 // @asyncapi
 // @operation send myChannel MyMessage
+// @binding.mqtt.qos 1
+// @binding.mqtt.retain true
+// @message.name myMessage
+// @message.summary Event emitted for every received message
+// @message.contentType json
+// @message.headers MyHeaders
+// @message.correlationId $message.header#/correlationId "Correlates the message with its request"
 func OnMessageReceived() {
 	// write your code
 }
+
+// @asyncapi
+// @operation receive myChannel MyMessage
+func OnMessageForwarded() {
+	// write your code
+}
+
+// @asyncapi
+// @operation send orders OrderCreated, OrderCancelled
+func OnOrderChanged() {
+	// write your code
+}
+
+// @asyncapi
+// @operation send orders OrderShipped
+func OnOrderShipped() {
+	// write your code
+}
+
+// @asyncapi
+// @operation receive orders OrderCreated,OrderCancelled
+func OnOrderAudited() {
+	// write your code
+}
+
+// @asyncapi
+// @operation send tenants/{tenantId}/orders OrderCreated
+func OnTenantOrderCreated() {
+	// write your code
+}
+
+// @asyncapi
+// @operation receive tenants/{tenantId}/orders OrderCreated
+func OnTenantOrderAudited() {
+	// write your code
+}
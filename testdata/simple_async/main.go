@@ -7,6 +7,9 @@ import (
 // @title Swagger Example AsyncAPI
 // @version 1.0
 // @description This is a sample server Petstore server.
+//
+// @asyncapi.securitySchemes.userPassword userPassword
+// @description Broker credentials
 func main() {
 	api.ConfigEventDrivenChannel()
 }
@@ -0,0 +1,39 @@
+// Package gin is a stub of the API of github.com/gin-gonic/gin used by the handlers of the testdata.
+package gin
+
+// H is a shortcut for map[string]any.
+type H map[string]any
+
+// Context is the context of a request.
+type Context struct{}
+
+// ShouldBindJSON decodes the JSON request body into obj.
+func (c *Context) ShouldBindJSON(obj any) error {
+	return nil
+}
+
+// JSON writes obj as the JSON response body with the status code.
+func (c *Context) JSON(code int, obj any) {}
+
+// AbortWithStatus writes the status code without response body.
+func (c *Context) AbortWithStatus(code int) {}
+
+// HandlerFunc handles a request.
+type HandlerFunc func(*Context)
+
+// Engine registers the handlers.
+type Engine struct{}
+
+// New returns an engine.
+func New() *Engine {
+	return &Engine{}
+}
+
+// GET registers a handler.
+func (engine *Engine) GET(path string, handlers ...HandlerFunc) {}
+
+// POST registers a handler.
+func (engine *Engine) POST(path string, handlers ...HandlerFunc) {}
+
+// DELETE registers a handler.
+func (engine *Engine) DELETE(path string, handlers ...HandlerFunc) {}
@@ -35,6 +35,15 @@ type TypeSpecDef struct {
 	SchemaName string
 
 	NotUnique bool
+
+	// polymorphism is set on an interface type annotated with @discriminator
+	polymorphism *polymorphism
+
+	// implementation is set on a type listed by an @implementation attribute
+	implementation *implementation
+
+	// marshaler is set on a type implementing encoding.TextMarshaler or json.Marshaler
+	marshaler *marshaler
 }
 
 // Name the name of the typeSpec.